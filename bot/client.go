@@ -41,6 +41,12 @@ type Client interface {
 	// Rest returns the rest.Rest used by the Client.
 	Rest() rest.Rest
 
+	// Resolve returns the Resolver used by the Client to look up entities in the cache.Caches & fetch them from the rest.Rest on a cache miss.
+	Resolve() Resolver
+
+	// MessageHistory returns the cache.MessageHistory used by the Client or nil if none is configured.
+	MessageHistory() cache.MessageHistory
+
 	// AddEventListeners adds one or more EventListener(s) to the EventManager.
 	AddEventListeners(listeners ...EventListener)
 
@@ -130,6 +136,10 @@ type clientImpl struct {
 
 	caches cache.Caches
 
+	messageHistory cache.MessageHistory
+
+	resolver Resolver
+
 	memberChunkingManager MemberChunkingManager
 }
 
@@ -178,6 +188,14 @@ func (c *clientImpl) Rest() rest.Rest {
 	return c.restServices
 }
 
+func (c *clientImpl) Resolve() Resolver {
+	return c.resolver
+}
+
+func (c *clientImpl) MessageHistory() cache.MessageHistory {
+	return c.messageHistory
+}
+
 func (c *clientImpl) AddEventListeners(listeners ...EventListener) {
 	c.eventManager.AddEventListeners(listeners...)
 }
@@ -50,6 +50,10 @@ type Config struct {
 	Caches          cache.Caches
 	CacheConfigOpts []cache.ConfigOpt
 
+	MessageHistory cache.MessageHistory
+
+	Resolver Resolver
+
 	MemberChunkingManager MemberChunkingManager
 	MemberChunkingFilter  MemberChunkingFilter
 }
@@ -194,6 +198,20 @@ func WithCacheConfigOpts(opts ...cache.ConfigOpt) ConfigOpt {
 	}
 }
 
+// WithMessageHistory lets you keep the revisions of messages for update & delete events. Use cache.NewMessageHistory for an in-memory history.
+func WithMessageHistory(messageHistory cache.MessageHistory) ConfigOpt {
+	return func(config *Config) {
+		config.MessageHistory = messageHistory
+	}
+}
+
+// WithResolver lets you inject your own Resolver.
+func WithResolver(resolver Resolver) ConfigOpt {
+	return func(config *Config) {
+		config.Resolver = resolver
+	}
+}
+
 // WithMemberChunkingManager lets you inject your own MemberChunkingManager.
 func WithMemberChunkingManager(memberChunkingManager MemberChunkingManager) ConfigOpt {
 	return func(config *Config) {
@@ -275,22 +293,10 @@ func BuildClient(token string, cfg *Config, gatewayEventHandlerFunc func(client
 	client.gateway = cfg.Gateway
 
 	if cfg.ShardManager == nil && len(cfg.ShardManagerConfigOpts) > 0 {
-		var gatewayBotRs *discord.GatewayBot
-		gatewayBotRs, err = client.restServices.GetGatewayBot()
-		if err != nil {
-			return nil, err
-		}
-
-		shardIDs := make([]int, gatewayBotRs.Shards)
-		for i := 0; i < gatewayBotRs.Shards-1; i++ {
-			shardIDs[i] = i
-		}
-
+		// the shard count, shard IDs, gateway url & max concurrency are fetched from Discord when opening the ShardManager
 		cfg.ShardManagerConfigOpts = append([]sharding.ConfigOpt{
-			sharding.WithShardCount(gatewayBotRs.Shards),
-			sharding.WithShardIDs(shardIDs...),
+			sharding.WithRest(client.restServices),
 			sharding.WithGatewayConfigOpts(
-				gateway.WithURL(gatewayBotRs.URL),
 				gateway.WithLogger(cfg.Logger),
 				gateway.WithOS(os),
 				gateway.WithBrowser(name),
@@ -301,7 +307,7 @@ func BuildClient(token string, cfg *Config, gatewayEventHandlerFunc func(client
 			),
 			sharding.WithLogger(cfg.Logger),
 			func(config *sharding.Config) {
-				config.RateLimiterConfigOpts = append([]sharding.RateLimiterConfigOpt{sharding.WithRateLimiterLogger(cfg.Logger), sharding.WithMaxConcurrency(gatewayBotRs.SessionStartLimit.MaxConcurrency)}, config.RateLimiterConfigOpts...)
+				config.RateLimiterConfigOpts = append([]sharding.RateLimiterConfigOpt{sharding.WithRateLimiterLogger(cfg.Logger)}, config.RateLimiterConfigOpts...)
 			},
 		}, cfg.ShardManagerConfigOpts...)
 
@@ -327,6 +333,12 @@ func BuildClient(token string, cfg *Config, gatewayEventHandlerFunc func(client
 		cfg.Caches = cache.New(cfg.CacheConfigOpts...)
 	}
 	client.caches = cfg.Caches
+	client.messageHistory = cfg.MessageHistory
+
+	if cfg.Resolver == nil {
+		cfg.Resolver = NewResolver(client.caches, client.restServices)
+	}
+	client.resolver = cfg.Resolver
 
 	return client, nil
 }
@@ -22,6 +22,7 @@ func NewEventManager(client Client, opts ...EventManagerConfigOpt) EventManager
 		logger:             cfg.Logger,
 		eventListeners:     cfg.EventListeners,
 		asyncEventsEnabled: cfg.AsyncEventsEnabled,
+		callListener:       chainListenerMiddlewares(cfg.ListenerMiddlewares),
 		gatewayHandlers:    cfg.GatewayHandlers,
 		httpServerHandler:  cfg.HTTPServerHandler,
 	}
@@ -50,6 +51,13 @@ type EventListener interface {
 	OnEvent(event Event)
 }
 
+// ListenerHandler calls an EventListener with an Event.
+type ListenerHandler func(listener EventListener, event Event)
+
+// ListenerMiddleware wraps each call of an EventListener by the EventManager. The returned ListenerHandler must call next to call the EventListener.
+// It can be used to measure or trace the execution of EventListener(s).
+type ListenerMiddleware func(next ListenerHandler) ListenerHandler
+
 // NewListenerFunc returns a new EventListener for the given func(e E)
 func NewListenerFunc[E Event](f func(e E)) EventListener {
 	return &listenerFunc[E]{f: f}
@@ -125,6 +133,7 @@ type eventManagerImpl struct {
 	eventListenerMu    sync.Mutex
 	eventListeners     []EventListener
 	asyncEventsEnabled bool
+	callListener       ListenerHandler
 	gatewayHandlers    map[gateway.EventType]GatewayEventHandler
 	httpServerHandler  HTTPServerEventHandler
 }
@@ -163,12 +172,23 @@ func (e *eventManagerImpl) DispatchEvent(event Event) {
 						return
 					}
 				}()
-				e.eventListeners[i].OnEvent(event)
+				e.callListener(e.eventListeners[i], event)
 			}(i)
 			continue
 		}
-		e.eventListeners[i].OnEvent(event)
+		e.callListener(e.eventListeners[i], event)
+	}
+}
+
+// chainListenerMiddlewares returns a ListenerHandler which calls the EventListener through the ListenerMiddleware(s).
+func chainListenerMiddlewares(middlewares []ListenerMiddleware) ListenerHandler {
+	next := ListenerHandler(func(listener EventListener, event Event) {
+		listener.OnEvent(event)
+	})
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		next = middlewares[i](next)
 	}
+	return next
 }
 
 func (e *eventManagerImpl) AddEventListeners(listeners ...EventListener) {
@@ -15,9 +15,10 @@ func DefaultEventManagerConfig() *EventManagerConfig {
 
 // EventManagerConfig can be used to configure the EventManager.
 type EventManagerConfig struct {
-	Logger             *slog.Logger
-	EventListeners     []EventListener
-	AsyncEventsEnabled bool
+	Logger              *slog.Logger
+	EventListeners      []EventListener
+	AsyncEventsEnabled  bool
+	ListenerMiddlewares []ListenerMiddleware
 
 	GatewayHandlers   map[gateway.EventType]GatewayEventHandler
 	HTTPServerHandler HTTPServerEventHandler
@@ -64,6 +65,13 @@ func WithAsyncEventsEnabled() EventManagerConfigOpt {
 	}
 }
 
+// WithListenerMiddlewares adds the given ListenerMiddleware(s) to the EventManagerConfig. The first ListenerMiddleware is the outermost one.
+func WithListenerMiddlewares(middlewares ...ListenerMiddleware) EventManagerConfigOpt {
+	return func(config *EventManagerConfig) {
+		config.ListenerMiddlewares = append(config.ListenerMiddlewares, middlewares...)
+	}
+}
+
 // WithGatewayHandlers overrides the default GatewayEventHandler(s) in the EventManagerConfig.
 func WithGatewayHandlers(handlers map[gateway.EventType]GatewayEventHandler) EventManagerConfigOpt {
 	return func(config *EventManagerConfig) {
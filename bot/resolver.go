@@ -0,0 +1,221 @@
+package bot
+
+import (
+	"sync"
+
+	"github.com/disgoorg/snowflake/v2"
+
+	"github.com/disgoorg/disgo/cache"
+	"github.com/disgoorg/disgo/discord"
+	"github.com/disgoorg/disgo/rest"
+)
+
+var _ Resolver = (*resolverImpl)(nil)
+
+// NewResolver returns a new Resolver which looks up entities in the given cache.Caches & falls back to the given rest.Rest.
+func NewResolver(caches cache.Caches, restClient rest.Rest) Resolver {
+	return &resolverImpl{
+		caches: caches,
+		rest:   restClient,
+	}
+}
+
+// Resolver returns entities from the cache.Caches and fetches them from the rest.Rest on a cache miss.
+// Fetched entities are added to the cache.Caches, which only keeps them if the configured cache.Flags & cache.Policy allow it.
+// Concurrent fetches of the same entity are coalesced into a single request. The result & error of this request is shared with all callers,
+// this also includes the rest.RequestOpt(s) of the first caller like its context.
+type Resolver interface {
+	// Guild returns the discord.Guild with the given ID.
+	Guild(guildID snowflake.ID, opts ...rest.RequestOpt) (discord.Guild, error)
+
+	// Channel returns the discord.Channel with the given ID. Only discord.GuildChannel(s) are cached.
+	Channel(channelID snowflake.ID, opts ...rest.RequestOpt) (discord.Channel, error)
+
+	// Role returns the discord.Role with the given ID from the given guild.
+	Role(guildID snowflake.ID, roleID snowflake.ID, opts ...rest.RequestOpt) (discord.Role, error)
+
+	// Member returns the discord.Member of the given user from the given guild.
+	Member(guildID snowflake.ID, userID snowflake.ID, opts ...rest.RequestOpt) (discord.Member, error)
+
+	// Message returns the discord.Message with the given ID from the given channel.
+	Message(channelID snowflake.ID, messageID snowflake.ID, opts ...rest.RequestOpt) (discord.Message, error)
+
+	// Emoji returns the discord.Emoji with the given ID from the given guild.
+	Emoji(guildID snowflake.ID, emojiID snowflake.ID, opts ...rest.RequestOpt) (discord.Emoji, error)
+
+	// Sticker returns the discord.Sticker with the given ID from the given guild.
+	// It returns discord.ErrStickerNotInGuild if the sticker belongs to another guild or is a standard sticker.
+	Sticker(guildID snowflake.ID, stickerID snowflake.ID, opts ...rest.RequestOpt) (discord.Sticker, error)
+
+	// GuildScheduledEvent returns the discord.GuildScheduledEvent with the given ID from the given guild.
+	GuildScheduledEvent(guildID snowflake.ID, guildScheduledEventID snowflake.ID, opts ...rest.RequestOpt) (discord.GuildScheduledEvent, error)
+}
+
+type resolverImpl struct {
+	caches cache.Caches
+	rest   rest.Rest
+
+	guilds               flightGroup[snowflake.ID, discord.Guild]
+	channels             flightGroup[snowflake.ID, discord.Channel]
+	roles                flightGroup[[2]snowflake.ID, discord.Role]
+	members              flightGroup[[2]snowflake.ID, discord.Member]
+	messages             flightGroup[[2]snowflake.ID, discord.Message]
+	emojis               flightGroup[[2]snowflake.ID, discord.Emoji]
+	stickers             flightGroup[[2]snowflake.ID, discord.Sticker]
+	guildScheduledEvents flightGroup[[2]snowflake.ID, discord.GuildScheduledEvent]
+}
+
+func (r *resolverImpl) Guild(guildID snowflake.ID, opts ...rest.RequestOpt) (discord.Guild, error) {
+	if guild, ok := r.caches.Guild(guildID); ok {
+		return guild, nil
+	}
+	return r.guilds.do(guildID, func() (discord.Guild, error) {
+		guild, err := r.rest.GetGuild(guildID, false, opts...)
+		if err != nil {
+			return discord.Guild{}, err
+		}
+		r.caches.AddGuild(guild.Guild)
+		return guild.Guild, nil
+	})
+}
+
+func (r *resolverImpl) Channel(channelID snowflake.ID, opts ...rest.RequestOpt) (discord.Channel, error) {
+	if channel, ok := r.caches.Channel(channelID); ok {
+		return channel, nil
+	}
+	return r.channels.do(channelID, func() (discord.Channel, error) {
+		channel, err := r.rest.GetChannel(channelID, opts...)
+		if err != nil {
+			return nil, err
+		}
+		if guildChannel, ok := channel.(discord.GuildChannel); ok {
+			r.caches.AddChannel(guildChannel)
+		}
+		return channel, nil
+	})
+}
+
+func (r *resolverImpl) Role(guildID snowflake.ID, roleID snowflake.ID, opts ...rest.RequestOpt) (discord.Role, error) {
+	if role, ok := r.caches.Role(guildID, roleID); ok {
+		return role, nil
+	}
+	return r.roles.do([2]snowflake.ID{guildID, roleID}, func() (discord.Role, error) {
+		role, err := r.rest.GetRole(guildID, roleID, opts...)
+		if err != nil {
+			return discord.Role{}, err
+		}
+		r.caches.AddRole(*role)
+		return *role, nil
+	})
+}
+
+func (r *resolverImpl) Member(guildID snowflake.ID, userID snowflake.ID, opts ...rest.RequestOpt) (discord.Member, error) {
+	if member, ok := r.caches.Member(guildID, userID); ok {
+		return member, nil
+	}
+	return r.members.do([2]snowflake.ID{guildID, userID}, func() (discord.Member, error) {
+		member, err := r.rest.GetMember(guildID, userID, opts...)
+		if err != nil {
+			return discord.Member{}, err
+		}
+		r.caches.AddMember(*member)
+		return *member, nil
+	})
+}
+
+func (r *resolverImpl) Message(channelID snowflake.ID, messageID snowflake.ID, opts ...rest.RequestOpt) (discord.Message, error) {
+	if message, ok := r.caches.Message(channelID, messageID); ok {
+		return message, nil
+	}
+	return r.messages.do([2]snowflake.ID{channelID, messageID}, func() (discord.Message, error) {
+		message, err := r.rest.GetMessage(channelID, messageID, opts...)
+		if err != nil {
+			return discord.Message{}, err
+		}
+		r.caches.AddMessage(*message)
+		return *message, nil
+	})
+}
+
+func (r *resolverImpl) Emoji(guildID snowflake.ID, emojiID snowflake.ID, opts ...rest.RequestOpt) (discord.Emoji, error) {
+	if emoji, ok := r.caches.Emoji(guildID, emojiID); ok {
+		return emoji, nil
+	}
+	return r.emojis.do([2]snowflake.ID{guildID, emojiID}, func() (discord.Emoji, error) {
+		emoji, err := r.rest.GetEmoji(guildID, emojiID, opts...)
+		if err != nil {
+			return discord.Emoji{}, err
+		}
+		r.caches.AddEmoji(*emoji)
+		return *emoji, nil
+	})
+}
+
+func (r *resolverImpl) Sticker(guildID snowflake.ID, stickerID snowflake.ID, opts ...rest.RequestOpt) (discord.Sticker, error) {
+	if sticker, ok := r.caches.Sticker(guildID, stickerID); ok {
+		return sticker, nil
+	}
+	return r.stickers.do([2]snowflake.ID{guildID, stickerID}, func() (discord.Sticker, error) {
+		sticker, err := r.rest.GetSticker(stickerID, opts...)
+		if err != nil {
+			return discord.Sticker{}, err
+		}
+		if sticker.GuildID == nil || *sticker.GuildID != guildID {
+			return discord.Sticker{}, discord.ErrStickerNotInGuild
+		}
+		r.caches.AddSticker(*sticker)
+		return *sticker, nil
+	})
+}
+
+func (r *resolverImpl) GuildScheduledEvent(guildID snowflake.ID, guildScheduledEventID snowflake.ID, opts ...rest.RequestOpt) (discord.GuildScheduledEvent, error) {
+	if guildScheduledEvent, ok := r.caches.GuildScheduledEvent(guildID, guildScheduledEventID); ok {
+		return guildScheduledEvent, nil
+	}
+	return r.guildScheduledEvents.do([2]snowflake.ID{guildID, guildScheduledEventID}, func() (discord.GuildScheduledEvent, error) {
+		guildScheduledEvent, err := r.rest.GetGuildScheduledEvent(guildID, guildScheduledEventID, false, opts...)
+		if err != nil {
+			return discord.GuildScheduledEvent{}, err
+		}
+		r.caches.AddGuildScheduledEvent(*guildScheduledEvent)
+		return *guildScheduledEvent, nil
+	})
+}
+
+// flightGroup coalesces concurrent calls with the same key into a single call of fn.
+type flightGroup[K comparable, V any] struct {
+	mu      sync.Mutex
+	flights map[K]*flight[V]
+}
+
+type flight[V any] struct {
+	wg    sync.WaitGroup
+	value V
+	err   error
+}
+
+// do calls fn & returns its result or waits for the result of an already running call with the same key.
+func (g *flightGroup[K, V]) do(key K, fn func() (V, error)) (V, error) {
+	g.mu.Lock()
+	if f, ok := g.flights[key]; ok {
+		g.mu.Unlock()
+		f.wg.Wait()
+		return f.value, f.err
+	}
+	if g.flights == nil {
+		g.flights = map[K]*flight[V]{}
+	}
+	f := &flight[V]{}
+	f.wg.Add(1)
+	g.flights[key] = f
+	g.mu.Unlock()
+
+	defer func() {
+		g.mu.Lock()
+		delete(g.flights, key)
+		g.mu.Unlock()
+		f.wg.Done()
+	}()
+	f.value, f.err = fn()
+	return f.value, f.err
+}
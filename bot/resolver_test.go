@@ -0,0 +1,75 @@
+package bot
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/disgoorg/disgo/cache"
+	"github.com/disgoorg/disgo/discord"
+	"github.com/disgoorg/disgo/rest"
+)
+
+func TestResolverMember(t *testing.T) {
+	var requests atomic.Int32
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		<-release
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"user":{"id":"2","username":"test"},"roles":[],"joined_at":"2024-01-01T00:00:00Z"}`))
+	}))
+	defer server.Close()
+
+	caches := cache.New(cache.WithCaches(cache.FlagMembers))
+	resolver := NewResolver(caches, rest.New(rest.NewClient("token", rest.WithURL(server.URL))))
+
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			member, err := resolver.Member(1, 2)
+			assert.NoError(t, err)
+			assert.Equal(t, "test", member.User.Username)
+		}()
+	}
+	assert.Eventually(t, func() bool { return requests.Load() == 1 }, time.Second, time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	assert.Equal(t, int32(1), requests.Load())
+
+	member, ok := caches.Member(1, 2)
+	assert.True(t, ok)
+	assert.Equal(t, "test", member.User.Username)
+
+	_, err := resolver.Member(1, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, int32(1), requests.Load())
+}
+
+func TestResolverStickerOtherGuild(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id":"2","name":"test","type":2,"guild_id":"3"}`))
+	}))
+	defer server.Close()
+
+	caches := cache.New(cache.WithCaches(cache.FlagStickers))
+	resolver := NewResolver(caches, rest.New(rest.NewClient("token", rest.WithURL(server.URL))))
+
+	_, err := resolver.Sticker(1, 2)
+	assert.ErrorIs(t, err, discord.ErrStickerNotInGuild)
+	_, ok := caches.Sticker(3, 2)
+	assert.False(t, ok)
+
+	sticker, err := resolver.Sticker(3, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, "test", sticker.Name)
+}
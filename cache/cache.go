@@ -31,7 +31,10 @@ type Cache[T any] interface {
 	ForEach(func(entity T))
 }
 
-var _ Cache[any] = (*DefaultCache[any])(nil)
+var (
+	_ Cache[any]      = (*DefaultCache[any])(nil)
+	_ Observable[any] = (*DefaultCache[any])(nil)
+)
 
 // NewCache returns a new DefaultCache implementation which filter the entities after the gives Flags and Policy.
 // This cache implementation is thread safe and can be used in multiple goroutines without any issues.
@@ -52,12 +55,16 @@ type DefaultCache[T any] struct {
 	neededFlags Flags
 	policy      Policy[T]
 	cache       map[snowflake.ID]T
+	observers   Observers[T]
+	stats       statsCounter
 }
 
 func (c *DefaultCache[T]) Get(id snowflake.ID) (T, bool) {
 	c.mu.RLock()
-	defer c.mu.RUnlock()
 	entity, ok := c.cache[id]
+	c.mu.RUnlock()
+
+	c.stats.get(ok)
 	return entity, ok
 }
 
@@ -66,11 +73,15 @@ func (c *DefaultCache[T]) Put(id snowflake.ID, entity T) {
 		return
 	}
 	if c.policy != nil && !c.policy(entity) {
+		c.stats.policyRejections.Add(1)
 		return
 	}
+	c.stats.puts.Add(1)
 	c.mu.Lock()
 	defer c.mu.Unlock()
+	oldEntity, ok := c.cache[id]
 	c.cache[id] = entity
+	c.observers.Notify(PutChange(0, id, oldEntity, ok, entity))
 }
 
 func (c *DefaultCache[T]) Remove(id snowflake.ID) (T, bool) {
@@ -79,18 +90,28 @@ func (c *DefaultCache[T]) Remove(id snowflake.ID) (T, bool) {
 	entity, ok := c.cache[id]
 	if ok {
 		delete(c.cache, id)
+		c.stats.removes.Add(1)
+		c.observers.Notify(RemoveChange(0, id, entity))
 	}
 	return entity, ok
 }
 
 func (c *DefaultCache[T]) RemoveIf(filterFunc FilterFunc[T]) {
+	observed := c.observers.Len() > 0
+	var changes []Change[T]
+
 	c.mu.Lock()
-	defer c.mu.Unlock()
 	for id, entity := range c.cache {
 		if filterFunc(entity) {
 			delete(c.cache, id)
+			c.stats.removes.Add(1)
+			if observed {
+				changes = append(changes, RemoveChange(0, id, entity))
+			}
 		}
 	}
+	c.observers.Notify(changes...)
+	c.mu.Unlock()
 }
 
 func (c *DefaultCache[T]) Len() int {
@@ -107,3 +128,20 @@ func (c *DefaultCache[T]) ForEach(forEachFunc func(entity T)) {
 		forEachFunc(entity)
 	}
 }
+
+func (c *DefaultCache[T]) AddObserver(observer Observer[T]) func() {
+	return c.observers.Add(observer)
+}
+
+func (c *DefaultCache[T]) Stats() Stats {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	var sampler sizeSampler
+	for _, entity := range c.cache {
+		if !sampler.sample(entity) {
+			break
+		}
+	}
+	return c.stats.stats(len(c.cache), sampler.estimate(len(c.cache)))
+}
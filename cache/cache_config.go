@@ -1,6 +1,8 @@
 package cache
 
 import (
+	"time"
+
 	"github.com/disgoorg/snowflake/v2"
 
 	"github.com/disgoorg/disgo/discord"
@@ -27,6 +29,8 @@ func DefaultConfig() *Config {
 // Config lets you configure your Caches instance.
 type Config struct {
 	CacheFlags Flags
+	// IndexFlags makes the default caches of these Flags keep secondary indexes. Only FlagChannels, FlagMembers & FlagVoiceStates are supported.
+	IndexFlags Flags
 
 	SelfUserCache SelfUserCache
 
@@ -47,18 +51,24 @@ type Config struct {
 
 	MemberCache       MemberCache
 	MemberCachePolicy Policy[discord.Member]
+	// MemberCacheFields makes the default MemberCache store only these fields in a memory-compact form. All fields are stored as is if none are set.
+	MemberCacheFields MemberFields
 
 	ThreadMemberCache       ThreadMemberCache
 	ThreadMemberCachePolicy Policy[discord.ThreadMember]
 
 	PresenceCache       PresenceCache
 	PresenceCachePolicy Policy[discord.Presence]
+	// PresenceCacheFields makes the default PresenceCache store only these fields in a memory-compact form. All fields are stored as is if none are set.
+	PresenceCacheFields PresenceFields
 
 	VoiceStateCache       VoiceStateCache
 	VoiceStateCachePolicy Policy[discord.VoiceState]
 
 	MessageCache       MessageCache
 	MessageCachePolicy Policy[discord.Message]
+	// MessageCacheLimits bounds the default MessageCache. Messages are cached without limits if none are set.
+	MessageCacheLimits LRUConfig[discord.Message]
 
 	EmojiCache       EmojiCache
 	EmojiCachePolicy Policy[discord.Emoji]
@@ -82,7 +92,12 @@ func (c *Config) Apply(opts []ConfigOpt) {
 		c.GuildCache = NewGuildCache(NewCache[discord.Guild](c.CacheFlags, FlagGuilds, c.GuildCachePolicy), NewSet[snowflake.ID](), NewSet[snowflake.ID]())
 	}
 	if c.ChannelCache == nil {
-		c.ChannelCache = NewChannelCache(NewCache[discord.GuildChannel](c.CacheFlags, FlagChannels, c.ChannelCachePolicy))
+		channelCache := NewCache[discord.GuildChannel](c.CacheFlags, FlagChannels, c.ChannelCachePolicy)
+		if c.IndexFlags.Has(FlagChannels) {
+			c.ChannelCache = NewIndexedChannelCache(channelCache)
+		} else {
+			c.ChannelCache = NewChannelCache(channelCache)
+		}
 	}
 	if c.StageInstanceCache == nil {
 		c.StageInstanceCache = NewStageInstanceCache(NewGroupedCache[discord.StageInstance](c.CacheFlags, FlagStageInstances, c.StageInstanceCachePolicy))
@@ -94,19 +109,42 @@ func (c *Config) Apply(opts []ConfigOpt) {
 		c.RoleCache = NewRoleCache(NewGroupedCache[discord.Role](c.CacheFlags, FlagRoles, c.RoleCachePolicy))
 	}
 	if c.MemberCache == nil {
-		c.MemberCache = NewMemberCache(NewGroupedCache[discord.Member](c.CacheFlags, FlagMembers, c.MemberCachePolicy))
+		var memberCache GroupedCache[discord.Member]
+		if c.MemberCacheFields != 0 {
+			memberCache = newCompactMemberGroupedCache(c.CacheFlags, c.MemberCachePolicy, c.MemberCacheFields)
+		} else {
+			memberCache = NewGroupedCache[discord.Member](c.CacheFlags, FlagMembers, c.MemberCachePolicy)
+		}
+		if c.IndexFlags.Has(FlagMembers) {
+			c.MemberCache = NewIndexedMemberCache(memberCache)
+		} else {
+			c.MemberCache = NewMemberCache(memberCache)
+		}
 	}
 	if c.ThreadMemberCache == nil {
 		c.ThreadMemberCache = NewThreadMemberCache(NewGroupedCache[discord.ThreadMember](c.CacheFlags, FlagThreadMembers, c.ThreadMemberCachePolicy))
 	}
 	if c.PresenceCache == nil {
-		c.PresenceCache = NewPresenceCache(NewGroupedCache[discord.Presence](c.CacheFlags, FlagPresences, c.PresenceCachePolicy))
+		if c.PresenceCacheFields != 0 {
+			c.PresenceCache = NewCompactPresenceCache(c.CacheFlags, c.PresenceCachePolicy, c.PresenceCacheFields)
+		} else {
+			c.PresenceCache = NewPresenceCache(NewGroupedCache[discord.Presence](c.CacheFlags, FlagPresences, c.PresenceCachePolicy))
+		}
 	}
 	if c.VoiceStateCache == nil {
-		c.VoiceStateCache = NewVoiceStateCache(NewGroupedCache[discord.VoiceState](c.CacheFlags, FlagVoiceStates, c.VoiceStateCachePolicy))
+		voiceStateCache := NewGroupedCache[discord.VoiceState](c.CacheFlags, FlagVoiceStates, c.VoiceStateCachePolicy)
+		if c.IndexFlags.Has(FlagVoiceStates) {
+			c.VoiceStateCache = NewIndexedVoiceStateCache(voiceStateCache)
+		} else {
+			c.VoiceStateCache = NewVoiceStateCache(voiceStateCache)
+		}
 	}
 	if c.MessageCache == nil {
-		c.MessageCache = NewMessageCache(NewGroupedCache[discord.Message](c.CacheFlags, FlagMessages, c.MessageCachePolicy))
+		if c.MessageCacheLimits.MaxPerGroup > 0 || c.MessageCacheLimits.MaxTotal > 0 || c.MessageCacheLimits.MaxAge > 0 {
+			c.MessageCache = NewMessageCache(NewLRUGroupedCache[discord.Message](c.CacheFlags, FlagMessages, c.MessageCachePolicy, c.MessageCacheLimits))
+		} else {
+			c.MessageCache = NewMessageCache(NewGroupedCache[discord.Message](c.CacheFlags, FlagMessages, c.MessageCachePolicy))
+		}
 	}
 	if c.EmojiCache == nil {
 		c.EmojiCache = NewEmojiCache(NewGroupedCache[discord.Emoji](c.CacheFlags, FlagEmojis, c.EmojiCachePolicy))
@@ -123,6 +161,21 @@ func WithCaches(flags ...Flags) ConfigOpt {
 	}
 }
 
+// WithIndexes makes the default caches of the given Flags keep secondary indexes, which speed up lookups like Caches.GuildThreadsInChannel or Caches.AudioChannelMembers.
+// Only FlagChannels, FlagMembers & FlagVoiceStates are supported. See NewIndexedChannelCache, NewIndexedMemberCache & NewIndexedVoiceStateCache.
+func WithIndexes(flags ...Flags) ConfigOpt {
+	return func(config *Config) {
+		config.IndexFlags = config.IndexFlags.Add(flags...)
+	}
+}
+
+// WithSelfUserCache sets the SelfUserCache of the Config.
+func WithSelfUserCache(selfUserCache SelfUserCache) ConfigOpt {
+	return func(config *Config) {
+		config.SelfUserCache = selfUserCache
+	}
+}
+
 // WithGuildCachePolicy sets the Policy[discord.Guild] of the Config.
 func WithGuildCachePolicy(policy Policy[discord.Guild]) ConfigOpt {
 	return func(config *Config) {
@@ -200,6 +253,14 @@ func WithMemberCachePolicy(policy Policy[discord.Member]) ConfigOpt {
 	}
 }
 
+// WithCompactMemberCache makes the default MemberCache store only the given MemberFields in a memory-compact form.
+// See NewCompactMemberCache for details.
+func WithCompactMemberCache(fields MemberFields) ConfigOpt {
+	return func(config *Config) {
+		config.MemberCacheFields = fields
+	}
+}
+
 // WithMemberCache sets the MemberCache of the Config.
 func WithMemberCache(memberCache MemberCache) ConfigOpt {
 	return func(config *Config) {
@@ -228,6 +289,14 @@ func WithPresenceCachePolicy(policy Policy[discord.Presence]) ConfigOpt {
 	}
 }
 
+// WithCompactPresenceCache makes the default PresenceCache store only the given PresenceFields in a memory-compact form.
+// See NewCompactPresenceCache for details.
+func WithCompactPresenceCache(fields PresenceFields) ConfigOpt {
+	return func(config *Config) {
+		config.PresenceCacheFields = fields
+	}
+}
+
 // WithPresenceCache sets the PresenceCache of the Config.
 func WithPresenceCache(presenceCache PresenceCache) ConfigOpt {
 	return func(config *Config) {
@@ -256,6 +325,37 @@ func WithMessageCachePolicy(policy Policy[discord.Message]) ConfigOpt {
 	}
 }
 
+// WithMessageCacheMaxPerChannel sets the max number of messages the default MessageCache keeps per channel.
+// The least recently used messages are evicted first.
+func WithMessageCacheMaxPerChannel(maxPerChannel int) ConfigOpt {
+	return func(config *Config) {
+		config.MessageCacheLimits.MaxPerGroup = maxPerChannel
+	}
+}
+
+// WithMessageCacheMaxTotal sets the max number of messages the default MessageCache keeps across all channels.
+// The least recently used messages are evicted first.
+func WithMessageCacheMaxTotal(maxTotal int) ConfigOpt {
+	return func(config *Config) {
+		config.MessageCacheLimits.MaxTotal = maxTotal
+	}
+}
+
+// WithMessageCacheMaxAge sets how long the default MessageCache keeps a message after it was last added or updated.
+func WithMessageCacheMaxAge(maxAge time.Duration) ConfigOpt {
+	return func(config *Config) {
+		config.MessageCacheLimits.MaxAge = maxAge
+	}
+}
+
+// WithMessageCacheEvictionFunc sets the EvictionFunc called with each message evicted from the default MessageCache.
+// It can be used to still emit events with the old content of evicted messages.
+func WithMessageCacheEvictionFunc(evictionFunc EvictionFunc[discord.Message]) ConfigOpt {
+	return func(config *Config) {
+		config.MessageCacheLimits.EvictionFunc = evictionFunc
+	}
+}
+
 // WithMessageCache sets the MessageCache of the Config.
 func WithMessageCache(messageCache MessageCache) ConfigOpt {
 	return func(config *Config) {
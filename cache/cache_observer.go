@@ -0,0 +1,106 @@
+package cache
+
+import (
+	"sync"
+
+	"github.com/disgoorg/snowflake/v2"
+)
+
+// Change describes a single mutation of a Cache or GroupedCache.
+type Change[T any] struct {
+	// GroupID is the group of the entity. It is always 0 for a Cache.
+	GroupID snowflake.ID
+	// ID is the ID of the entity.
+	ID snowflake.ID
+	// Old is the previously cached entity or nil if it was not cached.
+	Old *T
+	// New is the newly cached entity or nil if it was removed.
+	New *T
+}
+
+// Observer is called with each Change of a Cache or GroupedCache.
+// Observers are called synchronously while the cache is still locked, so they receive the changes in the order they were applied.
+// They must return quickly and must not access the cache they observe.
+type Observer[T any] func(change Change[T])
+
+// Observable is implemented by a Cache or GroupedCache which notifies Observer(s) about its changes.
+// All caches of this package implement it, use a type assertion to check whether other caches do.
+type Observable[T any] interface {
+	// AddObserver registers an Observer which is called with each change of the cache and returns a function which removes it again.
+	AddObserver(observer Observer[T]) func()
+}
+
+// Observers is a thread safe list of Observer(s) which can be used to implement Observable.
+// Notify should be called while holding the lock of the cache, so the changes are delivered in write order.
+// The zero value is ready to use.
+type Observers[T any] struct {
+	mu        sync.RWMutex
+	nextID    int
+	observers map[int]Observer[T]
+}
+
+// Add adds the given Observer and returns a function which removes it again.
+func (o *Observers[T]) Add(observer Observer[T]) func() {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	if o.observers == nil {
+		o.observers = map[int]Observer[T]{}
+	}
+	id := o.nextID
+	o.nextID++
+	o.observers[id] = observer
+	return func() {
+		o.mu.Lock()
+		defer o.mu.Unlock()
+		delete(o.observers, id)
+	}
+}
+
+// Len returns the number of registered observers. Implementations can use it to skip collecting changes nobody observes.
+func (o *Observers[T]) Len() int {
+	o.mu.RLock()
+	defer o.mu.RUnlock()
+	return len(o.observers)
+}
+
+// Notify calls all registered observers with the given changes.
+func (o *Observers[T]) Notify(changes ...Change[T]) {
+	if len(changes) == 0 {
+		return
+	}
+	o.mu.RLock()
+	observers := make([]Observer[T], 0, len(o.observers))
+	for _, observer := range o.observers {
+		observers = append(observers, observer)
+	}
+	o.mu.RUnlock()
+
+	for _, change := range changes {
+		for _, observer := range observers {
+			observer(change)
+		}
+	}
+}
+
+// PutChange returns a Change for an entity put into a cache.
+func PutChange[T any](groupID snowflake.ID, id snowflake.ID, oldEntity T, oldOK bool, newEntity T) Change[T] {
+	change := Change[T]{
+		GroupID: groupID,
+		ID:      id,
+		New:     &newEntity,
+	}
+	if oldOK {
+		change.Old = &oldEntity
+	}
+	return change
+}
+
+// RemoveChange returns a Change for an entity removed from a cache.
+func RemoveChange[T any](groupID snowflake.ID, id snowflake.ID, oldEntity T) Change[T] {
+	return Change[T]{
+		GroupID: groupID,
+		ID:      id,
+		Old:     &oldEntity,
+	}
+}
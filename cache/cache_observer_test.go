@@ -0,0 +1,55 @@
+package cache
+
+import (
+	"testing"
+
+	"github.com/disgoorg/snowflake/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func ptr[T any](v T) *T {
+	return &v
+}
+
+func TestCacheObserver(t *testing.T) {
+	c := NewCache[string](FlagsAll, FlagsNone, nil)
+	var changes []Change[string]
+	remove := c.(Observable[string]).AddObserver(func(change Change[string]) {
+		changes = append(changes, change)
+	})
+
+	c.Put(1, "a")
+	c.Put(1, "b")
+	c.Remove(1)
+	c.Remove(1)
+	remove()
+	c.Put(2, "c")
+
+	assert.Equal(t, []Change[string]{
+		{ID: 1, New: ptr("a")},
+		{ID: 1, Old: ptr("a"), New: ptr("b")},
+		{ID: 1, Old: ptr("b")},
+	}, changes)
+}
+
+func TestGroupedCacheObserver(t *testing.T) {
+	c := NewGroupedCache[string](FlagsAll, FlagsNone, nil)
+	c.Put(1, 10, "a")
+	c.Put(1, 11, "b")
+	c.Put(2, 20, "c")
+
+	var changes []Change[string]
+	c.(Observable[string]).AddObserver(func(change Change[string]) {
+		changes = append(changes, change)
+	})
+
+	c.GroupRemoveIf(1, func(_ snowflake.ID, entity string) bool {
+		return entity == "a"
+	})
+	c.GroupRemove(2)
+
+	assert.Equal(t, []Change[string]{
+		{GroupID: 1, ID: 10, Old: ptr("a")},
+		{GroupID: 2, ID: 20, Old: ptr("c")},
+	}, changes)
+}
@@ -0,0 +1,175 @@
+package cache
+
+import (
+	"reflect"
+	"sync/atomic"
+	"unsafe"
+)
+
+// Stats are the usage statistics of a single cache.
+type Stats struct {
+	// Entities is the number of currently cached entities.
+	Entities int
+	// Hits is the number of lookups which found an entity.
+	Hits uint64
+	// Misses is the number of lookups which did not find an entity.
+	Misses uint64
+	// Puts is the number of entities put into the cache.
+	Puts uint64
+	// Removes is the number of entities removed from the cache. This does not include evictions.
+	Removes uint64
+	// PolicyRejections is the number of entities not cached because of the Policy. Entities of disabled caches are not counted.
+	PolicyRejections uint64
+	// Evictions is the number of entities evicted by the cache itself.
+	Evictions uint64
+	// EstimatedBytes is the estimated heap size of the cached entities. It is extrapolated from a sample of the entities.
+	EstimatedBytes int64
+}
+
+// StatsProvider is implemented by caches which collect Stats.
+// All caches of this package implement it. For other caches only Stats.Entities is reported.
+type StatsProvider interface {
+	// Stats returns the current Stats of the cache.
+	Stats() Stats
+}
+
+// CachesStatsProvider is implemented by Caches which collect the Stats of their caches.
+// The Caches returned by New implement it.
+type CachesStatsProvider interface {
+	// Stats returns the Stats of each cache keyed by its name, e.g. "guilds" or "members".
+	// Caches not implementing StatsProvider only report their number of entities.
+	Stats() map[string]Stats
+}
+
+var _ CachesStatsProvider = (*cachesImpl)(nil)
+
+// statsSampleSize is the number of entities sampled to estimate the size of a cache.
+const statsSampleSize = 64
+
+// mapEntryOverhead is the approximate overhead of a single map entry in bytes.
+const mapEntryOverhead = 16
+
+type statsCounter struct {
+	hits             atomic.Uint64
+	misses           atomic.Uint64
+	puts             atomic.Uint64
+	removes          atomic.Uint64
+	policyRejections atomic.Uint64
+	evictions        atomic.Uint64
+}
+
+func (s *statsCounter) get(ok bool) {
+	if ok {
+		s.hits.Add(1)
+	} else {
+		s.misses.Add(1)
+	}
+}
+
+func (s *statsCounter) stats(entities int, estimatedBytes int64) Stats {
+	return Stats{
+		Entities:         entities,
+		Hits:             s.hits.Load(),
+		Misses:           s.misses.Load(),
+		Puts:             s.puts.Load(),
+		Removes:          s.removes.Load(),
+		PolicyRejections: s.policyRejections.Load(),
+		Evictions:        s.evictions.Load(),
+		EstimatedBytes:   estimatedBytes,
+	}
+}
+
+// sizeSampler estimates the size of all entities of a cache from a sample of them.
+type sizeSampler struct {
+	samples int
+	bytes   int64
+}
+
+// sample adds the size of the given entity and returns false once enough entities were sampled.
+func (s *sizeSampler) sample(entity any) bool {
+	s.bytes += sizeOf(reflect.ValueOf(entity)) + mapEntryOverhead
+	s.samples++
+	return s.samples < statsSampleSize
+}
+
+func (s *sizeSampler) estimate(entities int) int64 {
+	if s.samples == 0 {
+		return 0
+	}
+	return s.bytes / int64(s.samples) * int64(entities)
+}
+
+// sizeOf returns the approximate number of bytes the given value & everything it references occupies.
+func sizeOf(v reflect.Value) int64 {
+	if !v.IsValid() {
+		return 0
+	}
+	return int64(v.Type().Size()) + referencedSizeOf(v)
+}
+
+// referencedSizeOf returns the approximate number of bytes referenced by the given value, excluding the value itself.
+func referencedSizeOf(v reflect.Value) int64 {
+	switch v.Kind() {
+	case reflect.Pointer:
+		if v.IsNil() {
+			return 0
+		}
+		return sizeOf(v.Elem())
+	case reflect.Interface:
+		if v.IsNil() {
+			return 0
+		}
+		return sizeOf(v.Elem())
+	case reflect.String:
+		return int64(v.Len())
+	case reflect.Slice:
+		if v.IsNil() {
+			return 0
+		}
+		size := int64(v.Cap()) * int64(v.Type().Elem().Size())
+		for i := 0; i < v.Len(); i++ {
+			size += referencedSizeOf(v.Index(i))
+		}
+		return size
+	case reflect.Map:
+		if v.IsNil() {
+			return 0
+		}
+		size := int64(unsafe.Sizeof(uintptr(0)))
+		iter := v.MapRange()
+		for iter.Next() {
+			size += sizeOf(iter.Key()) + sizeOf(iter.Value()) + mapEntryOverhead
+		}
+		return size
+	case reflect.Struct:
+		var size int64
+		for i := 0; i < v.NumField(); i++ {
+			size += referencedSizeOf(v.Field(i))
+		}
+		return size
+	case reflect.Array:
+		var size int64
+		for i := 0; i < v.Len(); i++ {
+			size += referencedSizeOf(v.Index(i))
+		}
+		return size
+	default:
+		return 0
+	}
+}
+
+// cacheStats returns the Stats of the given cache if it implements StatsProvider, otherwise only the number of entities.
+func cacheStats(cache interface{ Len() int }) Stats {
+	if provider, ok := cache.(StatsProvider); ok {
+		return provider.Stats()
+	}
+	return Stats{Entities: cache.Len()}
+}
+
+// subCacheStats returns the Stats of the given sub cache if it implements StatsProvider, otherwise only the number of entities returned by lenFunc.
+func subCacheStats(cache any, lenFunc func() int) Stats {
+	if provider, ok := cache.(StatsProvider); ok {
+		return provider.Stats()
+	}
+	return Stats{Entities: lenFunc()}
+}
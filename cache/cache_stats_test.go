@@ -0,0 +1,66 @@
+package cache
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/disgoorg/disgo/discord"
+)
+
+func TestGroupedCacheStats(t *testing.T) {
+	c := NewGroupedCache[string](FlagsAll, FlagsNone, func(entity string) bool {
+		return entity != ""
+	})
+
+	c.Put(1, 1, "a")
+	c.Put(1, 2, "b")
+	c.Put(2, 1, "")
+	c.Get(1, 1)
+	c.Get(1, 3)
+	c.Remove(1, 2)
+
+	stats := cacheStats(c)
+	assert.Equal(t, 1, stats.Entities)
+	assert.Equal(t, uint64(1), stats.Hits)
+	assert.Equal(t, uint64(1), stats.Misses)
+	assert.Equal(t, uint64(2), stats.Puts)
+	assert.Equal(t, uint64(1), stats.Removes)
+	assert.Equal(t, uint64(1), stats.PolicyRejections)
+	assert.Positive(t, stats.EstimatedBytes)
+}
+
+func TestGroupedCacheStatsMissingFlags(t *testing.T) {
+	c := NewGroupedCache[string](FlagsNone, FlagRoles, nil)
+	c.Put(1, 1, "a")
+
+	stats := cacheStats(c)
+	assert.Equal(t, 0, stats.Entities)
+	assert.Equal(t, uint64(0), stats.Puts)
+	assert.Equal(t, uint64(0), stats.PolicyRejections)
+}
+
+func TestLRUGroupedCacheStatsEvictions(t *testing.T) {
+	c := NewLRUGroupedCache[string](FlagsAll, FlagsNone, nil, LRUConfig[string]{MaxTotal: 1})
+
+	c.Put(1, 1, "a")
+	c.Put(1, 2, "b")
+
+	stats := cacheStats(c)
+	assert.Equal(t, 1, stats.Entities)
+	assert.Equal(t, uint64(2), stats.Puts)
+	assert.Equal(t, uint64(1), stats.Evictions)
+	assert.Equal(t, uint64(0), stats.Removes)
+}
+
+func TestCachesStatsFallback(t *testing.T) {
+	// hides the Stats method of the default RoleCache
+	roleCache := struct{ RoleCache }{NewRoleCache(NewGroupedCache[discord.Role](FlagsAll, FlagRoles, nil))}
+	caches := New(WithCaches(FlagsAll), WithRoleCache(roleCache))
+	caches.AddRole(discord.Role{ID: 1, GuildID: 2})
+	caches.Role(2, 1)
+
+	stats := caches.(CachesStatsProvider).Stats()
+	assert.Len(t, stats, 12)
+	assert.Equal(t, Stats{Entities: 1}, stats["roles"])
+}
@@ -0,0 +1,55 @@
+package cachebolt
+
+import (
+	"log/slog"
+)
+
+// DefaultConfig returns a Config with sensible defaults.
+func DefaultConfig() *Config {
+	return &Config{
+		Logger:       slog.Default(),
+		Bucket:       "disgo_message_history",
+		MaxRevisions: 10,
+	}
+}
+
+// Config lets you configure the bolt backed MessageHistory.
+type Config struct {
+	// Logger is used to log bolt errors as the cache.MessageHistory interface can't return them. Defaults to slog.Default().
+	Logger *slog.Logger
+	// Bucket is the name of the bolt bucket the revisions are stored in. Defaults to "disgo_message_history".
+	Bucket string
+	// MaxRevisions is the maximum number of revisions kept per message. Zero means no limit. Defaults to 10.
+	MaxRevisions int
+}
+
+// ConfigOpt can be used to supply optional parameters to NewMessageHistory.
+type ConfigOpt func(config *Config)
+
+// Apply applies the given ConfigOpt(s) to the Config.
+func (c *Config) Apply(opts []ConfigOpt) {
+	for _, opt := range opts {
+		opt(c)
+	}
+}
+
+// WithLogger applies a custom logger to the MessageHistory.
+func WithLogger(logger *slog.Logger) ConfigOpt {
+	return func(config *Config) {
+		config.Logger = logger
+	}
+}
+
+// WithBucket sets the name of the bolt bucket the revisions are stored in.
+func WithBucket(bucket string) ConfigOpt {
+	return func(config *Config) {
+		config.Bucket = bucket
+	}
+}
+
+// WithMaxRevisions sets the maximum number of revisions kept per message.
+func WithMaxRevisions(maxRevisions int) ConfigOpt {
+	return func(config *Config) {
+		config.MaxRevisions = maxRevisions
+	}
+}
@@ -0,0 +1,20 @@
+module github.com/disgoorg/disgo/cache/cachebolt
+
+go 1.21
+
+replace github.com/disgoorg/disgo => ../../
+
+require (
+	github.com/disgoorg/disgo v0.18.8
+	github.com/disgoorg/json v1.1.0
+	github.com/disgoorg/snowflake/v2 v2.0.3
+	github.com/stretchr/testify v1.9.0
+	go.etcd.io/bbolt v1.3.10
+)
+
+require (
+	github.com/davecgh/go-spew v1.1.1 // indirect
+	github.com/pmezard/go-difflib v1.0.0 // indirect
+	golang.org/x/sys v0.22.0 // indirect
+	gopkg.in/yaml.v3 v3.0.1 // indirect
+)
@@ -0,0 +1,155 @@
+// Package cachebolt provides a cache.MessageHistory which persists message revisions in an embedded bolt database.
+// This keeps the revisions across restarts without an external service:
+//
+//	db, err := bbolt.Open("history.db", 0600, nil)
+//	history, err := cachebolt.NewMessageHistory(db)
+//	client, err := disgo.New(token, bot.WithMessageHistory(history))
+//
+// Revisions are only removed when their message is deleted, so Prune should be called regularly.
+package cachebolt
+
+import (
+	"encoding/binary"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/disgoorg/json"
+	"github.com/disgoorg/snowflake/v2"
+	"go.etcd.io/bbolt"
+
+	"github.com/disgoorg/disgo/cache"
+	"github.com/disgoorg/disgo/discord"
+)
+
+// MessageHistory is a cache.MessageHistory backed by a bolt database.
+type MessageHistory interface {
+	cache.MessageHistory
+
+	// Prune removes the revisions of all messages created before the given time.
+	Prune(before time.Time) error
+}
+
+var _ MessageHistory = (*messageHistoryImpl)(nil)
+
+// NewMessageHistory returns a new MessageHistory storing its revisions in the given bolt database.
+// The configured bucket is created if it does not exist yet.
+func NewMessageHistory(db *bbolt.DB, opts ...ConfigOpt) (MessageHistory, error) {
+	config := DefaultConfig()
+	config.Apply(opts)
+	config.Logger = config.Logger.With(slog.String("name", "cache_bolt_message_history"))
+
+	if err := db.Update(func(tx *bbolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists([]byte(config.Bucket))
+		return err
+	}); err != nil {
+		return nil, fmt.Errorf("failed to create bucket: %w", err)
+	}
+
+	return &messageHistoryImpl{
+		config: *config,
+		db:     db,
+	}, nil
+}
+
+type messageHistoryImpl struct {
+	config Config
+	db     *bbolt.DB
+}
+
+func (h *messageHistoryImpl) AddRevision(message discord.Message) []discord.Message {
+	var revisions []discord.Message
+	if err := h.db.Update(func(tx *bbolt.Tx) error {
+		bucket := tx.Bucket([]byte(h.config.Bucket))
+		key := messageKey(message.ChannelID, message.ID)
+
+		var err error
+		if revisions, err = decodeRevisions(bucket.Get(key)); err != nil {
+			return err
+		}
+		revisions = append(revisions, message)
+		if h.config.MaxRevisions > 0 && len(revisions) > h.config.MaxRevisions {
+			revisions = revisions[len(revisions)-h.config.MaxRevisions:]
+		}
+
+		data, err := json.Marshal(revisions)
+		if err != nil {
+			return err
+		}
+		return bucket.Put(key, data)
+	}); err != nil {
+		h.config.Logger.Error("failed to add message revision", slog.Any("err", err), slog.String("channel_id", message.ChannelID.String()), slog.String("message_id", message.ID.String()))
+		return []discord.Message{message}
+	}
+	return revisions
+}
+
+func (h *messageHistoryImpl) Revisions(channelID snowflake.ID, messageID snowflake.ID) []discord.Message {
+	var revisions []discord.Message
+	if err := h.db.View(func(tx *bbolt.Tx) error {
+		var err error
+		revisions, err = decodeRevisions(tx.Bucket([]byte(h.config.Bucket)).Get(messageKey(channelID, messageID)))
+		return err
+	}); err != nil {
+		h.config.Logger.Error("failed to get message revisions", slog.Any("err", err), slog.String("channel_id", channelID.String()), slog.String("message_id", messageID.String()))
+		return nil
+	}
+	return revisions
+}
+
+func (h *messageHistoryImpl) RemoveRevisions(channelID snowflake.ID, messageID snowflake.ID) []discord.Message {
+	var revisions []discord.Message
+	if err := h.db.Update(func(tx *bbolt.Tx) error {
+		bucket := tx.Bucket([]byte(h.config.Bucket))
+		key := messageKey(channelID, messageID)
+
+		var err error
+		if revisions, err = decodeRevisions(bucket.Get(key)); err != nil {
+			return err
+		}
+		return bucket.Delete(key)
+	}); err != nil {
+		h.config.Logger.Error("failed to remove message revisions", slog.Any("err", err), slog.String("channel_id", channelID.String()), slog.String("message_id", messageID.String()))
+	}
+	return revisions
+}
+
+func (h *messageHistoryImpl) Prune(before time.Time) error {
+	return h.db.Update(func(tx *bbolt.Tx) error {
+		bucket := tx.Bucket([]byte(h.config.Bucket))
+
+		// keys can't be deleted while iterating, so collect them first
+		var keys [][]byte
+		if err := bucket.ForEach(func(key []byte, _ []byte) error {
+			if snowflake.ID(binary.BigEndian.Uint64(key[8:])).Time().Before(before) {
+				keys = append(keys, append([]byte(nil), key...))
+			}
+			return nil
+		}); err != nil {
+			return err
+		}
+		for _, key := range keys {
+			if err := bucket.Delete(key); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// messageKey returns the bolt key of a message. Keys are ordered by channel & then by message creation time.
+func messageKey(channelID snowflake.ID, messageID snowflake.ID) []byte {
+	key := make([]byte, 16)
+	binary.BigEndian.PutUint64(key, uint64(channelID))
+	binary.BigEndian.PutUint64(key[8:], uint64(messageID))
+	return key
+}
+
+func decodeRevisions(data []byte) ([]discord.Message, error) {
+	if data == nil {
+		return nil, nil
+	}
+	var revisions []discord.Message
+	err := json.Unmarshal(data, &revisions)
+	return revisions, err
+}
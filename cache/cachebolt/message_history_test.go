@@ -0,0 +1,47 @@
+package cachebolt
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/disgoorg/snowflake/v2"
+	"github.com/stretchr/testify/assert"
+	"go.etcd.io/bbolt"
+
+	"github.com/disgoorg/disgo/discord"
+)
+
+func TestMessageHistory(t *testing.T) {
+	db, err := bbolt.Open(filepath.Join(t.TempDir(), "history.db"), 0600, nil)
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer db.Close()
+
+	history, err := NewMessageHistory(db, WithMaxRevisions(2))
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	oldID := snowflake.New(time.Now().Add(-time.Hour))
+	newID := snowflake.New(time.Now())
+
+	history.AddRevision(discord.Message{ID: oldID, ChannelID: 1, Content: "a"})
+	history.AddRevision(discord.Message{ID: oldID, ChannelID: 1, Content: "b"})
+	revisions := history.AddRevision(discord.Message{ID: oldID, ChannelID: 1, Content: "c"})
+	if assert.Len(t, revisions, 2) {
+		assert.Equal(t, "b", revisions[0].Content)
+		assert.Equal(t, "c", revisions[1].Content)
+	}
+
+	history.AddRevision(discord.Message{ID: newID, ChannelID: 1, Content: "d"})
+	assert.NoError(t, history.Prune(time.Now().Add(-time.Minute)))
+	assert.Nil(t, history.Revisions(1, oldID))
+
+	revisions = history.RemoveRevisions(1, newID)
+	if assert.Len(t, revisions, 1) {
+		assert.Equal(t, "d", revisions[0].Content)
+	}
+	assert.Nil(t, history.Revisions(1, newID))
+}
@@ -0,0 +1,81 @@
+// Package cacheprometheus provides a prometheus.Collector which exports the cache.Stats of all caches of a cache.Caches.
+//
+//	prometheus.MustRegister(cacheprometheus.NewCollector(client.Caches()))
+//
+// All metrics have a "cache" label with the name of the cache as returned by cache.CachesStatsProvider, e.g. "members".
+// cache.Caches which don't implement cache.CachesStatsProvider report no metrics.
+package cacheprometheus
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/disgoorg/disgo/cache"
+)
+
+var _ prometheus.Collector = (*Collector)(nil)
+
+// NewCollector returns a new Collector for the given cache.Caches with the given ConfigOpt(s) applied.
+func NewCollector(caches cache.Caches, opts ...ConfigOpt) *Collector {
+	config := DefaultConfig()
+	config.Apply(opts)
+
+	desc := func(name string, help string) *prometheus.Desc {
+		return prometheus.NewDesc(prometheus.BuildFQName(config.Namespace, config.Subsystem, name), help, []string{"cache"}, config.ConstLabels)
+	}
+
+	return &Collector{
+		caches:           caches,
+		entities:         desc("entities", "Number of currently cached entities."),
+		hits:             desc("hits_total", "Number of lookups which found an entity."),
+		misses:           desc("misses_total", "Number of lookups which did not find an entity."),
+		puts:             desc("puts_total", "Number of entities put into the cache."),
+		removes:          desc("removes_total", "Number of entities removed from the cache."),
+		policyRejections: desc("policy_rejections_total", "Number of entities not cached because of the cache policy."),
+		evictions:        desc("evictions_total", "Number of entities evicted by the cache."),
+		estimatedBytes:   desc("estimated_bytes", "Estimated heap size of the cached entities in bytes."),
+	}
+}
+
+// Collector is a prometheus.Collector which collects the cache.Stats of a cache.Caches on each scrape.
+type Collector struct {
+	caches cache.Caches
+
+	entities         *prometheus.Desc
+	hits             *prometheus.Desc
+	misses           *prometheus.Desc
+	puts             *prometheus.Desc
+	removes          *prometheus.Desc
+	policyRejections *prometheus.Desc
+	evictions        *prometheus.Desc
+	estimatedBytes   *prometheus.Desc
+}
+
+// Describe implements prometheus.Collector.
+func (c *Collector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.entities
+	ch <- c.hits
+	ch <- c.misses
+	ch <- c.puts
+	ch <- c.removes
+	ch <- c.policyRejections
+	ch <- c.evictions
+	ch <- c.estimatedBytes
+}
+
+// Collect implements prometheus.Collector.
+func (c *Collector) Collect(ch chan<- prometheus.Metric) {
+	provider, ok := c.caches.(cache.CachesStatsProvider)
+	if !ok {
+		return
+	}
+	for name, stats := range provider.Stats() {
+		ch <- prometheus.MustNewConstMetric(c.entities, prometheus.GaugeValue, float64(stats.Entities), name)
+		ch <- prometheus.MustNewConstMetric(c.hits, prometheus.CounterValue, float64(stats.Hits), name)
+		ch <- prometheus.MustNewConstMetric(c.misses, prometheus.CounterValue, float64(stats.Misses), name)
+		ch <- prometheus.MustNewConstMetric(c.puts, prometheus.CounterValue, float64(stats.Puts), name)
+		ch <- prometheus.MustNewConstMetric(c.removes, prometheus.CounterValue, float64(stats.Removes), name)
+		ch <- prometheus.MustNewConstMetric(c.policyRejections, prometheus.CounterValue, float64(stats.PolicyRejections), name)
+		ch <- prometheus.MustNewConstMetric(c.evictions, prometheus.CounterValue, float64(stats.Evictions), name)
+		ch <- prometheus.MustNewConstMetric(c.estimatedBytes, prometheus.GaugeValue, float64(stats.EstimatedBytes), name)
+	}
+}
@@ -0,0 +1,54 @@
+package cacheprometheus
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/disgoorg/disgo/cache"
+	"github.com/disgoorg/disgo/discord"
+)
+
+func TestCollector(t *testing.T) {
+	caches := cache.New(cache.WithCaches(cache.FlagRoles))
+	caches.AddRole(discord.Role{ID: 1, GuildID: 2})
+	caches.Role(2, 1)
+	caches.Role(2, 3)
+
+	collector := NewCollector(caches)
+
+	err := testutil.CollectAndCompare(collector, strings.NewReader(`
+# HELP disgo_cache_hits_total Number of lookups which found an entity.
+# TYPE disgo_cache_hits_total counter
+disgo_cache_hits_total{cache="channels"} 0
+disgo_cache_hits_total{cache="emojis"} 0
+disgo_cache_hits_total{cache="guild_scheduled_events"} 0
+disgo_cache_hits_total{cache="guilds"} 0
+disgo_cache_hits_total{cache="members"} 0
+disgo_cache_hits_total{cache="messages"} 0
+disgo_cache_hits_total{cache="presences"} 0
+disgo_cache_hits_total{cache="roles"} 1
+disgo_cache_hits_total{cache="stage_instances"} 0
+disgo_cache_hits_total{cache="stickers"} 0
+disgo_cache_hits_total{cache="thread_members"} 0
+disgo_cache_hits_total{cache="voice_states"} 0
+# HELP disgo_cache_misses_total Number of lookups which did not find an entity.
+# TYPE disgo_cache_misses_total counter
+disgo_cache_misses_total{cache="channels"} 0
+disgo_cache_misses_total{cache="emojis"} 0
+disgo_cache_misses_total{cache="guild_scheduled_events"} 0
+disgo_cache_misses_total{cache="guilds"} 0
+disgo_cache_misses_total{cache="members"} 0
+disgo_cache_misses_total{cache="messages"} 0
+disgo_cache_misses_total{cache="presences"} 0
+disgo_cache_misses_total{cache="roles"} 1
+disgo_cache_misses_total{cache="stage_instances"} 0
+disgo_cache_misses_total{cache="stickers"} 0
+disgo_cache_misses_total{cache="thread_members"} 0
+disgo_cache_misses_total{cache="voice_states"} 0
+`), "disgo_cache_hits_total", "disgo_cache_misses_total")
+	assert.NoError(t, err)
+	assert.Equal(t, 96, testutil.CollectAndCount(collector))
+}
@@ -0,0 +1,54 @@
+package cacheprometheus
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// DefaultConfig returns a Config with sensible defaults.
+func DefaultConfig() *Config {
+	return &Config{
+		Namespace: "disgo",
+		Subsystem: "cache",
+	}
+}
+
+// Config lets you configure the Collector.
+type Config struct {
+	// Namespace is the namespace of all metrics. Defaults to "disgo".
+	Namespace string
+	// Subsystem is the subsystem of all metrics. Defaults to "cache".
+	Subsystem string
+	// ConstLabels are added to all metrics, e.g. to distinguish multiple bots.
+	ConstLabels prometheus.Labels
+}
+
+// ConfigOpt can be used to supply optional parameters to NewCollector.
+type ConfigOpt func(config *Config)
+
+// Apply applies the given ConfigOpt(s) to the Config.
+func (c *Config) Apply(opts []ConfigOpt) {
+	for _, opt := range opts {
+		opt(c)
+	}
+}
+
+// WithNamespace sets the namespace of all metrics.
+func WithNamespace(namespace string) ConfigOpt {
+	return func(config *Config) {
+		config.Namespace = namespace
+	}
+}
+
+// WithSubsystem sets the subsystem of all metrics.
+func WithSubsystem(subsystem string) ConfigOpt {
+	return func(config *Config) {
+		config.Subsystem = subsystem
+	}
+}
+
+// WithConstLabels sets labels which are added to all metrics.
+func WithConstLabels(labels prometheus.Labels) ConfigOpt {
+	return func(config *Config) {
+		config.ConstLabels = labels
+	}
+}
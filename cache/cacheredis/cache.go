@@ -0,0 +1,194 @@
+package cacheredis
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+
+	"github.com/disgoorg/snowflake/v2"
+	"github.com/redis/go-redis/v9"
+
+	"github.com/disgoorg/disgo/cache"
+)
+
+var (
+	_ cache.Cache[any]      = (*cacheImpl[any])(nil)
+	_ cache.Observable[any] = (*cacheImpl[any])(nil)
+)
+
+// NewCache returns a new cache.Cache which stores its entities in a redis hash with the given name.
+// Entities not passing the cache.Policy are not cached. A nil policy caches all entities.
+// Redis errors are logged and handled like a cache miss.
+func NewCache[T any](client redis.UniversalClient, name string, policy cache.Policy[T], opts ...ConfigOpt) cache.Cache[T] {
+	config := newConfig(name, opts)
+	return &cacheImpl[T]{
+		client: client,
+		key:    config.Prefix + name,
+		policy: policy,
+		config: config,
+	}
+}
+
+type cacheImpl[T any] struct {
+	client    redis.UniversalClient
+	key       string
+	policy    cache.Policy[T]
+	config    Config
+	observers cache.Observers[T]
+	// writeMu serializes writes while the cache is observed
+	writeMu sync.Mutex
+}
+
+func (c *cacheImpl[T]) ctx() (context.Context, context.CancelFunc) {
+	return context.WithTimeout(context.Background(), c.config.Timeout)
+}
+
+func (c *cacheImpl[T]) decode(data []byte) (T, bool) {
+	entity, err := decode[T](c.config.Serializer, data)
+	if err != nil {
+		c.config.Logger.Error("failed to decode entity", slog.Any("err", err))
+		return entity, false
+	}
+	return entity, true
+}
+
+func (c *cacheImpl[T]) Get(id snowflake.ID) (T, bool) {
+	ctx, cancel := c.ctx()
+	defer cancel()
+
+	data, err := c.client.HGet(ctx, c.key, id.String()).Bytes()
+	if err != nil {
+		if !isNil(err) {
+			c.config.Logger.Error("failed to get entity", slog.Any("err", err), slog.String("id", id.String()))
+		}
+		var entity T
+		return entity, false
+	}
+	return c.decode(data)
+}
+
+func (c *cacheImpl[T]) Put(id snowflake.ID, entity T) {
+	if c.policy != nil && !c.policy(entity) {
+		return
+	}
+	data, err := c.config.Serializer.Marshal(entity)
+	if err != nil {
+		c.config.Logger.Error("failed to encode entity", slog.Any("err", err), slog.String("id", id.String()))
+		return
+	}
+
+	if c.observers.Len() > 0 {
+		// serialize writes, so observers receive the changes in write order
+		c.writeMu.Lock()
+		defer c.writeMu.Unlock()
+	}
+	ctx, cancel := c.ctx()
+	defer cancel()
+	if c.observers.Len() == 0 {
+		if err = c.client.HSet(ctx, c.key, id.String(), data).Err(); err != nil {
+			c.config.Logger.Error("failed to put entity", slog.Any("err", err), slog.String("id", id.String()))
+		}
+		return
+	}
+
+	// only fetch the old entity if someone is interested in it
+	oldData, err := hsetget(ctx, c.client, c.key, id.String(), data)
+	if err != nil && !isNil(err) {
+		c.config.Logger.Error("failed to put entity", slog.Any("err", err), slog.String("id", id.String()))
+		return
+	}
+	var (
+		oldEntity T
+		oldOK     bool
+	)
+	if err == nil {
+		oldEntity, oldOK = c.decode(oldData)
+	}
+	c.observers.Notify(cache.PutChange(0, id, oldEntity, oldOK, entity))
+}
+
+func (c *cacheImpl[T]) Remove(id snowflake.ID) (T, bool) {
+	if c.observers.Len() > 0 {
+		// serialize writes, so observers receive the changes in write order
+		c.writeMu.Lock()
+		defer c.writeMu.Unlock()
+	}
+	ctx, cancel := c.ctx()
+	defer cancel()
+
+	data, err := hgetdel(ctx, c.client, c.key, id.String())
+	if err != nil {
+		if !isNil(err) {
+			c.config.Logger.Error("failed to remove entity", slog.Any("err", err), slog.String("id", id.String()))
+		}
+		var entity T
+		return entity, false
+	}
+	entity, ok := c.decode(data)
+	if ok {
+		c.observers.Notify(cache.RemoveChange(0, id, entity))
+	}
+	return entity, ok
+}
+
+func (c *cacheImpl[T]) RemoveIf(filterFunc cache.FilterFunc[T]) {
+	if c.observers.Len() > 0 {
+		// serialize writes, so observers receive the changes in write order
+		c.writeMu.Lock()
+		defer c.writeMu.Unlock()
+	}
+	ctx, cancel := c.ctx()
+	defer cancel()
+
+	var (
+		ids     []string
+		changes []cache.Change[T]
+	)
+	if err := hscan(ctx, c.client, c.key, func(id string, data []byte) {
+		if entity, ok := c.decode(data); ok && filterFunc(entity) {
+			ids = append(ids, id)
+			if snowflakeID, err := snowflake.Parse(id); err == nil {
+				changes = append(changes, cache.RemoveChange(0, snowflakeID, entity))
+			}
+		}
+	}); err != nil {
+		c.config.Logger.Error("failed to scan entities", slog.Any("err", err))
+		return
+	}
+	if len(ids) == 0 {
+		return
+	}
+	if err := c.client.HDel(ctx, c.key, ids...).Err(); err != nil {
+		c.config.Logger.Error("failed to remove entities", slog.Any("err", err))
+		return
+	}
+	c.observers.Notify(changes...)
+}
+
+func (c *cacheImpl[T]) Len() int {
+	ctx, cancel := c.ctx()
+	defer cancel()
+
+	length, err := c.client.HLen(ctx, c.key).Result()
+	if err != nil {
+		c.config.Logger.Error("failed to get length", slog.Any("err", err))
+	}
+	return int(length)
+}
+
+func (c *cacheImpl[T]) ForEach(forEachFunc func(entity T)) {
+	ctx, cancel := c.ctx()
+	defer cancel()
+
+	if err := hscan(ctx, c.client, c.key, func(_ string, data []byte) {
+		if entity, ok := c.decode(data); ok {
+			forEachFunc(entity)
+		}
+	}); err != nil {
+		c.config.Logger.Error("failed to scan entities", slog.Any("err", err))
+	}
+}
+
+func (c *cacheImpl[T]) AddObserver(observer cache.Observer[T]) func() {
+	return c.observers.Add(observer)
+}
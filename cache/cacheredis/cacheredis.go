@@ -0,0 +1,130 @@
+// Package cacheredis provides redis backed implementations of the cache.Cache, cache.GroupedCache, cache.Set & cache.SelfUserCache interfaces.
+// This allows multiple processes to share their caches or keep them across restarts.
+// Observers only see changes made by the same process, so WithCaches never creates caches with secondary indexes.
+//
+// All sub-caches can be replaced at once with WithCaches or one by one with the cache.With*Cache options:
+//
+//	caches := cache.New(
+//		cache.WithCaches(cache.FlagsAll),
+//		cacheredis.WithCaches(client),
+//	)
+package cacheredis
+
+import (
+	"context"
+	"errors"
+
+	"github.com/disgoorg/snowflake/v2"
+	"github.com/redis/go-redis/v9"
+
+	"github.com/disgoorg/disgo/cache"
+	"github.com/disgoorg/disgo/discord"
+)
+
+// hgetdelScript returns & removes a hash field atomically.
+//
+// KEYS[1] is the hash key, ARGV[1] the field.
+var hgetdelScript = redis.NewScript(`
+local value = redis.call('HGET', KEYS[1], ARGV[1])
+if value then
+	redis.call('HDEL', KEYS[1], ARGV[1])
+end
+return value
+`)
+
+// hsetgetScript sets a hash field and returns its previous value atomically.
+//
+// KEYS[1] is the hash key, ARGV[1] the field & ARGV[2] the new value.
+var hsetgetScript = redis.NewScript(`
+local value = redis.call('HGET', KEYS[1], ARGV[1])
+redis.call('HSET', KEYS[1], ARGV[1], ARGV[2])
+return value
+`)
+
+// hsetget sets the given hash field and returns its previous value. A previously missing field returns redis.Nil.
+func hsetget(ctx context.Context, client redis.UniversalClient, key string, field string, value []byte) ([]byte, error) {
+	oldValue, err := hsetgetScript.Run(ctx, client, []string{key}, field, value).Text()
+	if err != nil {
+		return nil, err
+	}
+	return []byte(oldValue), nil
+}
+
+// hgetdel returns & removes the given hash field. A missing field returns redis.Nil.
+func hgetdel(ctx context.Context, client redis.UniversalClient, key string, field string) ([]byte, error) {
+	value, err := hgetdelScript.Run(ctx, client, []string{key}, field).Text()
+	if err != nil {
+		return nil, err
+	}
+	return []byte(value), nil
+}
+
+// hscan calls fn for each field of the given hash.
+func hscan(ctx context.Context, client redis.UniversalClient, key string, fn func(field string, value []byte)) error {
+	var cursor uint64
+	for {
+		values, nextCursor, err := client.HScan(ctx, key, cursor, "", 100).Result()
+		if err != nil {
+			return err
+		}
+		for i := 0; i+1 < len(values); i += 2 {
+			fn(values[i], []byte(values[i+1]))
+		}
+		if nextCursor == 0 {
+			return nil
+		}
+		cursor = nextCursor
+	}
+}
+
+func isNil(err error) bool {
+	return errors.Is(err, redis.Nil)
+}
+
+// WithCaches returns a cache.ConfigOpt which replaces all sub-caches enabled by the cache.Flags with redis backed ones.
+// It uses the cache.Policy(s) configured at the time it's applied, so it should be passed after all policy options.
+func WithCaches(client redis.UniversalClient, opts ...ConfigOpt) cache.ConfigOpt {
+	return func(config *cache.Config) {
+		config.SelfUserCache = NewSelfUserCache(client, opts...)
+
+		guildCache := cache.NewCache[discord.Guild](config.CacheFlags, cache.FlagGuilds, config.GuildCachePolicy)
+		if config.CacheFlags.Has(cache.FlagGuilds) {
+			guildCache = NewCache[discord.Guild](client, "guilds", config.GuildCachePolicy, opts...)
+		}
+		config.GuildCache = cache.NewGuildCache(guildCache, NewSet[snowflake.ID](client, "unready_guilds", opts...), NewSet[snowflake.ID](client, "unavailable_guilds", opts...))
+
+		if config.CacheFlags.Has(cache.FlagChannels) {
+			config.ChannelCache = cache.NewChannelCache(NewCache[discord.GuildChannel](client, "channels", config.ChannelCachePolicy, opts...))
+		}
+		if config.CacheFlags.Has(cache.FlagStageInstances) {
+			config.StageInstanceCache = cache.NewStageInstanceCache(NewGroupedCache[discord.StageInstance](client, "stage_instances", config.StageInstanceCachePolicy, opts...))
+		}
+		if config.CacheFlags.Has(cache.FlagGuildScheduledEvents) {
+			config.GuildScheduledEventCache = cache.NewGuildScheduledEventCache(NewGroupedCache[discord.GuildScheduledEvent](client, "guild_scheduled_events", config.GuildScheduledEventCachePolicy, opts...))
+		}
+		if config.CacheFlags.Has(cache.FlagRoles) {
+			config.RoleCache = cache.NewRoleCache(NewGroupedCache[discord.Role](client, "roles", config.RoleCachePolicy, opts...))
+		}
+		if config.CacheFlags.Has(cache.FlagMembers) {
+			config.MemberCache = cache.NewMemberCache(NewGroupedCache[discord.Member](client, "members", config.MemberCachePolicy, opts...))
+		}
+		if config.CacheFlags.Has(cache.FlagThreadMembers) {
+			config.ThreadMemberCache = cache.NewThreadMemberCache(NewGroupedCache[discord.ThreadMember](client, "thread_members", config.ThreadMemberCachePolicy, opts...))
+		}
+		if config.CacheFlags.Has(cache.FlagPresences) {
+			config.PresenceCache = cache.NewPresenceCache(NewGroupedCache[discord.Presence](client, "presences", config.PresenceCachePolicy, opts...))
+		}
+		if config.CacheFlags.Has(cache.FlagVoiceStates) {
+			config.VoiceStateCache = cache.NewVoiceStateCache(NewGroupedCache[discord.VoiceState](client, "voice_states", config.VoiceStateCachePolicy, opts...))
+		}
+		if config.CacheFlags.Has(cache.FlagMessages) {
+			config.MessageCache = cache.NewMessageCache(NewGroupedCache[discord.Message](client, "messages", config.MessageCachePolicy, opts...))
+		}
+		if config.CacheFlags.Has(cache.FlagEmojis) {
+			config.EmojiCache = cache.NewEmojiCache(NewGroupedCache[discord.Emoji](client, "emojis", config.EmojiCachePolicy, opts...))
+		}
+		if config.CacheFlags.Has(cache.FlagStickers) {
+			config.StickerCache = cache.NewStickerCache(NewGroupedCache[discord.Sticker](client, "stickers", config.StickerCachePolicy, opts...))
+		}
+	}
+}
@@ -0,0 +1,148 @@
+package cacheredis
+
+import (
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/disgoorg/json"
+	"github.com/disgoorg/snowflake/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/disgoorg/disgo/cache"
+	"github.com/disgoorg/disgo/discord"
+)
+
+func newClient(t *testing.T) redis.UniversalClient {
+	server := miniredis.RunT(t)
+	return redis.NewClient(&redis.Options{Addr: server.Addr()})
+}
+
+func TestCache(t *testing.T) {
+	c := NewCache[discord.Role](newClient(t), "roles", func(role discord.Role) bool {
+		return !role.Managed
+	})
+
+	c.Put(1, discord.Role{ID: 1, Name: "role"})
+	c.Put(2, discord.Role{ID: 2, Managed: true})
+	assert.Equal(t, 1, c.Len())
+
+	role, ok := c.Get(1)
+	assert.True(t, ok)
+	assert.Equal(t, "role", role.Name)
+
+	_, ok = c.Get(2)
+	assert.False(t, ok)
+
+	role, ok = c.Remove(1)
+	assert.True(t, ok)
+	assert.Equal(t, snowflake.ID(1), role.ID)
+
+	_, ok = c.Remove(1)
+	assert.False(t, ok)
+	assert.Equal(t, 0, c.Len())
+}
+
+func TestCacheGuildChannel(t *testing.T) {
+	c := NewCache[discord.GuildChannel](newClient(t), "channels", nil)
+
+	var unmarshalChannel discord.UnmarshalChannel
+	err := json.Unmarshal([]byte(`{"id":"1","guild_id":"2","type":0,"name":"general"}`), &unmarshalChannel)
+	assert.NoError(t, err)
+	c.Put(1, unmarshalChannel.Channel.(discord.GuildChannel))
+
+	channel, ok := c.Get(1)
+	assert.True(t, ok)
+	textChannel, ok := channel.(discord.GuildTextChannel)
+	assert.True(t, ok)
+	assert.Equal(t, "general", textChannel.Name())
+	assert.Equal(t, snowflake.ID(2), textChannel.GuildID())
+}
+
+func TestGroupedCache(t *testing.T) {
+	c := NewGroupedCache[discord.Role](newClient(t), "roles", nil)
+
+	c.Put(1, 10, discord.Role{ID: 10, GuildID: 1})
+	c.Put(1, 11, discord.Role{ID: 11, GuildID: 1, Managed: true})
+	c.Put(2, 20, discord.Role{ID: 20, GuildID: 2})
+	assert.Equal(t, 3, c.Len())
+	assert.Equal(t, 2, c.GroupLen(1))
+
+	c.RemoveIf(func(_ snowflake.ID, role discord.Role) bool {
+		return role.Managed
+	})
+	assert.Equal(t, 1, c.GroupLen(1))
+
+	var groupIDs []snowflake.ID
+	c.ForEach(func(groupID snowflake.ID, _ discord.Role) {
+		groupIDs = append(groupIDs, groupID)
+	})
+	assert.ElementsMatch(t, []snowflake.ID{1, 2}, groupIDs)
+
+	c.GroupRemove(1)
+	_, ok := c.Get(1, 10)
+	assert.False(t, ok)
+	assert.Equal(t, 1, c.Len())
+}
+
+func TestSet(t *testing.T) {
+	s := NewSet[snowflake.ID](newClient(t), "unready_guilds")
+
+	s.Add(1)
+	s.Add(2)
+	s.Add(2)
+	assert.Equal(t, 2, s.Len())
+	assert.True(t, s.Has(1))
+
+	s.Remove(1)
+	assert.False(t, s.Has(1))
+
+	var items []snowflake.ID
+	s.ForEach(func(item snowflake.ID) {
+		items = append(items, item)
+	})
+	assert.Equal(t, []snowflake.ID{2}, items)
+
+	s.Clear()
+	assert.Equal(t, 0, s.Len())
+}
+
+func TestWithCaches(t *testing.T) {
+	client := newClient(t)
+	caches := cache.New(
+		cache.WithCaches(cache.FlagGuilds, cache.FlagRoles),
+		WithCaches(client, WithPrefix("test:")),
+	)
+
+	caches.AddRole(discord.Role{ID: 10, GuildID: 1})
+	caches.SetGuildUnready(1, true)
+
+	other := cache.New(
+		cache.WithCaches(cache.FlagGuilds, cache.FlagRoles),
+		WithCaches(client, WithPrefix("test:")),
+	)
+	_, ok := other.Role(1, 10)
+	assert.True(t, ok)
+	assert.True(t, other.IsGuildUnready(1))
+}
+
+func TestGroupedCacheObserver(t *testing.T) {
+	c := NewGroupedCache[discord.Role](newClient(t), "roles", nil)
+
+	var changes []cache.Change[discord.Role]
+	c.(cache.Observable[discord.Role]).AddObserver(func(change cache.Change[discord.Role]) {
+		changes = append(changes, change)
+	})
+
+	c.Put(1, 10, discord.Role{ID: 10, Name: "a"})
+	c.Put(1, 10, discord.Role{ID: 10, Name: "b"})
+	c.GroupRemove(1)
+
+	if assert.Len(t, changes, 3) {
+		assert.Nil(t, changes[0].Old)
+		assert.Equal(t, "a", changes[1].Old.Name)
+		assert.Equal(t, "b", changes[1].New.Name)
+		assert.Equal(t, "b", changes[2].Old.Name)
+		assert.Nil(t, changes[2].New)
+	}
+}
@@ -0,0 +1,73 @@
+package cacheredis
+
+import (
+	"log/slog"
+	"time"
+)
+
+// DefaultConfig returns a Config with sensible defaults.
+func DefaultConfig() *Config {
+	return &Config{
+		Logger:     slog.Default(),
+		Serializer: NewJSONSerializer(),
+		Prefix:     "disgo:cache:",
+		Timeout:    5 * time.Second,
+	}
+}
+
+// Config lets you configure the redis backed caches.
+type Config struct {
+	// Logger is used to log redis errors as the cache interfaces can't return them. Defaults to slog.Default().
+	Logger *slog.Logger
+	// Serializer is used to encode & decode the cached entities. Defaults to NewJSONSerializer().
+	Serializer Serializer
+	// Prefix is prepended to all redis keys. Use a different prefix per bot. Defaults to "disgo:cache:".
+	Prefix string
+	// Timeout is the timeout of a single cache operation. Defaults to 5 seconds.
+	Timeout time.Duration
+}
+
+// ConfigOpt can be used to supply optional parameters to the redis backed caches.
+type ConfigOpt func(config *Config)
+
+// Apply applies the given ConfigOpt(s) to the Config.
+func (c *Config) Apply(opts []ConfigOpt) {
+	for _, opt := range opts {
+		opt(c)
+	}
+}
+
+// WithLogger applies a custom logger to the caches.
+func WithLogger(logger *slog.Logger) ConfigOpt {
+	return func(config *Config) {
+		config.Logger = logger
+	}
+}
+
+// WithSerializer sets the Serializer used to encode & decode the cached entities.
+func WithSerializer(serializer Serializer) ConfigOpt {
+	return func(config *Config) {
+		config.Serializer = serializer
+	}
+}
+
+// WithPrefix sets the prefix of all redis keys used by the caches.
+func WithPrefix(prefix string) ConfigOpt {
+	return func(config *Config) {
+		config.Prefix = prefix
+	}
+}
+
+// WithTimeout sets the timeout of a single cache operation.
+func WithTimeout(timeout time.Duration) ConfigOpt {
+	return func(config *Config) {
+		config.Timeout = timeout
+	}
+}
+
+func newConfig(name string, opts []ConfigOpt) Config {
+	config := DefaultConfig()
+	config.Apply(opts)
+	config.Logger = config.Logger.With(slog.String("name", "cache_redis"), slog.String("cache", name))
+	return *config
+}
@@ -0,0 +1,323 @@
+package cacheredis
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+
+	"github.com/disgoorg/snowflake/v2"
+	"github.com/redis/go-redis/v9"
+
+	"github.com/disgoorg/disgo/cache"
+)
+
+var (
+	_ cache.GroupedCache[any] = (*groupedCacheImpl[any])(nil)
+	_ cache.Observable[any]   = (*groupedCacheImpl[any])(nil)
+)
+
+// NewGroupedCache returns a new cache.GroupedCache which stores the entities of each group in a redis hash.
+// The IDs of all groups are tracked in a redis set.
+// Entities not passing the cache.Policy are not cached. A nil policy caches all entities.
+// Redis errors are logged and handled like a cache miss.
+func NewGroupedCache[T any](client redis.UniversalClient, name string, policy cache.Policy[T], opts ...ConfigOpt) cache.GroupedCache[T] {
+	config := newConfig(name, opts)
+	return &groupedCacheImpl[T]{
+		client:    client,
+		key:       config.Prefix + name,
+		groupsKey: config.Prefix + name + ":groups",
+		policy:    policy,
+		config:    config,
+	}
+}
+
+type groupedCacheImpl[T any] struct {
+	client    redis.UniversalClient
+	key       string
+	groupsKey string
+	policy    cache.Policy[T]
+	config    Config
+	observers cache.Observers[T]
+	// writeMu serializes writes while the cache is observed
+	writeMu sync.Mutex
+}
+
+func (c *groupedCacheImpl[T]) ctx() (context.Context, context.CancelFunc) {
+	return context.WithTimeout(context.Background(), c.config.Timeout)
+}
+
+func (c *groupedCacheImpl[T]) groupKey(groupID snowflake.ID) string {
+	return c.key + ":" + groupID.String()
+}
+
+func (c *groupedCacheImpl[T]) decode(data []byte) (T, bool) {
+	entity, err := decode[T](c.config.Serializer, data)
+	if err != nil {
+		c.config.Logger.Error("failed to decode entity", slog.Any("err", err))
+		return entity, false
+	}
+	return entity, true
+}
+
+// groupIDs returns the IDs of all groups which might contain entities.
+func (c *groupedCacheImpl[T]) groupIDs(ctx context.Context) []snowflake.ID {
+	members, err := c.client.SMembers(ctx, c.groupsKey).Result()
+	if err != nil {
+		c.config.Logger.Error("failed to get groups", slog.Any("err", err))
+		return nil
+	}
+	groupIDs := make([]snowflake.ID, 0, len(members))
+	for _, member := range members {
+		groupID, err := snowflake.Parse(member)
+		if err != nil {
+			continue
+		}
+		groupIDs = append(groupIDs, groupID)
+	}
+	return groupIDs
+}
+
+func (c *groupedCacheImpl[T]) Get(groupID snowflake.ID, id snowflake.ID) (T, bool) {
+	ctx, cancel := c.ctx()
+	defer cancel()
+
+	data, err := c.client.HGet(ctx, c.groupKey(groupID), id.String()).Bytes()
+	if err != nil {
+		if !isNil(err) {
+			c.config.Logger.Error("failed to get entity", slog.Any("err", err), slog.String("group_id", groupID.String()), slog.String("id", id.String()))
+		}
+		var entity T
+		return entity, false
+	}
+	return c.decode(data)
+}
+
+func (c *groupedCacheImpl[T]) Put(groupID snowflake.ID, id snowflake.ID, entity T) {
+	if c.policy != nil && !c.policy(entity) {
+		return
+	}
+	data, err := c.config.Serializer.Marshal(entity)
+	if err != nil {
+		c.config.Logger.Error("failed to encode entity", slog.Any("err", err), slog.String("group_id", groupID.String()), slog.String("id", id.String()))
+		return
+	}
+
+	if c.observers.Len() > 0 {
+		// serialize writes, so observers receive the changes in write order
+		c.writeMu.Lock()
+		defer c.writeMu.Unlock()
+	}
+	ctx, cancel := c.ctx()
+	defer cancel()
+	if err = c.client.SAdd(ctx, c.groupsKey, groupID.String()).Err(); err != nil {
+		c.config.Logger.Error("failed to put group", slog.Any("err", err), slog.String("group_id", groupID.String()))
+		return
+	}
+	if c.observers.Len() == 0 {
+		if err = c.client.HSet(ctx, c.groupKey(groupID), id.String(), data).Err(); err != nil {
+			c.config.Logger.Error("failed to put entity", slog.Any("err", err), slog.String("group_id", groupID.String()), slog.String("id", id.String()))
+		}
+		return
+	}
+
+	// only fetch the old entity if someone is interested in it
+	oldData, err := hsetget(ctx, c.client, c.groupKey(groupID), id.String(), data)
+	if err != nil && !isNil(err) {
+		c.config.Logger.Error("failed to put entity", slog.Any("err", err), slog.String("group_id", groupID.String()), slog.String("id", id.String()))
+		return
+	}
+	var (
+		oldEntity T
+		oldOK     bool
+	)
+	if err == nil {
+		oldEntity, oldOK = c.decode(oldData)
+	}
+	c.observers.Notify(cache.PutChange(groupID, id, oldEntity, oldOK, entity))
+}
+
+func (c *groupedCacheImpl[T]) Remove(groupID snowflake.ID, id snowflake.ID) (T, bool) {
+	if c.observers.Len() > 0 {
+		// serialize writes, so observers receive the changes in write order
+		c.writeMu.Lock()
+		defer c.writeMu.Unlock()
+	}
+	ctx, cancel := c.ctx()
+	defer cancel()
+
+	data, err := hgetdel(ctx, c.client, c.groupKey(groupID), id.String())
+	if err != nil {
+		if !isNil(err) {
+			c.config.Logger.Error("failed to remove entity", slog.Any("err", err), slog.String("group_id", groupID.String()), slog.String("id", id.String()))
+		}
+		var entity T
+		return entity, false
+	}
+	entity, ok := c.decode(data)
+	if ok {
+		c.observers.Notify(cache.RemoveChange(groupID, id, entity))
+	}
+	return entity, ok
+}
+
+func (c *groupedCacheImpl[T]) GroupRemove(groupID snowflake.ID) {
+	if c.observers.Len() > 0 {
+		// serialize writes, so observers receive the changes in write order
+		c.writeMu.Lock()
+		defer c.writeMu.Unlock()
+	}
+	ctx, cancel := c.ctx()
+	defer cancel()
+
+	var getAll *redis.MapStringStringCmd
+	_, err := c.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		// only fetch the removed entities if someone is interested in them
+		if c.observers.Len() > 0 {
+			getAll = pipe.HGetAll(ctx, c.groupKey(groupID))
+		}
+		pipe.Del(ctx, c.groupKey(groupID))
+		pipe.SRem(ctx, c.groupsKey, groupID.String())
+		return nil
+	})
+	if err != nil {
+		c.config.Logger.Error("failed to remove group", slog.Any("err", err), slog.String("group_id", groupID.String()))
+		return
+	}
+	if getAll == nil {
+		return
+	}
+
+	changes := make([]cache.Change[T], 0, len(getAll.Val()))
+	for id, data := range getAll.Val() {
+		snowflakeID, err := snowflake.Parse(id)
+		if err != nil {
+			continue
+		}
+		if entity, ok := c.decode([]byte(data)); ok {
+			changes = append(changes, cache.RemoveChange(groupID, snowflakeID, entity))
+		}
+	}
+	c.observers.Notify(changes...)
+}
+
+func (c *groupedCacheImpl[T]) RemoveIf(filterFunc cache.GroupedFilterFunc[T]) {
+	if c.observers.Len() > 0 {
+		// serialize writes, so observers receive the changes in write order
+		c.writeMu.Lock()
+		defer c.writeMu.Unlock()
+	}
+	ctx, cancel := c.ctx()
+	defer cancel()
+
+	for _, groupID := range c.groupIDs(ctx) {
+		c.groupRemoveIf(ctx, groupID, filterFunc)
+	}
+}
+
+func (c *groupedCacheImpl[T]) GroupRemoveIf(groupID snowflake.ID, filterFunc cache.GroupedFilterFunc[T]) {
+	if c.observers.Len() > 0 {
+		// serialize writes, so observers receive the changes in write order
+		c.writeMu.Lock()
+		defer c.writeMu.Unlock()
+	}
+	ctx, cancel := c.ctx()
+	defer cancel()
+
+	c.groupRemoveIf(ctx, groupID, filterFunc)
+}
+
+func (c *groupedCacheImpl[T]) groupRemoveIf(ctx context.Context, groupID snowflake.ID, filterFunc cache.GroupedFilterFunc[T]) {
+	key := c.groupKey(groupID)
+	var (
+		ids     []string
+		changes []cache.Change[T]
+	)
+	if err := hscan(ctx, c.client, key, func(id string, data []byte) {
+		if entity, ok := c.decode(data); ok && filterFunc(groupID, entity) {
+			ids = append(ids, id)
+			if snowflakeID, err := snowflake.Parse(id); err == nil {
+				changes = append(changes, cache.RemoveChange(groupID, snowflakeID, entity))
+			}
+		}
+	}); err != nil {
+		c.config.Logger.Error("failed to scan entities", slog.Any("err", err), slog.String("group_id", groupID.String()))
+		return
+	}
+	if len(ids) == 0 {
+		return
+	}
+	if err := c.client.HDel(ctx, key, ids...).Err(); err != nil {
+		c.config.Logger.Error("failed to remove entities", slog.Any("err", err), slog.String("group_id", groupID.String()))
+		return
+	}
+	c.observers.Notify(changes...)
+}
+
+func (c *groupedCacheImpl[T]) Len() int {
+	ctx, cancel := c.ctx()
+	defer cancel()
+
+	groupIDs := c.groupIDs(ctx)
+	if len(groupIDs) == 0 {
+		return 0
+	}
+	cmds, err := c.client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		for _, groupID := range groupIDs {
+			pipe.HLen(ctx, c.groupKey(groupID))
+		}
+		return nil
+	})
+	if err != nil {
+		c.config.Logger.Error("failed to get length", slog.Any("err", err))
+		return 0
+	}
+
+	var length int
+	for _, cmd := range cmds {
+		length += int(cmd.(*redis.IntCmd).Val())
+	}
+	return length
+}
+
+func (c *groupedCacheImpl[T]) GroupLen(groupID snowflake.ID) int {
+	ctx, cancel := c.ctx()
+	defer cancel()
+
+	length, err := c.client.HLen(ctx, c.groupKey(groupID)).Result()
+	if err != nil {
+		c.config.Logger.Error("failed to get group length", slog.Any("err", err), slog.String("group_id", groupID.String()))
+	}
+	return int(length)
+}
+
+func (c *groupedCacheImpl[T]) ForEach(forEachFunc func(groupID snowflake.ID, entity T)) {
+	ctx, cancel := c.ctx()
+	defer cancel()
+
+	for _, groupID := range c.groupIDs(ctx) {
+		c.groupForEach(ctx, groupID, func(entity T) {
+			forEachFunc(groupID, entity)
+		})
+	}
+}
+
+func (c *groupedCacheImpl[T]) GroupForEach(groupID snowflake.ID, forEachFunc func(entity T)) {
+	ctx, cancel := c.ctx()
+	defer cancel()
+
+	c.groupForEach(ctx, groupID, forEachFunc)
+}
+
+func (c *groupedCacheImpl[T]) groupForEach(ctx context.Context, groupID snowflake.ID, forEachFunc func(entity T)) {
+	if err := hscan(ctx, c.client, c.groupKey(groupID), func(_ string, data []byte) {
+		if entity, ok := c.decode(data); ok {
+			forEachFunc(entity)
+		}
+	}); err != nil {
+		c.config.Logger.Error("failed to scan entities", slog.Any("err", err), slog.String("group_id", groupID.String()))
+	}
+}
+
+func (c *groupedCacheImpl[T]) AddObserver(observer cache.Observer[T]) func() {
+	return c.observers.Add(observer)
+}
@@ -0,0 +1,63 @@
+package cacheredis
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/disgoorg/disgo/cache"
+	"github.com/disgoorg/disgo/discord"
+)
+
+var _ cache.SelfUserCache = (*selfUserCacheImpl)(nil)
+
+// NewSelfUserCache returns a new cache.SelfUserCache which stores the self user in a redis string.
+func NewSelfUserCache(client redis.UniversalClient, opts ...ConfigOpt) cache.SelfUserCache {
+	config := newConfig("self_user", opts)
+	return &selfUserCacheImpl{
+		client: client,
+		key:    config.Prefix + "self_user",
+		config: config,
+	}
+}
+
+type selfUserCacheImpl struct {
+	client redis.UniversalClient
+	key    string
+	config Config
+}
+
+func (c *selfUserCacheImpl) SelfUser() (discord.OAuth2User, bool) {
+	ctx, cancel := context.WithTimeout(context.Background(), c.config.Timeout)
+	defer cancel()
+
+	data, err := c.client.Get(ctx, c.key).Bytes()
+	if err != nil {
+		if !isNil(err) {
+			c.config.Logger.Error("failed to get self user", slog.Any("err", err))
+		}
+		return discord.OAuth2User{}, false
+	}
+
+	var selfUser discord.OAuth2User
+	if err = c.config.Serializer.Unmarshal(data, &selfUser); err != nil {
+		c.config.Logger.Error("failed to decode self user", slog.Any("err", err))
+		return discord.OAuth2User{}, false
+	}
+	return selfUser, true
+}
+
+func (c *selfUserCacheImpl) SetSelfUser(selfUser discord.OAuth2User) {
+	data, err := c.config.Serializer.Marshal(selfUser)
+	if err != nil {
+		c.config.Logger.Error("failed to encode self user", slog.Any("err", err))
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), c.config.Timeout)
+	defer cancel()
+	if err = c.client.Set(ctx, c.key, data, 0).Err(); err != nil {
+		c.config.Logger.Error("failed to set self user", slog.Any("err", err))
+	}
+}
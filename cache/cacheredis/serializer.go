@@ -0,0 +1,50 @@
+package cacheredis
+
+import (
+	"github.com/disgoorg/json"
+
+	"github.com/disgoorg/disgo/discord"
+)
+
+// Serializer encodes & decodes cached entities.
+type Serializer interface {
+	// Marshal encodes the given entity.
+	Marshal(v any) ([]byte, error)
+	// Unmarshal decodes the given data into v.
+	Unmarshal(data []byte, v any) error
+}
+
+var _ Serializer = (*jsonSerializer)(nil)
+
+// NewJSONSerializer returns a Serializer which encodes entities as JSON.
+func NewJSONSerializer() Serializer {
+	return &jsonSerializer{}
+}
+
+type jsonSerializer struct{}
+
+func (*jsonSerializer) Marshal(v any) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+func (*jsonSerializer) Unmarshal(data []byte, v any) error {
+	return json.Unmarshal(data, v)
+}
+
+// decode decodes the given data into a T. Interface types like discord.GuildChannel are decoded via their concrete type.
+func decode[T any](serializer Serializer, data []byte) (T, error) {
+	var entity T
+	if _, ok := any(&entity).(*discord.GuildChannel); ok {
+		var channel discord.UnmarshalChannel
+		if err := serializer.Unmarshal(data, &channel); err != nil {
+			return entity, err
+		}
+		if guildChannel, ok := channel.Channel.(discord.GuildChannel); ok {
+			entity, _ = any(guildChannel).(T)
+		}
+		return entity, nil
+	}
+
+	err := serializer.Unmarshal(data, &entity)
+	return entity, err
+}
@@ -0,0 +1,130 @@
+package cacheredis
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/disgoorg/disgo/cache"
+)
+
+var _ cache.Set[int] = (*setImpl[int])(nil)
+
+// NewSet returns a new cache.Set which stores its items in a redis set with the given name.
+// Items are encoded with the configured Serializer.
+// Redis errors are logged and handled like a missing item.
+func NewSet[T comparable](client redis.UniversalClient, name string, opts ...ConfigOpt) cache.Set[T] {
+	config := newConfig(name, opts)
+	return &setImpl[T]{
+		client: client,
+		key:    config.Prefix + name,
+		config: config,
+	}
+}
+
+type setImpl[T comparable] struct {
+	client redis.UniversalClient
+	key    string
+	config Config
+}
+
+func (s *setImpl[T]) ctx() (context.Context, context.CancelFunc) {
+	return context.WithTimeout(context.Background(), s.config.Timeout)
+}
+
+func (s *setImpl[T]) encode(item T) ([]byte, bool) {
+	data, err := s.config.Serializer.Marshal(item)
+	if err != nil {
+		s.config.Logger.Error("failed to encode item", slog.Any("err", err))
+		return nil, false
+	}
+	return data, true
+}
+
+func (s *setImpl[T]) Add(item T) {
+	data, ok := s.encode(item)
+	if !ok {
+		return
+	}
+	ctx, cancel := s.ctx()
+	defer cancel()
+
+	if err := s.client.SAdd(ctx, s.key, data).Err(); err != nil {
+		s.config.Logger.Error("failed to add item", slog.Any("err", err))
+	}
+}
+
+func (s *setImpl[T]) Remove(item T) {
+	data, ok := s.encode(item)
+	if !ok {
+		return
+	}
+	ctx, cancel := s.ctx()
+	defer cancel()
+
+	if err := s.client.SRem(ctx, s.key, data).Err(); err != nil {
+		s.config.Logger.Error("failed to remove item", slog.Any("err", err))
+	}
+}
+
+func (s *setImpl[T]) Has(item T) bool {
+	data, ok := s.encode(item)
+	if !ok {
+		return false
+	}
+	ctx, cancel := s.ctx()
+	defer cancel()
+
+	has, err := s.client.SIsMember(ctx, s.key, data).Result()
+	if err != nil {
+		s.config.Logger.Error("failed to check item", slog.Any("err", err))
+	}
+	return has
+}
+
+func (s *setImpl[T]) Len() int {
+	ctx, cancel := s.ctx()
+	defer cancel()
+
+	length, err := s.client.SCard(ctx, s.key).Result()
+	if err != nil {
+		s.config.Logger.Error("failed to get length", slog.Any("err", err))
+	}
+	return int(length)
+}
+
+func (s *setImpl[T]) Clear() {
+	ctx, cancel := s.ctx()
+	defer cancel()
+
+	if err := s.client.Del(ctx, s.key).Err(); err != nil {
+		s.config.Logger.Error("failed to clear set", slog.Any("err", err))
+	}
+}
+
+func (s *setImpl[T]) ForEach(f func(item T)) {
+	ctx, cancel := s.ctx()
+	defer cancel()
+
+	var cursor uint64
+	for {
+		members, nextCursor, err := s.client.SScan(ctx, s.key, cursor, "", 100).Result()
+		if err != nil {
+			s.config.Logger.Error("failed to scan items", slog.Any("err", err))
+			return
+		}
+		for _, member := range members {
+			item, err := decode[T](s.config.Serializer, []byte(member))
+			if err != nil {
+				s.config.Logger.Error("failed to decode item", slog.Any("err", err))
+				continue
+			}
+			f(item)
+		}
+		if nextCursor == 0 {
+			return
+		}
+		cursor = nextCursor
+	}
+}
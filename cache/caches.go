@@ -1,8 +1,8 @@
 package cache
 
 import (
+	"slices"
 	"sync"
-	"time"
 
 	"github.com/disgoorg/snowflake/v2"
 
@@ -95,9 +95,9 @@ func (c *guildCacheImpl) IsGuildUnavailable(guildID snowflake.ID) bool {
 }
 
 func (c *guildCacheImpl) SetGuildUnavailable(guildID snowflake.ID, unavailable bool) {
-	if c.unavailableGuilds.Has(guildID) && unavailable {
+	if c.unavailableGuilds.Has(guildID) && !unavailable {
 		c.unavailableGuilds.Remove(guildID)
-	} else if !c.unavailableGuilds.Has(guildID) && !unavailable {
+	} else if !c.unavailableGuilds.Has(guildID) && unavailable {
 		c.unavailableGuilds.Add(guildID)
 	}
 }
@@ -122,6 +122,10 @@ func (c *guildCacheImpl) GuildsLen() int {
 	return c.cache.Len()
 }
 
+func (c *guildCacheImpl) Stats() Stats {
+	return cacheStats(c.cache)
+}
+
 func (c *guildCacheImpl) AddGuild(guild discord.Guild) {
 	c.cache.Put(guild.ID, guild)
 }
@@ -139,12 +143,45 @@ type ChannelCache interface {
 	RemoveChannelsByGuildID(guildID snowflake.ID)
 }
 
+// ChannelIndexer is an optional interface of a ChannelCache which looks up channels by guild & parent ID without scanning all channels.
+// Use NewIndexedChannelCache or WithIndexes to create one.
+type ChannelIndexer interface {
+	// GuildChannelsForEach calls the given function for each channel of the given guild.
+	GuildChannelsForEach(guildID snowflake.ID, fn func(channel discord.GuildChannel))
+	// ChildChannelsForEach calls the given function for each channel & thread with the given parent ID.
+	ChildChannelsForEach(parentID snowflake.ID, fn func(channel discord.GuildChannel))
+}
+
 func NewChannelCache(cache Cache[discord.GuildChannel]) ChannelCache {
 	return &channelCacheImpl{
 		cache: cache,
 	}
 }
 
+// NewIndexedChannelCache returns a ChannelCache which implements ChannelIndexer.
+// The given Cache must implement Observable, otherwise the returned ChannelCache scans all channels like NewChannelCache.
+func NewIndexedChannelCache(cache Cache[discord.GuildChannel]) ChannelCache {
+	byGuild := newCacheIndex(cache, func(channel discord.GuildChannel) []snowflake.ID {
+		return []snowflake.ID{channel.GuildID()}
+	}, discord.GuildChannel.ID)
+	byParent := newCacheIndex(cache, func(channel discord.GuildChannel) []snowflake.ID {
+		if parentID := channel.ParentID(); parentID != nil {
+			return []snowflake.ID{*parentID}
+		}
+		return nil
+	}, discord.GuildChannel.ID)
+	if byGuild == nil || byParent == nil {
+		return NewChannelCache(cache)
+	}
+	return &indexedChannelCacheImpl{
+		channelCacheImpl: &channelCacheImpl{
+			cache: cache,
+		},
+		byGuild:  byGuild,
+		byParent: byParent,
+	}
+}
+
 type channelCacheImpl struct {
 	cache Cache[discord.GuildChannel]
 }
@@ -161,6 +198,10 @@ func (c *channelCacheImpl) ChannelsLen() int {
 	return c.cache.Len()
 }
 
+func (c *channelCacheImpl) Stats() Stats {
+	return cacheStats(c.cache)
+}
+
 func (c *channelCacheImpl) AddChannel(channel discord.GuildChannel) {
 	c.cache.Put(channel.ID(), channel)
 }
@@ -175,6 +216,41 @@ func (c *channelCacheImpl) RemoveChannelsByGuildID(guildID snowflake.ID) {
 	})
 }
 
+type indexedChannelCacheImpl struct {
+	*channelCacheImpl
+	byGuild  Index[discord.GuildChannel]
+	byParent Index[discord.GuildChannel]
+}
+
+func (c *indexedChannelCacheImpl) RemoveChannelsByGuildID(guildID snowflake.ID) {
+	for _, ref := range c.byGuild.Refs(guildID) {
+		if channel, ok := c.cache.Get(ref.ID); ok && channel.GuildID() == guildID {
+			c.cache.Remove(ref.ID)
+		}
+	}
+}
+
+func (c *indexedChannelCacheImpl) GuildChannelsForEach(guildID snowflake.ID, fn func(channel discord.GuildChannel)) {
+	for _, ref := range c.byGuild.Refs(guildID) {
+		if channel, ok := c.cache.Get(ref.ID); ok && channel.GuildID() == guildID {
+			fn(channel)
+		}
+	}
+}
+
+func (c *indexedChannelCacheImpl) ChildChannelsForEach(parentID snowflake.ID, fn func(channel discord.GuildChannel)) {
+	for _, ref := range c.byParent.Refs(parentID) {
+		if channel, ok := c.cache.Get(ref.ID); ok && isChildChannel(channel, parentID) {
+			fn(channel)
+		}
+	}
+}
+
+func isChildChannel(channel discord.GuildChannel, parentID snowflake.ID) bool {
+	channelParentID := channel.ParentID()
+	return channelParentID != nil && *channelParentID == parentID
+}
+
 type StageInstanceCache interface {
 	StageInstance(guildID snowflake.ID, stageInstanceID snowflake.ID) (discord.StageInstance, bool)
 	StageInstanceForEach(guildID snowflake.ID, fn func(stageInstance discord.StageInstance))
@@ -209,6 +285,10 @@ func (c *stageInstanceCacheImpl) StageInstancesAllLen() int {
 	return c.cache.Len()
 }
 
+func (c *stageInstanceCacheImpl) Stats() Stats {
+	return cacheStats(c.cache)
+}
+
 func (c *stageInstanceCacheImpl) StageInstancesLen(guildID snowflake.ID) int {
 	return c.cache.GroupLen(guildID)
 }
@@ -257,6 +337,10 @@ func (c *guildScheduledEventCacheImpl) GuildScheduledEventsAllLen() int {
 	return c.cache.Len()
 }
 
+func (c *guildScheduledEventCacheImpl) Stats() Stats {
+	return cacheStats(c.cache)
+}
+
 func (c *guildScheduledEventCacheImpl) GuildScheduledEventsLen(guildID snowflake.ID) int {
 	return c.cache.GroupLen(guildID)
 }
@@ -305,6 +389,10 @@ func (c *roleCacheImpl) RolesAllLen() int {
 	return c.cache.Len()
 }
 
+func (c *roleCacheImpl) Stats() Stats {
+	return cacheStats(c.cache)
+}
+
 func (c *roleCacheImpl) RolesLen(guildID snowflake.ID) int {
 	return c.cache.GroupLen(guildID)
 }
@@ -331,12 +419,38 @@ type MemberCache interface {
 	RemoveMembersByGuildID(guildID snowflake.ID)
 }
 
+// MemberIndexer is an optional interface of a MemberCache which looks up members by role ID without scanning all members.
+// Use NewIndexedMemberCache or WithIndexes to create one.
+type MemberIndexer interface {
+	// RoleMembersForEach calls the given function for each member with the given role.
+	RoleMembersForEach(roleID snowflake.ID, fn func(member discord.Member))
+}
+
 func NewMemberCache(cache GroupedCache[discord.Member]) MemberCache {
 	return &memberCacheImpl{
 		cache: cache,
 	}
 }
 
+// NewIndexedMemberCache returns a MemberCache which implements MemberIndexer.
+// The given GroupedCache must implement Observable, otherwise the returned MemberCache scans all members like NewMemberCache.
+func NewIndexedMemberCache(cache GroupedCache[discord.Member]) MemberCache {
+	byRole := newGroupedCacheIndex(cache, func(member discord.Member) []snowflake.ID {
+		return member.RoleIDs
+	}, func(member discord.Member) snowflake.ID {
+		return member.User.ID
+	})
+	if byRole == nil {
+		return NewMemberCache(cache)
+	}
+	return &indexedMemberCacheImpl{
+		memberCacheImpl: &memberCacheImpl{
+			cache: cache,
+		},
+		byRole: byRole,
+	}
+}
+
 type memberCacheImpl struct {
 	cache GroupedCache[discord.Member]
 }
@@ -353,6 +467,10 @@ func (c *memberCacheImpl) MembersAllLen() int {
 	return c.cache.Len()
 }
 
+func (c *memberCacheImpl) Stats() Stats {
+	return cacheStats(c.cache)
+}
+
 func (c *memberCacheImpl) MembersLen(guildID snowflake.ID) int {
 	return c.cache.GroupLen(guildID)
 }
@@ -369,6 +487,19 @@ func (c *memberCacheImpl) RemoveMembersByGuildID(guildID snowflake.ID) {
 	c.cache.GroupRemove(guildID)
 }
 
+type indexedMemberCacheImpl struct {
+	*memberCacheImpl
+	byRole Index[discord.Member]
+}
+
+func (c *indexedMemberCacheImpl) RoleMembersForEach(roleID snowflake.ID, fn func(member discord.Member)) {
+	for _, ref := range c.byRole.Refs(roleID) {
+		if member, ok := c.cache.Get(ref.GroupID, ref.ID); ok && slices.Contains(member.RoleIDs, roleID) {
+			fn(member)
+		}
+	}
+}
+
 type ThreadMemberCache interface {
 	ThreadMember(threadID snowflake.ID, userID snowflake.ID) (discord.ThreadMember, bool)
 	ThreadMemberForEach(threadID snowflake.ID, fn func(threadMember discord.ThreadMember))
@@ -403,6 +534,10 @@ func (c *threadMemberCacheImpl) ThreadMembersAllLen() int {
 	return c.cache.Len()
 }
 
+func (c *threadMemberCacheImpl) Stats() Stats {
+	return cacheStats(c.cache)
+}
+
 func (c *threadMemberCacheImpl) ThreadMembersLen(guildID snowflake.ID) int {
 	return c.cache.GroupLen(guildID)
 }
@@ -453,6 +588,10 @@ func (c *presenceCacheImpl) PresencesAllLen() int {
 	return c.cache.Len()
 }
 
+func (c *presenceCacheImpl) Stats() Stats {
+	return cacheStats(c.cache)
+}
+
 func (c *presenceCacheImpl) PresencesLen(guildID snowflake.ID) int {
 	return c.cache.GroupLen(guildID)
 }
@@ -479,12 +618,41 @@ type VoiceStateCache interface {
 	RemoveVoiceStatesByGuildID(guildID snowflake.ID)
 }
 
+// VoiceStateIndexer is an optional interface of a VoiceStateCache which looks up voice states by channel ID without scanning all voice states.
+// Use NewIndexedVoiceStateCache or WithIndexes to create one.
+type VoiceStateIndexer interface {
+	// ChannelVoiceStatesForEach calls the given function for each voice state connected to the given channel.
+	ChannelVoiceStatesForEach(channelID snowflake.ID, fn func(voiceState discord.VoiceState))
+}
+
 func NewVoiceStateCache(cache GroupedCache[discord.VoiceState]) VoiceStateCache {
 	return &voiceStateCacheImpl{
 		cache: cache,
 	}
 }
 
+// NewIndexedVoiceStateCache returns a VoiceStateCache which implements VoiceStateIndexer.
+// The given GroupedCache must implement Observable, otherwise the returned VoiceStateCache scans all voice states like NewVoiceStateCache.
+func NewIndexedVoiceStateCache(cache GroupedCache[discord.VoiceState]) VoiceStateCache {
+	byChannel := newGroupedCacheIndex(cache, func(voiceState discord.VoiceState) []snowflake.ID {
+		if voiceState.ChannelID != nil {
+			return []snowflake.ID{*voiceState.ChannelID}
+		}
+		return nil
+	}, func(voiceState discord.VoiceState) snowflake.ID {
+		return voiceState.UserID
+	})
+	if byChannel == nil {
+		return NewVoiceStateCache(cache)
+	}
+	return &indexedVoiceStateCacheImpl{
+		voiceStateCacheImpl: &voiceStateCacheImpl{
+			cache: cache,
+		},
+		byChannel: byChannel,
+	}
+}
+
 type voiceStateCacheImpl struct {
 	cache GroupedCache[discord.VoiceState]
 }
@@ -501,6 +669,10 @@ func (c *voiceStateCacheImpl) VoiceStatesAllLen() int {
 	return c.cache.Len()
 }
 
+func (c *voiceStateCacheImpl) Stats() Stats {
+	return cacheStats(c.cache)
+}
+
 func (c *voiceStateCacheImpl) VoiceStatesLen(guildID snowflake.ID) int {
 	return c.cache.GroupLen(guildID)
 }
@@ -517,6 +689,19 @@ func (c *voiceStateCacheImpl) RemoveVoiceStatesByGuildID(guildID snowflake.ID) {
 	c.cache.GroupRemove(guildID)
 }
 
+type indexedVoiceStateCacheImpl struct {
+	*voiceStateCacheImpl
+	byChannel Index[discord.VoiceState]
+}
+
+func (c *indexedVoiceStateCacheImpl) ChannelVoiceStatesForEach(channelID snowflake.ID, fn func(voiceState discord.VoiceState)) {
+	for _, ref := range c.byChannel.Refs(channelID) {
+		if voiceState, ok := c.cache.Get(ref.GroupID, ref.ID); ok && voiceState.ChannelID != nil && *voiceState.ChannelID == channelID {
+			fn(voiceState)
+		}
+	}
+}
+
 type MessageCache interface {
 	Message(channelID snowflake.ID, messageID snowflake.ID) (discord.Message, bool)
 	MessagesForEach(channelID snowflake.ID, fn func(message discord.Message))
@@ -550,6 +735,10 @@ func (c *messageCacheImpl) MessagesAllLen() int {
 	return c.cache.Len()
 }
 
+func (c *messageCacheImpl) Stats() Stats {
+	return cacheStats(c.cache)
+}
+
 func (c *messageCacheImpl) MessagesLen(guildID snowflake.ID) int {
 	return c.cache.GroupLen(guildID)
 }
@@ -604,6 +793,10 @@ func (c *emojiCacheImpl) EmojisAllLen() int {
 	return c.cache.Len()
 }
 
+func (c *emojiCacheImpl) Stats() Stats {
+	return cacheStats(c.cache)
+}
+
 func (c *emojiCacheImpl) EmojisLen(guildID snowflake.ID) int {
 	return c.cache.GroupLen(guildID)
 }
@@ -652,6 +845,10 @@ func (c *stickerCacheImpl) StickersAllLen() int {
 	return c.cache.Len()
 }
 
+func (c *stickerCacheImpl) Stats() Stats {
+	return cacheStats(c.cache)
+}
+
 func (c *stickerCacheImpl) StickersLen(guildID snowflake.ID) int {
 	return c.cache.GroupLen(guildID)
 }
@@ -776,6 +973,30 @@ func New(opts ...ConfigOpt) Caches {
 	}
 }
 
+// MemberPermissionsTrace returns the calculated permissions of the given member in the given channel or the guild if channel is nil,
+// together with the roles & overwrites which allowed or denied them. See discord.ComputePermissions.
+// This requires the FlagRoles and FlagChannels to be set.
+func MemberPermissionsTrace(caches Caches, channel discord.GuildChannel, member discord.Member) discord.PermissionTrace {
+	input := discord.PermissionInput{
+		GuildID: member.GuildID,
+		Member:  member,
+		Roles:   caches.MemberRoles(member),
+		Channel: channel,
+	}
+	if guild, ok := caches.Guild(member.GuildID); ok {
+		input.OwnerID = guild.OwnerID
+	}
+	if publicRole, ok := caches.Role(member.GuildID, member.GuildID); ok {
+		input.Roles = append(input.Roles, publicRole)
+	}
+	if thread, ok := channel.(discord.GuildThread); ok {
+		if parent, ok := caches.Channel(*thread.ParentID()); ok {
+			input.ParentChannel = parent
+		}
+	}
+	return discord.ComputePermissions(input)
+}
+
 type cachesImpl struct {
 	config Config
 
@@ -798,68 +1019,29 @@ func (c *cachesImpl) CacheFlags() Flags {
 	return c.config.CacheFlags
 }
 
-func (c *cachesImpl) MemberPermissions(member discord.Member) discord.Permissions {
-	if guild, ok := c.Guild(member.GuildID); ok && guild.OwnerID == member.User.ID {
-		return discord.PermissionsAll
-	}
-
-	var permissions discord.Permissions
-	if publicRole, ok := c.Role(member.GuildID, member.GuildID); ok {
-		permissions = publicRole.Permissions
+func (c *cachesImpl) Stats() map[string]Stats {
+	return map[string]Stats{
+		"guilds":                 subCacheStats(c.GuildCache, c.GuildsLen),
+		"channels":               subCacheStats(c.ChannelCache, c.ChannelsLen),
+		"stage_instances":        subCacheStats(c.StageInstanceCache, c.StageInstancesAllLen),
+		"guild_scheduled_events": subCacheStats(c.GuildScheduledEventCache, c.GuildScheduledEventsAllLen),
+		"roles":                  subCacheStats(c.RoleCache, c.RolesAllLen),
+		"members":                subCacheStats(c.MemberCache, c.MembersAllLen),
+		"thread_members":         subCacheStats(c.ThreadMemberCache, c.ThreadMembersAllLen),
+		"presences":              subCacheStats(c.PresenceCache, c.PresencesAllLen),
+		"voice_states":           subCacheStats(c.VoiceStateCache, c.VoiceStatesAllLen),
+		"messages":               subCacheStats(c.MessageCache, c.MessagesAllLen),
+		"emojis":                 subCacheStats(c.EmojiCache, c.EmojisAllLen),
+		"stickers":               subCacheStats(c.StickerCache, c.StickersAllLen),
 	}
+}
 
-	for _, role := range c.MemberRoles(member) {
-		permissions = permissions.Add(role.Permissions)
-		if permissions.Has(discord.PermissionAdministrator) {
-			return discord.PermissionsAll
-		}
-	}
-	if member.CommunicationDisabledUntil != nil && member.CommunicationDisabledUntil.After(time.Now()) {
-		permissions &= discord.PermissionViewChannel | discord.PermissionReadMessageHistory
-	}
-	return permissions
+func (c *cachesImpl) MemberPermissions(member discord.Member) discord.Permissions {
+	return MemberPermissionsTrace(c, nil, member).Permissions
 }
 
 func (c *cachesImpl) MemberPermissionsInChannel(channel discord.GuildChannel, member discord.Member) discord.Permissions {
-	permissions := c.MemberPermissions(member)
-	if permissions.Has(discord.PermissionAdministrator) {
-		return discord.PermissionsAll
-	}
-
-	var (
-		allow discord.Permissions
-		deny  discord.Permissions
-	)
-
-	if overwrite, ok := channel.PermissionOverwrites().Role(channel.GuildID()); ok {
-		permissions |= overwrite.Allow
-		permissions &= ^overwrite.Deny
-	}
-
-	for _, roleID := range member.RoleIDs {
-		if roleID == channel.GuildID() {
-			continue
-		}
-
-		if overwrite, ok := channel.PermissionOverwrites().Role(roleID); ok {
-			allow |= overwrite.Allow
-			deny |= overwrite.Deny
-		}
-	}
-
-	if overwrite, ok := channel.PermissionOverwrites().Member(member.User.ID); ok {
-		allow |= overwrite.Allow
-		deny |= overwrite.Deny
-	}
-
-	permissions &= ^deny
-	permissions |= allow
-
-	if member.CommunicationDisabledUntil != nil && member.CommunicationDisabledUntil.After(time.Now()) {
-		permissions &= discord.PermissionViewChannel | discord.PermissionReadMessageHistory
-	}
-
-	return permissions
+	return MemberPermissionsTrace(c, channel, member).Permissions
 }
 
 func (c *cachesImpl) MemberRoles(member discord.Member) []discord.Role {
@@ -876,11 +1058,16 @@ func (c *cachesImpl) MemberRoles(member discord.Member) []discord.Role {
 
 func (c *cachesImpl) AudioChannelMembers(channel discord.GuildAudioChannel) []discord.Member {
 	var members []discord.Member
-	c.VoiceStatesForEach(channel.GuildID(), func(state discord.VoiceState) {
+	fn := func(state discord.VoiceState) {
 		if member, ok := c.Member(channel.GuildID(), state.UserID); ok && state.ChannelID != nil && *state.ChannelID == channel.ID() {
 			members = append(members, member)
 		}
-	})
+	}
+	if indexer, ok := c.VoiceStateCache.(VoiceStateIndexer); ok {
+		indexer.ChannelVoiceStatesForEach(channel.ID(), fn)
+	} else {
+		c.VoiceStatesForEach(channel.GuildID(), fn)
+	}
 	return members
 }
 
@@ -894,11 +1081,16 @@ func (c *cachesImpl) SelfMember(guildID snowflake.ID) (discord.Member, bool) {
 
 func (c *cachesImpl) GuildThreadsInChannel(channelID snowflake.ID) []discord.GuildThread {
 	var threads []discord.GuildThread
-	c.ChannelsForEach(func(channel discord.GuildChannel) {
-		if thread, ok := channel.(discord.GuildThread); ok && *thread.ParentID() == channelID {
+	fn := func(channel discord.GuildChannel) {
+		if thread, ok := channel.(discord.GuildThread); ok && isChildChannel(thread, channelID) {
 			threads = append(threads, thread)
 		}
-	})
+	}
+	if indexer, ok := c.ChannelCache.(ChannelIndexer); ok {
+		indexer.ChildChannelsForEach(channelID, fn)
+	} else {
+		c.ChannelsForEach(fn)
+	}
 	return threads
 }
 
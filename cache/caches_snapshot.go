@@ -0,0 +1,197 @@
+package cache
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/disgoorg/json"
+	"github.com/disgoorg/snowflake/v2"
+
+	"github.com/disgoorg/disgo/discord"
+)
+
+// SnapshotVersion is the version of the format written by Snapshotter.Snapshot.
+// Snapshotter.Restore only accepts snapshots of the same version.
+const SnapshotVersion = 1
+
+// ErrUnsupportedSnapshotVersion is returned by Snapshotter.Restore when the snapshot was written in a different format.
+var ErrUnsupportedSnapshotVersion = errors.New("unsupported cache snapshot version")
+
+// Snapshotter is implemented by Caches which can write their content to a snapshot and restore it.
+// The Caches returned by New implement it, use a type assertion to check whether other Caches do.
+type Snapshotter interface {
+	// Snapshot writes the content of all caches including the unready & unavailable guilds to the given io.Writer.
+	// Grouped entities are only included for guilds & channels known to the caches, so direct messages are not part of the snapshot.
+	Snapshot(w io.Writer) error
+
+	// Restore adds all entities of a snapshot written by Snapshot to the caches.
+	// Entities already cached are overwritten, others are kept. Policies & Flags are applied as usual.
+	Restore(r io.Reader) error
+}
+
+var _ Snapshotter = (*cachesImpl)(nil)
+
+type snapshot struct {
+	Version              int                           `json:"version"`
+	SelfUser             *discord.OAuth2User           `json:"self_user,omitempty"`
+	Guilds               []discord.Guild               `json:"guilds"`
+	UnreadyGuilds        []snowflake.ID                `json:"unready_guilds"`
+	UnavailableGuilds    []snowflake.ID                `json:"unavailable_guilds"`
+	Channels             []discord.GuildChannel        `json:"channels"`
+	StageInstances       []discord.StageInstance       `json:"stage_instances"`
+	GuildScheduledEvents []discord.GuildScheduledEvent `json:"guild_scheduled_events"`
+	Roles                []discord.Role                `json:"roles"`
+	Members              []discord.Member              `json:"members"`
+	ThreadMembers        []discord.ThreadMember        `json:"thread_members"`
+	Presences            []discord.Presence            `json:"presences"`
+	VoiceStates          []discord.VoiceState          `json:"voice_states"`
+	Messages             []discord.Message             `json:"messages"`
+	Emojis               []discord.Emoji               `json:"emojis"`
+	Stickers             []discord.Sticker             `json:"stickers"`
+}
+
+func (s *snapshot) UnmarshalJSON(data []byte) error {
+	type snapshotAlias snapshot
+	var v struct {
+		Channels []discord.UnmarshalChannel `json:"channels"`
+		*snapshotAlias
+	}
+	v.snapshotAlias = (*snapshotAlias)(s)
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+
+	s.Channels = make([]discord.GuildChannel, 0, len(v.Channels))
+	for _, channel := range v.Channels {
+		if guildChannel, ok := channel.Channel.(discord.GuildChannel); ok {
+			s.Channels = append(s.Channels, guildChannel)
+		}
+	}
+	return nil
+}
+
+func (c *cachesImpl) Snapshot(w io.Writer) error {
+	s := snapshot{
+		Version:           SnapshotVersion,
+		UnreadyGuilds:     c.UnreadyGuildIDs(),
+		UnavailableGuilds: c.UnavailableGuildIDs(),
+	}
+	if selfUser, ok := c.SelfUser(); ok {
+		s.SelfUser = &selfUser
+	}
+
+	// grouped entities are collected per guild & channel, so all guild & channel IDs we know about are needed
+	guildIDs := map[snowflake.ID]struct{}{}
+	for _, guildID := range s.UnreadyGuilds {
+		guildIDs[guildID] = struct{}{}
+	}
+	for _, guildID := range s.UnavailableGuilds {
+		guildIDs[guildID] = struct{}{}
+	}
+	c.GuildsForEach(func(guild discord.Guild) {
+		s.Guilds = append(s.Guilds, guild)
+		guildIDs[guild.ID] = struct{}{}
+	})
+	c.ChannelsForEach(func(channel discord.GuildChannel) {
+		s.Channels = append(s.Channels, channel)
+		guildIDs[channel.GuildID()] = struct{}{}
+	})
+
+	for guildID := range guildIDs {
+		c.StageInstanceForEach(guildID, func(stageInstance discord.StageInstance) {
+			s.StageInstances = append(s.StageInstances, stageInstance)
+		})
+		c.GuildScheduledEventsForEach(guildID, func(guildScheduledEvent discord.GuildScheduledEvent) {
+			s.GuildScheduledEvents = append(s.GuildScheduledEvents, guildScheduledEvent)
+		})
+		c.RolesForEach(guildID, func(role discord.Role) {
+			s.Roles = append(s.Roles, role)
+		})
+		c.MembersForEach(guildID, func(member discord.Member) {
+			s.Members = append(s.Members, member)
+		})
+		c.PresenceForEach(guildID, func(presence discord.Presence) {
+			s.Presences = append(s.Presences, presence)
+		})
+		c.VoiceStatesForEach(guildID, func(voiceState discord.VoiceState) {
+			s.VoiceStates = append(s.VoiceStates, voiceState)
+		})
+		c.EmojisForEach(guildID, func(emoji discord.Emoji) {
+			s.Emojis = append(s.Emojis, emoji)
+		})
+		c.StickersForEach(guildID, func(sticker discord.Sticker) {
+			s.Stickers = append(s.Stickers, sticker)
+		})
+	}
+	for _, channel := range s.Channels {
+		c.ThreadMemberForEach(channel.ID(), func(threadMember discord.ThreadMember) {
+			s.ThreadMembers = append(s.ThreadMembers, threadMember)
+		})
+		c.MessagesForEach(channel.ID(), func(message discord.Message) {
+			s.Messages = append(s.Messages, message)
+		})
+	}
+
+	if err := json.NewEncoder(w).Encode(s); err != nil {
+		return fmt.Errorf("failed to write cache snapshot: %w", err)
+	}
+	return nil
+}
+
+func (c *cachesImpl) Restore(r io.Reader) error {
+	var s snapshot
+	if err := json.NewDecoder(r).Decode(&s); err != nil {
+		return fmt.Errorf("failed to read cache snapshot: %w", err)
+	}
+	if s.Version != SnapshotVersion {
+		return fmt.Errorf("%w: expected %d, got %d", ErrUnsupportedSnapshotVersion, SnapshotVersion, s.Version)
+	}
+
+	if s.SelfUser != nil {
+		c.SetSelfUser(*s.SelfUser)
+	}
+	for _, guild := range s.Guilds {
+		c.AddGuild(guild)
+	}
+	for _, guildID := range s.UnreadyGuilds {
+		c.SetGuildUnready(guildID, true)
+	}
+	for _, guildID := range s.UnavailableGuilds {
+		c.SetGuildUnavailable(guildID, true)
+	}
+	for _, channel := range s.Channels {
+		c.AddChannel(channel)
+	}
+	for _, stageInstance := range s.StageInstances {
+		c.AddStageInstance(stageInstance)
+	}
+	for _, guildScheduledEvent := range s.GuildScheduledEvents {
+		c.AddGuildScheduledEvent(guildScheduledEvent)
+	}
+	for _, role := range s.Roles {
+		c.AddRole(role)
+	}
+	for _, member := range s.Members {
+		c.AddMember(member)
+	}
+	for _, threadMember := range s.ThreadMembers {
+		c.AddThreadMember(threadMember)
+	}
+	for _, presence := range s.Presences {
+		c.AddPresence(presence)
+	}
+	for _, voiceState := range s.VoiceStates {
+		c.AddVoiceState(voiceState)
+	}
+	for _, message := range s.Messages {
+		c.AddMessage(message)
+	}
+	for _, emoji := range s.Emojis {
+		c.AddEmoji(emoji)
+	}
+	for _, sticker := range s.Stickers {
+		c.AddSticker(sticker)
+	}
+	return nil
+}
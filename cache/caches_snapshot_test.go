@@ -0,0 +1,57 @@
+package cache
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/disgoorg/json"
+	"github.com/disgoorg/snowflake/v2"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/disgoorg/disgo/discord"
+)
+
+func TestCachesSnapshotRestore(t *testing.T) {
+	var channel discord.UnmarshalChannel
+	err := json.Unmarshal([]byte(`{"id":"10","guild_id":"1","type":0,"name":"general"}`), &channel)
+	assert.NoError(t, err)
+
+	caches := New(WithCaches(FlagsAll))
+	caches.AddGuild(discord.Guild{ID: 1, Name: "guild"})
+	caches.SetGuildUnready(2, true)
+	caches.SetGuildUnavailable(3, true)
+	caches.AddChannel(channel.Channel.(discord.GuildChannel))
+	caches.AddRole(discord.Role{ID: 20, GuildID: 1, Name: "role"})
+	caches.AddMember(discord.Member{GuildID: 1, User: discord.User{ID: 30}})
+	caches.AddMessage(discord.Message{ID: 40, ChannelID: 10, Content: "hello"})
+
+	buf := &bytes.Buffer{}
+	assert.NoError(t, caches.(Snapshotter).Snapshot(buf))
+
+	restored := New(WithCaches(FlagsAll))
+	assert.NoError(t, restored.(Snapshotter).Restore(buf))
+
+	guild, ok := restored.Guild(1)
+	assert.True(t, ok)
+	assert.Equal(t, "guild", guild.Name)
+	assert.True(t, restored.IsGuildUnready(2))
+	assert.True(t, restored.IsGuildUnavailable(3))
+
+	textChannel, ok := restored.GuildTextChannel(10)
+	assert.True(t, ok)
+	assert.Equal(t, "general", textChannel.Name())
+
+	_, ok = restored.Role(1, 20)
+	assert.True(t, ok)
+	_, ok = restored.Member(1, 30)
+	assert.True(t, ok)
+	message, ok := restored.Message(10, 40)
+	assert.True(t, ok)
+	assert.Equal(t, "hello", message.Content)
+	assert.Equal(t, snowflake.ID(10), message.ChannelID)
+}
+
+func TestCachesRestoreUnsupportedVersion(t *testing.T) {
+	err := New().(Snapshotter).Restore(bytes.NewBufferString(`{"version":0}`))
+	assert.ErrorIs(t, err, ErrUnsupportedSnapshotVersion)
+}
@@ -0,0 +1,19 @@
+package cache
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGuildCacheSetGuildUnavailable(t *testing.T) {
+	caches := New(WithCaches(FlagGuilds))
+
+	caches.SetGuildUnavailable(1, true)
+	assert.True(t, caches.IsGuildUnavailable(1))
+	assert.Len(t, caches.UnavailableGuildIDs(), 1)
+
+	caches.SetGuildUnavailable(1, false)
+	assert.False(t, caches.IsGuildUnavailable(1))
+	assert.Empty(t, caches.UnavailableGuildIDs())
+}
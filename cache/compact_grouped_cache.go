@@ -0,0 +1,278 @@
+package cache
+
+import (
+	"sync"
+
+	"github.com/disgoorg/snowflake/v2"
+)
+
+var (
+	_ GroupedCache[any] = (*compactGroupedCache[any, any])(nil)
+	_ Observable[any]   = (*compactGroupedCache[any, any])(nil)
+)
+
+// newCompactGroupedCache returns a GroupedCache which stores its entities as C using the given pack & unpack functions.
+// Entities are unpacked on every read, so they should be cheap to reconstruct.
+// The optional release function is called with each packed entity which is removed or replaced.
+func newCompactGroupedCache[T any, C any](flags Flags, neededFlags Flags, policy Policy[T], pack func(entity T) C, unpack func(groupID snowflake.ID, id snowflake.ID, packed C) T, release func(packed C)) GroupedCache[T] {
+	return &compactGroupedCache[T, C]{
+		flags:       flags,
+		neededFlags: neededFlags,
+		policy:      policy,
+		pack:        pack,
+		unpack:      unpack,
+		release:     release,
+		cache:       make(map[snowflake.ID]map[snowflake.ID]C),
+	}
+}
+
+type compactGroupedCache[T any, C any] struct {
+	mu          sync.RWMutex
+	flags       Flags
+	neededFlags Flags
+	policy      Policy[T]
+	pack        func(entity T) C
+	unpack      func(groupID snowflake.ID, id snowflake.ID, packed C) T
+	release     func(packed C)
+	cache       map[snowflake.ID]map[snowflake.ID]C
+	observers   Observers[T]
+	stats       statsCounter
+}
+
+func (c *compactGroupedCache[T, C]) Get(groupID snowflake.ID, id snowflake.ID) (T, bool) {
+	c.mu.RLock()
+	packed, ok := c.cache[groupID][id]
+	c.mu.RUnlock()
+
+	c.stats.get(ok)
+	if !ok {
+		var entity T
+		return entity, false
+	}
+	return c.unpack(groupID, id, packed), true
+}
+
+func (c *compactGroupedCache[T, C]) Put(groupID snowflake.ID, id snowflake.ID, entity T) {
+	if c.flags.Missing(c.neededFlags) {
+		return
+	}
+	if c.policy != nil && !c.policy(entity) {
+		c.stats.policyRejections.Add(1)
+		return
+	}
+	c.stats.puts.Add(1)
+	packed := c.pack(entity)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	groupEntities, ok := c.cache[groupID]
+	if !ok {
+		groupEntities = make(map[snowflake.ID]C)
+		c.cache[groupID] = groupEntities
+	}
+	oldPacked, ok := groupEntities[id]
+	groupEntities[id] = packed
+
+	if ok {
+		defer c.releasePacked(oldPacked)
+	}
+
+	if c.observers.Len() == 0 {
+		return
+	}
+	var oldEntity T
+	if ok {
+		oldEntity = c.unpack(groupID, id, oldPacked)
+	}
+	c.observers.Notify(PutChange(groupID, id, oldEntity, ok, c.unpack(groupID, id, packed)))
+}
+
+func (c *compactGroupedCache[T, C]) Remove(groupID snowflake.ID, id snowflake.ID) (T, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	packed, ok := c.cache[groupID][id]
+	if ok {
+		delete(c.cache[groupID], id)
+	}
+
+	if !ok {
+		var entity T
+		return entity, false
+	}
+	c.stats.removes.Add(1)
+	entity := c.unpack(groupID, id, packed)
+	c.releasePacked(packed)
+	c.observers.Notify(RemoveChange(groupID, id, entity))
+	return entity, true
+}
+
+func (c *compactGroupedCache[T, C]) GroupRemove(groupID snowflake.ID) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	groupEntities := c.cache[groupID]
+	delete(c.cache, groupID)
+
+	c.stats.removes.Add(uint64(len(groupEntities)))
+	var changes []Change[T]
+	if c.observers.Len() > 0 {
+		changes = make([]Change[T], 0, len(groupEntities))
+	}
+	for id, packed := range groupEntities {
+		if changes != nil {
+			changes = append(changes, RemoveChange(groupID, id, c.unpack(groupID, id, packed)))
+		}
+		c.releasePacked(packed)
+	}
+	c.observers.Notify(changes...)
+}
+
+func (c *compactGroupedCache[T, C]) RemoveIf(filterFunc GroupedFilterFunc[T]) {
+	var changes []Change[T]
+
+	c.mu.Lock()
+	for groupID := range c.cache {
+		changes = c.groupRemoveIf(groupID, filterFunc, changes)
+	}
+	c.observers.Notify(changes...)
+	c.mu.Unlock()
+}
+
+func (c *compactGroupedCache[T, C]) GroupRemoveIf(groupID snowflake.ID, filterFunc GroupedFilterFunc[T]) {
+	c.mu.Lock()
+	changes := c.groupRemoveIf(groupID, filterFunc, nil)
+	c.observers.Notify(changes...)
+	c.mu.Unlock()
+}
+
+// groupRemoveIf removes all entities of the group passing the filterFunc and appends their changes. It must be called with mu held.
+func (c *compactGroupedCache[T, C]) groupRemoveIf(groupID snowflake.ID, filterFunc GroupedFilterFunc[T], changes []Change[T]) []Change[T] {
+	for id, packed := range c.cache[groupID] {
+		entity := c.unpack(groupID, id, packed)
+		if filterFunc(groupID, entity) {
+			delete(c.cache[groupID], id)
+			c.stats.removes.Add(1)
+			c.releasePacked(packed)
+			changes = append(changes, RemoveChange(groupID, id, entity))
+		}
+	}
+	return changes
+}
+
+func (c *compactGroupedCache[T, C]) releasePacked(packed C) {
+	if c.release != nil {
+		c.release(packed)
+	}
+}
+
+func (c *compactGroupedCache[T, C]) Len() int {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	var totalLen int
+	for _, groupEntities := range c.cache {
+		totalLen += len(groupEntities)
+	}
+	return totalLen
+}
+
+func (c *compactGroupedCache[T, C]) GroupLen(groupID snowflake.ID) int {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return len(c.cache[groupID])
+}
+
+func (c *compactGroupedCache[T, C]) ForEach(forEachFunc func(groupID snowflake.ID, entity T)) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	for groupID, groupEntities := range c.cache {
+		for id, packed := range groupEntities {
+			forEachFunc(groupID, c.unpack(groupID, id, packed))
+		}
+	}
+}
+
+func (c *compactGroupedCache[T, C]) GroupForEach(groupID snowflake.ID, forEachFunc func(entity T)) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	for id, packed := range c.cache[groupID] {
+		forEachFunc(c.unpack(groupID, id, packed))
+	}
+}
+
+func (c *compactGroupedCache[T, C]) AddObserver(observer Observer[T]) func() {
+	return c.observers.Add(observer)
+}
+
+// Stats estimates the size of the packed entities as that is what is actually stored.
+func (c *compactGroupedCache[T, C]) Stats() Stats {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	var (
+		entities int
+		sampler  sizeSampler
+		sampling = true
+	)
+	for _, groupEntities := range c.cache {
+		entities += len(groupEntities)
+		for _, packed := range groupEntities {
+			if !sampling {
+				break
+			}
+			sampling = sampler.sample(packed)
+		}
+	}
+	return c.stats.stats(entities, sampler.estimate(entities))
+}
+
+// interner deduplicates values which are equal but stored in separate allocations.
+// Values are reference counted and released once the last reference to them is released.
+type interner[K comparable, V any] struct {
+	mu     sync.Mutex
+	values map[K]*internedValue[V]
+}
+
+type internedValue[V any] struct {
+	value V
+	refs  int
+}
+
+// acquire returns the interned value for the given key or stores the value returned by newValue.
+// Each call must be paired with a call to release once the value is no longer used.
+func (i *interner[K, V]) acquire(key K, newValue func() V) V {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	interned, ok := i.values[key]
+	if !ok {
+		if i.values == nil {
+			i.values = map[K]*internedValue[V]{}
+		}
+		interned = &internedValue[V]{value: newValue()}
+		i.values[key] = interned
+	}
+	interned.refs++
+	return interned.value
+}
+
+// release releases a reference to the interned value for the given key and removes it if it was the last one.
+func (i *interner[K, V]) release(key K) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	interned, ok := i.values[key]
+	if !ok {
+		return
+	}
+	interned.refs--
+	if interned.refs <= 0 {
+		delete(i.values, key)
+	}
+}
+
+// len returns the number of interned values.
+func (i *interner[K, V]) len() int {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	return len(i.values)
+}
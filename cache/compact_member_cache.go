@@ -0,0 +1,334 @@
+package cache
+
+import (
+	"encoding/binary"
+	"math"
+	"slices"
+	"time"
+
+	"github.com/disgoorg/snowflake/v2"
+
+	"github.com/disgoorg/disgo/discord"
+)
+
+// MemberFields selects which fields of a discord.Member a compact MemberCache keeps.
+// Fields which are not kept are returned as their zero value.
+type MemberFields int
+
+const (
+	// MemberFieldUser keeps the discord.User of the member. Without it only the user ID is kept.
+	MemberFieldUser MemberFields = 1 << iota
+	// MemberFieldNick keeps discord.Member.Nick.
+	MemberFieldNick
+	// MemberFieldAssets keeps discord.Member.Avatar, discord.Member.Banner & discord.Member.AvatarDecorationData.
+	MemberFieldAssets
+	// MemberFieldRoles keeps discord.Member.RoleIDs.
+	MemberFieldRoles
+	// MemberFieldJoinedAt keeps discord.Member.JoinedAt.
+	MemberFieldJoinedAt
+	// MemberFieldPremiumSince keeps discord.Member.PremiumSince.
+	MemberFieldPremiumSince
+	// MemberFieldVoice keeps discord.Member.Deaf & discord.Member.Mute.
+	MemberFieldVoice
+	// MemberFieldFlags keeps discord.Member.Flags & discord.Member.Pending.
+	MemberFieldFlags
+	// MemberFieldTimeout keeps discord.Member.CommunicationDisabledUntil.
+	MemberFieldTimeout
+
+	// MemberFieldsAll keeps all fields of a discord.Member.
+	MemberFieldsAll = MemberFieldUser | MemberFieldNick | MemberFieldAssets | MemberFieldRoles | MemberFieldJoinedAt |
+		MemberFieldPremiumSince | MemberFieldVoice | MemberFieldFlags | MemberFieldTimeout
+)
+
+// Has returns true if all given MemberFields are set.
+func (f MemberFields) Has(fields MemberFields) bool {
+	return f&fields == fields
+}
+
+// NewCompactMemberCache returns a MemberCache which stores only the given MemberFields in a memory-compact form.
+// All strings of a member are stored in a single allocation & identical role ID sets are shared between members.
+func NewCompactMemberCache(flags Flags, policy Policy[discord.Member], fields MemberFields) MemberCache {
+	return NewMemberCache(newCompactMemberGroupedCache(flags, policy, fields))
+}
+
+func newCompactMemberGroupedCache(flags Flags, policy Policy[discord.Member], fields MemberFields) GroupedCache[discord.Member] {
+	p := &memberPacker{fields: fields}
+	return newCompactGroupedCache[discord.Member, compactMember](flags, FlagMembers, policy, p.pack, p.unpack, p.release)
+}
+
+// compact member strings are concatenated into compactMember.strings in this order
+const (
+	compactStringUsername = iota
+	compactStringDiscriminator
+	compactStringGlobalName
+	compactStringUserAvatar
+	compactStringUserBanner
+	compactStringNick
+	compactStringAvatar
+	compactStringBanner
+	compactStringCount
+)
+
+const (
+	compactMemberHasGlobalName uint32 = 1 << iota
+	compactMemberHasUserAvatar
+	compactMemberHasUserBanner
+	compactMemberHasAccentColor
+	compactMemberBot
+	compactMemberSystem
+	compactMemberHasNick
+	compactMemberHasAvatar
+	compactMemberHasBanner
+	compactMemberHasPremiumSince
+	compactMemberHasTimeout
+	compactMemberDeaf
+	compactMemberMute
+	compactMemberPending
+)
+
+type compactMember struct {
+	// strings holds all strings of the member in a single allocation, their lengths are stored in lengths
+	strings string
+	lengths [compactStringCount]uint8
+	// longStrings holds the strings which are too long for lengths. It is nil if there are none.
+	longStrings  *[compactStringCount]string
+	roleIDs      *[]snowflake.ID
+	decorations  *compactAvatarDecorations
+	joinedAt     int64
+	premiumSince int64
+	timeoutUntil int64
+	accentColor  int32
+	publicFlags  uint32
+	flags        uint32
+	bits         uint32
+}
+
+// compactAvatarDecorations is stored separately as few members have avatar decorations.
+type compactAvatarDecorations struct {
+	user   *discord.AvatarDecorationData
+	member *discord.AvatarDecorationData
+}
+
+type memberPacker struct {
+	fields  MemberFields
+	roleIDs interner[string, *[]snowflake.ID]
+}
+
+func (p *memberPacker) pack(member discord.Member) compactMember {
+	var (
+		m       compactMember
+		strings [compactStringCount]string
+	)
+	setString := func(i int, s *string, bit uint32) {
+		if s != nil {
+			strings[i] = *s
+			m.bits |= bit
+		}
+	}
+
+	// the user ID is the key of the cache, so we don't need to keep it
+	if p.fields.Has(MemberFieldUser) {
+		user := member.User
+		strings[compactStringUsername] = user.Username
+		strings[compactStringDiscriminator] = user.Discriminator
+		setString(compactStringGlobalName, user.GlobalName, compactMemberHasGlobalName)
+		setString(compactStringUserAvatar, user.Avatar, compactMemberHasUserAvatar)
+		setString(compactStringUserBanner, user.Banner, compactMemberHasUserBanner)
+		if user.AccentColor != nil {
+			m.accentColor = int32(*user.AccentColor)
+			m.bits |= compactMemberHasAccentColor
+		}
+		if user.Bot {
+			m.bits |= compactMemberBot
+		}
+		if user.System {
+			m.bits |= compactMemberSystem
+		}
+		m.publicFlags = uint32(user.PublicFlags)
+		if user.AvatarDecorationData != nil {
+			m.decorations = &compactAvatarDecorations{user: user.AvatarDecorationData}
+		}
+	}
+	if p.fields.Has(MemberFieldNick) {
+		setString(compactStringNick, member.Nick, compactMemberHasNick)
+	}
+	if p.fields.Has(MemberFieldAssets) {
+		setString(compactStringAvatar, member.Avatar, compactMemberHasAvatar)
+		setString(compactStringBanner, member.Banner, compactMemberHasBanner)
+		if member.AvatarDecorationData != nil {
+			if m.decorations == nil {
+				m.decorations = &compactAvatarDecorations{}
+			}
+			m.decorations.member = member.AvatarDecorationData
+		}
+	}
+	if p.fields.Has(MemberFieldRoles) {
+		m.roleIDs = p.packRoleIDs(member.RoleIDs)
+	}
+	if p.fields.Has(MemberFieldJoinedAt) {
+		m.joinedAt = packTime(member.JoinedAt)
+	}
+	if p.fields.Has(MemberFieldPremiumSince) && member.PremiumSince != nil {
+		m.premiumSince = packTime(*member.PremiumSince)
+		m.bits |= compactMemberHasPremiumSince
+	}
+	if p.fields.Has(MemberFieldVoice) {
+		if member.Deaf {
+			m.bits |= compactMemberDeaf
+		}
+		if member.Mute {
+			m.bits |= compactMemberMute
+		}
+	}
+	if p.fields.Has(MemberFieldFlags) {
+		m.flags = uint32(member.Flags)
+		if member.Pending {
+			m.bits |= compactMemberPending
+		}
+	}
+	if p.fields.Has(MemberFieldTimeout) && member.CommunicationDisabledUntil != nil {
+		m.timeoutUntil = packTime(*member.CommunicationDisabledUntil)
+		m.bits |= compactMemberHasTimeout
+	}
+
+	var length int
+	for i, str := range strings {
+		// strings of a member are limited by Discord to well below 256 bytes, so longer ones are stored separately
+		if len(str) > math.MaxUint8 {
+			if m.longStrings == nil {
+				m.longStrings = &[compactStringCount]string{}
+			}
+			m.longStrings[i] = str
+			strings[i] = ""
+			continue
+		}
+		m.lengths[i] = uint8(len(str))
+		length += len(str)
+	}
+	if length > 0 {
+		buf := make([]byte, 0, length)
+		for _, str := range strings {
+			buf = append(buf, str...)
+		}
+		m.strings = string(buf)
+	}
+	return m
+}
+
+func (p *memberPacker) unpack(guildID snowflake.ID, userID snowflake.ID, m compactMember) discord.Member {
+	var (
+		strings [compactStringCount]string
+		offset  int
+	)
+	for i, length := range m.lengths {
+		strings[i] = m.strings[offset : offset+int(length)]
+		offset += int(length)
+	}
+	if m.longStrings != nil {
+		for i, str := range m.longStrings {
+			if str != "" {
+				strings[i] = str
+			}
+		}
+	}
+	getString := func(i int, bit uint32) *string {
+		if m.bits&bit == 0 {
+			return nil
+		}
+		return &strings[i]
+	}
+
+	member := discord.Member{
+		User: discord.User{
+			ID:            userID,
+			Username:      strings[compactStringUsername],
+			Discriminator: strings[compactStringDiscriminator],
+			GlobalName:    getString(compactStringGlobalName, compactMemberHasGlobalName),
+			Avatar:        getString(compactStringUserAvatar, compactMemberHasUserAvatar),
+			Banner:        getString(compactStringUserBanner, compactMemberHasUserBanner),
+			Bot:           m.bits&compactMemberBot != 0,
+			System:        m.bits&compactMemberSystem != 0,
+			PublicFlags:   discord.UserFlags(m.publicFlags),
+		},
+		Nick:     getString(compactStringNick, compactMemberHasNick),
+		Avatar:   getString(compactStringAvatar, compactMemberHasAvatar),
+		Banner:   getString(compactStringBanner, compactMemberHasBanner),
+		JoinedAt: unpackTime(m.joinedAt),
+		Deaf:     m.bits&compactMemberDeaf != 0,
+		Mute:     m.bits&compactMemberMute != 0,
+		Flags:    discord.MemberFlags(m.flags),
+		Pending:  m.bits&compactMemberPending != 0,
+		GuildID:  guildID,
+	}
+	if m.bits&compactMemberHasAccentColor != 0 {
+		accentColor := int(m.accentColor)
+		member.User.AccentColor = &accentColor
+	}
+	if m.decorations != nil {
+		member.User.AvatarDecorationData = copyPtr(m.decorations.user)
+		member.AvatarDecorationData = copyPtr(m.decorations.member)
+	}
+	if m.roleIDs != nil {
+		// role IDs are shared between members, so callers get their own copy
+		member.RoleIDs = slices.Clone(*m.roleIDs)
+	}
+	if m.bits&compactMemberHasPremiumSince != 0 {
+		premiumSince := unpackTime(m.premiumSince)
+		member.PremiumSince = &premiumSince
+	}
+	if m.bits&compactMemberHasTimeout != 0 {
+		timeoutUntil := unpackTime(m.timeoutUntil)
+		member.CommunicationDisabledUntil = &timeoutUntil
+	}
+	return member
+}
+
+// packRoleIDs returns a shared slice for each distinct set of role IDs as most members have the same few roles.
+func (p *memberPacker) packRoleIDs(roleIDs []snowflake.ID) *[]snowflake.ID {
+	if len(roleIDs) == 0 {
+		return nil
+	}
+	return p.roleIDs.acquire(roleIDsKey(roleIDs), func() *[]snowflake.ID {
+		packed := slices.Clone(roleIDs)
+		return &packed
+	})
+}
+
+// release releases the shared role IDs of the given packed member.
+func (p *memberPacker) release(m compactMember) {
+	if m.roleIDs != nil {
+		p.roleIDs.release(roleIDsKey(*m.roleIDs))
+	}
+}
+
+func roleIDsKey(roleIDs []snowflake.ID) string {
+	key := make([]byte, 8*len(roleIDs))
+	for i, roleID := range roleIDs {
+		binary.LittleEndian.PutUint64(key[i*8:], uint64(roleID))
+	}
+	return string(key)
+}
+
+// copyPtr returns a pointer to a copy of the given value or nil, so callers can't modify cached values.
+func copyPtr[T any](v *T) *T {
+	if v == nil {
+		return nil
+	}
+	c := *v
+	return &c
+}
+
+// packTime returns the given time as unix nanoseconds. The zero time is packed as 0.
+func packTime(t time.Time) int64 {
+	if t.IsZero() {
+		return 0
+	}
+	return t.UnixNano()
+}
+
+func unpackTime(t int64) time.Time {
+	if t == 0 {
+		return time.Time{}
+	}
+	return time.Unix(0, t).UTC()
+}
@@ -0,0 +1,156 @@
+package cache
+
+import (
+	"fmt"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/disgoorg/snowflake/v2"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/disgoorg/disgo/discord"
+)
+
+func testMember(guildID snowflake.ID, userID snowflake.ID) discord.Member {
+	username := fmt.Sprintf("user%d", userID)
+	globalName := fmt.Sprintf("User %d", userID)
+	avatar := fmt.Sprintf("%032x", uint64(userID))
+	accentColor := 0xff00ff
+	premiumSince := time.Unix(1700000000, 0).UTC()
+	return discord.Member{
+		User: discord.User{
+			ID:            userID,
+			Username:      username,
+			Discriminator: "0",
+			GlobalName:    &globalName,
+			Avatar:        &avatar,
+			AccentColor:   &accentColor,
+			PublicFlags:   discord.UserFlagDiscordEmployee,
+		},
+		RoleIDs:      []snowflake.ID{100, 101 + userID%3},
+		JoinedAt:     time.Unix(1600000000, 0).UTC(),
+		PremiumSince: &premiumSince,
+		Flags:        discord.MemberFlagCompletedOnboarding,
+		GuildID:      guildID,
+	}
+}
+
+func TestCompactMemberCache(t *testing.T) {
+	c := NewCompactMemberCache(FlagsAll, nil, MemberFieldsAll)
+	member := testMember(1, 10)
+	c.AddMember(member)
+
+	cached, ok := c.Member(1, 10)
+	assert.True(t, ok)
+	assert.Equal(t, member, cached)
+
+	indexed := NewIndexedMemberCache(newCompactMemberGroupedCache(FlagsAll, nil, MemberFieldsAll))
+	indexed.AddMember(member)
+
+	var roleMembers []snowflake.ID
+	indexed.(MemberIndexer).RoleMembersForEach(100, func(member discord.Member) {
+		roleMembers = append(roleMembers, member.User.ID)
+	})
+	assert.Equal(t, []snowflake.ID{10}, roleMembers)
+}
+
+func TestCompactMemberCacheFields(t *testing.T) {
+	c := NewCompactMemberCache(FlagsAll, nil, MemberFieldRoles)
+	c.AddMember(testMember(1, 10))
+
+	cached, ok := c.Member(1, 10)
+	assert.True(t, ok)
+	assert.Equal(t, discord.Member{
+		User:    discord.User{ID: 10},
+		RoleIDs: []snowflake.ID{100, 102},
+		GuildID: 1,
+	}, cached)
+}
+
+func TestCompactMemberCacheRoleIDs(t *testing.T) {
+	p := &memberPacker{fields: MemberFieldsAll}
+	c := NewMemberCache(newCompactGroupedCache[discord.Member, compactMember](FlagsAll, FlagMembers, nil, p.pack, p.unpack, p.release))
+	c.AddMember(testMember(1, 10))
+	c.AddMember(testMember(1, 13))
+	c.AddMember(testMember(1, 11))
+	assert.Equal(t, 2, p.roleIDs.len())
+
+	// role IDs are copied on read, so modifying them doesn't affect other members
+	cached, _ := c.Member(1, 10)
+	cached.RoleIDs[0] = 1
+	cached, _ = c.Member(1, 13)
+	assert.Equal(t, []snowflake.ID{100, 102}, cached.RoleIDs)
+
+	c.RemoveMember(1, 10)
+	c.AddMember(discord.Member{GuildID: 1, User: discord.User{ID: 11}})
+	assert.Equal(t, 1, p.roleIDs.len())
+	c.RemoveMembersByGuildID(1)
+	assert.Equal(t, 0, p.roleIDs.len())
+}
+
+func TestCompactMemberCacheLongStrings(t *testing.T) {
+	c := NewCompactMemberCache(FlagsAll, nil, MemberFieldsAll)
+	member := testMember(1, 10)
+	nick := strings.Repeat("a", 300)
+	member.Nick = &nick
+	c.AddMember(member)
+
+	cached, ok := c.Member(1, 10)
+	assert.True(t, ok)
+	assert.Equal(t, member, cached)
+}
+
+func TestCompactPresenceCache(t *testing.T) {
+	c := NewCompactPresenceCache(FlagsAll, nil, PresenceFieldsAll)
+	presence := discord.Presence{
+		PresenceUser: discord.PresenceUser{ID: 10},
+		GuildID:      1,
+		Status:       discord.OnlineStatusDND,
+		Activities:   []discord.Activity{{ID: "custom", Name: "Custom Status", Type: discord.ActivityTypeGame}},
+		ClientStatus: discord.ClientStatus{Desktop: discord.OnlineStatusDND},
+	}
+	c.AddPresence(presence)
+
+	cached, ok := c.Presence(1, 10)
+	assert.True(t, ok)
+	assert.Equal(t, presence, cached)
+}
+
+// BenchmarkMemberCacheMemory reports the heap used per cached member.
+func BenchmarkMemberCacheMemory(b *testing.B) {
+	const members = 100_000
+	caches := map[string]func() MemberCache{
+		"default": func() MemberCache {
+			return NewMemberCache(NewGroupedCache[discord.Member](FlagsAll, FlagsNone, nil))
+		},
+		"compact_all": func() MemberCache {
+			return NewCompactMemberCache(FlagsAll, nil, MemberFieldsAll)
+		},
+		"compact_roles": func() MemberCache {
+			return NewCompactMemberCache(FlagsAll, nil, MemberFieldRoles)
+		},
+	}
+	for name, newCache := range caches {
+		b.Run(name, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				before := heapAlloc()
+				c := newCache()
+				for userID := snowflake.ID(1); userID <= members; userID++ {
+					c.AddMember(testMember(1, userID))
+				}
+				after := heapAlloc()
+				b.ReportMetric(float64(after-before)/members, "B/member")
+				runtime.KeepAlive(c)
+			}
+		})
+	}
+}
+
+func heapAlloc() uint64 {
+	runtime.GC()
+	var stats runtime.MemStats
+	runtime.ReadMemStats(&stats)
+	return stats.HeapAlloc
+}
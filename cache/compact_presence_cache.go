@@ -0,0 +1,96 @@
+package cache
+
+import (
+	"slices"
+
+	"github.com/disgoorg/snowflake/v2"
+
+	"github.com/disgoorg/disgo/discord"
+)
+
+// PresenceFields selects which fields of a discord.Presence a compact PresenceCache keeps.
+// Fields which are not kept are returned as their zero value.
+type PresenceFields int
+
+const (
+	// PresenceFieldStatus keeps discord.Presence.Status.
+	PresenceFieldStatus PresenceFields = 1 << iota
+	// PresenceFieldClientStatus keeps discord.Presence.ClientStatus.
+	PresenceFieldClientStatus
+	// PresenceFieldActivities keeps discord.Presence.Activities.
+	PresenceFieldActivities
+
+	// PresenceFieldsAll keeps all fields of a discord.Presence.
+	PresenceFieldsAll = PresenceFieldStatus | PresenceFieldClientStatus | PresenceFieldActivities
+)
+
+// Has returns true if all given PresenceFields are set.
+func (f PresenceFields) Has(fields PresenceFields) bool {
+	return f&fields == fields
+}
+
+// NewCompactPresenceCache returns a PresenceCache which stores only the given PresenceFields in a memory-compact form.
+// Statuses are packed into single bytes.
+func NewCompactPresenceCache(flags Flags, policy Policy[discord.Presence], fields PresenceFields) PresenceCache {
+	p := &presencePacker{fields: fields}
+	return NewPresenceCache(newCompactGroupedCache[discord.Presence, compactPresence](flags, FlagPresences, policy, p.pack, p.unpack, nil))
+}
+
+// onlineStatuses maps the packed index of a discord.OnlineStatus to it. Unknown statuses are packed as "".
+var onlineStatuses = []discord.OnlineStatus{
+	"",
+	discord.OnlineStatusOnline,
+	discord.OnlineStatusDND,
+	discord.OnlineStatusIdle,
+	discord.OnlineStatusInvisible,
+	discord.OnlineStatusOffline,
+}
+
+type compactPresence struct {
+	activities []discord.Activity
+	status     uint8
+	desktop    uint8
+	mobile     uint8
+	web        uint8
+}
+
+type presencePacker struct {
+	fields PresenceFields
+}
+
+func (p *presencePacker) pack(presence discord.Presence) compactPresence {
+	var c compactPresence
+	if p.fields.Has(PresenceFieldStatus) {
+		c.status = packOnlineStatus(presence.Status)
+	}
+	if p.fields.Has(PresenceFieldClientStatus) {
+		c.desktop = packOnlineStatus(presence.ClientStatus.Desktop)
+		c.mobile = packOnlineStatus(presence.ClientStatus.Mobile)
+		c.web = packOnlineStatus(presence.ClientStatus.Web)
+	}
+	if p.fields.Has(PresenceFieldActivities) && len(presence.Activities) > 0 {
+		c.activities = slices.Clone(presence.Activities)
+	}
+	return c
+}
+
+func (p *presencePacker) unpack(guildID snowflake.ID, userID snowflake.ID, c compactPresence) discord.Presence {
+	return discord.Presence{
+		PresenceUser: discord.PresenceUser{ID: userID},
+		GuildID:      guildID,
+		Status:       onlineStatuses[c.status],
+		Activities:   slices.Clone(c.activities),
+		ClientStatus: discord.ClientStatus{
+			Desktop: onlineStatuses[c.desktop],
+			Mobile:  onlineStatuses[c.mobile],
+			Web:     onlineStatuses[c.web],
+		},
+	}
+}
+
+func packOnlineStatus(status discord.OnlineStatus) uint8 {
+	if i := slices.Index(onlineStatuses, status); i > 0 {
+		return uint8(i)
+	}
+	return 0
+}
@@ -43,7 +43,10 @@ type GroupedCache[T any] interface {
 	GroupForEach(groupID snowflake.ID, forEachFunc func(entity T))
 }
 
-var _ GroupedCache[any] = (*defaultGroupedCache[any])(nil)
+var (
+	_ GroupedCache[any] = (*defaultGroupedCache[any])(nil)
+	_ Observable[any]   = (*defaultGroupedCache[any])(nil)
+)
 
 // NewGroupedCache returns a new default GroupedCache with the provided flags, neededFlags and policy.
 func NewGroupedCache[T any](flags Flags, neededFlags Flags, policy Policy[T]) GroupedCache[T] {
@@ -61,20 +64,17 @@ type defaultGroupedCache[T any] struct {
 	neededFlags Flags
 	policy      Policy[T]
 	cache       map[snowflake.ID]map[snowflake.ID]T
+	observers   Observers[T]
+	stats       statsCounter
 }
 
 func (c *defaultGroupedCache[T]) Get(groupID snowflake.ID, id snowflake.ID) (T, bool) {
 	c.mu.RLock()
-	defer c.mu.RUnlock()
-
-	if groupEntities, ok := c.cache[groupID]; ok {
-		if entity, ok := groupEntities[id]; ok {
-			return entity, true
-		}
-	}
+	entity, ok := c.cache[groupID][id]
+	c.mu.RUnlock()
 
-	var entity T
-	return entity, false
+	c.stats.get(ok)
+	return entity, ok
 }
 
 func (c *defaultGroupedCache[T]) Put(groupID snowflake.ID, id snowflake.ID, entity T) {
@@ -82,69 +82,91 @@ func (c *defaultGroupedCache[T]) Put(groupID snowflake.ID, id snowflake.ID, enti
 		return
 	}
 	if c.policy != nil && !c.policy(entity) {
+		c.stats.policyRejections.Add(1)
 		return
 	}
+	c.stats.puts.Add(1)
 	c.mu.Lock()
 	defer c.mu.Unlock()
-
 	if c.cache == nil {
 		c.cache = make(map[snowflake.ID]map[snowflake.ID]T)
 	}
 
-	if groupEntities, ok := c.cache[groupID]; ok {
-		groupEntities[id] = entity
-	} else {
+	groupEntities, ok := c.cache[groupID]
+	if !ok {
 		groupEntities = make(map[snowflake.ID]T)
-		groupEntities[id] = entity
 		c.cache[groupID] = groupEntities
 	}
+	oldEntity, ok := groupEntities[id]
+	groupEntities[id] = entity
+	c.observers.Notify(PutChange(groupID, id, oldEntity, ok, entity))
 }
 
-func (c *defaultGroupedCache[T]) Remove(groupID snowflake.ID, id snowflake.ID) (entity T, ok bool) {
+func (c *defaultGroupedCache[T]) Remove(groupID snowflake.ID, id snowflake.ID) (T, bool) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
-
-	if groupEntities, ok := c.cache[groupID]; ok {
-		if entity, ok := groupEntities[id]; ok {
-			delete(groupEntities, id)
-			return entity, ok
-		}
+	entity, ok := c.cache[groupID][id]
+	if ok {
+		delete(c.cache[groupID], id)
+		c.stats.removes.Add(1)
+		c.observers.Notify(RemoveChange(groupID, id, entity))
 	}
-	ok = false
-	return
+	return entity, ok
 }
 
 func (c *defaultGroupedCache[T]) GroupRemove(groupID snowflake.ID) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
-
+	groupEntities := c.cache[groupID]
 	delete(c.cache, groupID)
+
+	c.stats.removes.Add(uint64(len(groupEntities)))
+	if c.observers.Len() == 0 {
+		return
+	}
+	changes := make([]Change[T], 0, len(groupEntities))
+	for id, entity := range groupEntities {
+		changes = append(changes, RemoveChange(groupID, id, entity))
+	}
+	c.observers.Notify(changes...)
 }
 
 func (c *defaultGroupedCache[T]) RemoveIf(filterFunc GroupedFilterFunc[T]) {
-	c.mu.Lock()
-	defer c.mu.Unlock()
+	observed := c.observers.Len() > 0
+	var changes []Change[T]
 
+	c.mu.Lock()
 	for groupID := range c.cache {
 		for id, entity := range c.cache[groupID] {
 			if filterFunc(groupID, entity) {
 				delete(c.cache[groupID], id)
+				c.stats.removes.Add(1)
+				if observed {
+					changes = append(changes, RemoveChange(groupID, id, entity))
+				}
 			}
 		}
 	}
+	c.observers.Notify(changes...)
+	c.mu.Unlock()
 }
 
 func (c *defaultGroupedCache[T]) GroupRemoveIf(groupID snowflake.ID, filterFunc GroupedFilterFunc[T]) {
-	c.mu.Lock()
-	defer c.mu.Unlock()
+	observed := c.observers.Len() > 0
+	var changes []Change[T]
 
-	if groupEntities, ok := c.cache[groupID]; ok {
-		for id, entity := range groupEntities {
-			if filterFunc(groupID, entity) {
-				delete(c.cache[groupID], id)
+	c.mu.Lock()
+	for id, entity := range c.cache[groupID] {
+		if filterFunc(groupID, entity) {
+			delete(c.cache[groupID], id)
+			c.stats.removes.Add(1)
+			if observed {
+				changes = append(changes, RemoveChange(groupID, id, entity))
 			}
 		}
 	}
+	c.observers.Notify(changes...)
+	c.mu.Unlock()
 }
 
 func (c *defaultGroupedCache[T]) Len() int {
@@ -184,3 +206,28 @@ func (c *defaultGroupedCache[T]) GroupForEach(groupID snowflake.ID, forEachFunc
 		forEachFunc(entity)
 	}
 }
+
+func (c *defaultGroupedCache[T]) AddObserver(observer Observer[T]) func() {
+	return c.observers.Add(observer)
+}
+
+func (c *defaultGroupedCache[T]) Stats() Stats {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	var (
+		entities int
+		sampler  sizeSampler
+		sampling = true
+	)
+	for _, groupEntities := range c.cache {
+		entities += len(groupEntities)
+		for _, entity := range groupEntities {
+			if !sampling {
+				break
+			}
+			sampling = sampler.sample(entity)
+		}
+	}
+	return c.stats.stats(entities, sampler.estimate(entities))
+}
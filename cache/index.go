@@ -0,0 +1,120 @@
+package cache
+
+import (
+	"sync"
+
+	"github.com/disgoorg/snowflake/v2"
+)
+
+// IndexKeyFunc returns the keys an entity is indexed under.
+type IndexKeyFunc[T any] func(entity T) []snowflake.ID
+
+// IndexRef references an entity in a Cache or GroupedCache. GroupID is always 0 for a Cache.
+type IndexRef struct {
+	GroupID snowflake.ID
+	ID      snowflake.ID
+}
+
+// Index is a secondary index over a Cache or GroupedCache which maps keys to the entities indexed under them.
+// It is kept up to date by registering Observe as Observer on the cache, so it only sees changes made by the same process.
+type Index[T any] interface {
+	// Refs returns the references to all entities indexed under the given key.
+	Refs(key snowflake.ID) []IndexRef
+
+	// Len returns the number of entities indexed under the given key.
+	Len(key snowflake.ID) int
+
+	// Observe updates the index with the given Change.
+	Observe(change Change[T])
+}
+
+var _ Index[any] = (*indexImpl[any])(nil)
+
+// NewIndex returns a new thread safe Index which indexes entities under the keys returned by the given IndexKeyFunc.
+func NewIndex[T any](keyFunc IndexKeyFunc[T]) Index[T] {
+	return &indexImpl[T]{
+		keyFunc: keyFunc,
+		refs:    map[snowflake.ID]map[IndexRef]struct{}{},
+	}
+}
+
+type indexImpl[T any] struct {
+	mu      sync.RWMutex
+	keyFunc IndexKeyFunc[T]
+	refs    map[snowflake.ID]map[IndexRef]struct{}
+}
+
+func (i *indexImpl[T]) Refs(key snowflake.ID) []IndexRef {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+
+	refs := make([]IndexRef, 0, len(i.refs[key]))
+	for ref := range i.refs[key] {
+		refs = append(refs, ref)
+	}
+	return refs
+}
+
+func (i *indexImpl[T]) Len(key snowflake.ID) int {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+	return len(i.refs[key])
+}
+
+func (i *indexImpl[T]) Observe(change Change[T]) {
+	ref := IndexRef{GroupID: change.GroupID, ID: change.ID}
+
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	if change.Old != nil {
+		for _, key := range i.keyFunc(*change.Old) {
+			if refs, ok := i.refs[key]; ok {
+				delete(refs, ref)
+				if len(refs) == 0 {
+					delete(i.refs, key)
+				}
+			}
+		}
+	}
+	if change.New != nil {
+		for _, key := range i.keyFunc(*change.New) {
+			refs, ok := i.refs[key]
+			if !ok {
+				refs = map[IndexRef]struct{}{}
+				i.refs[key] = refs
+			}
+			refs[ref] = struct{}{}
+		}
+	}
+}
+
+// newCacheIndex returns a new Index kept up to date with the given Cache. Entities already cached are indexed using the idFunc.
+// It returns nil if the Cache does not implement Observable.
+func newCacheIndex[T any](cache Cache[T], keyFunc IndexKeyFunc[T], idFunc func(entity T) snowflake.ID) Index[T] {
+	observable, ok := cache.(Observable[T])
+	if !ok {
+		return nil
+	}
+	index := NewIndex(keyFunc)
+	observable.AddObserver(index.Observe)
+	cache.ForEach(func(entity T) {
+		index.Observe(Change[T]{ID: idFunc(entity), New: &entity})
+	})
+	return index
+}
+
+// newGroupedCacheIndex returns a new Index kept up to date with the given GroupedCache. Entities already cached are indexed using the idFunc.
+// It returns nil if the GroupedCache does not implement Observable.
+func newGroupedCacheIndex[T any](cache GroupedCache[T], keyFunc IndexKeyFunc[T], idFunc func(entity T) snowflake.ID) Index[T] {
+	observable, ok := cache.(Observable[T])
+	if !ok {
+		return nil
+	}
+	index := NewIndex(keyFunc)
+	observable.AddObserver(index.Observe)
+	cache.ForEach(func(groupID snowflake.ID, entity T) {
+		index.Observe(Change[T]{GroupID: groupID, ID: idFunc(entity), New: &entity})
+	})
+	return index
+}
@@ -0,0 +1,68 @@
+package cache
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/disgoorg/snowflake/v2"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/disgoorg/disgo/discord"
+)
+
+func TestIndex(t *testing.T) {
+	c := NewGroupedCache[discord.Member](FlagsAll, FlagsNone, nil)
+	c.Put(1, 10, discord.Member{GuildID: 1, User: discord.User{ID: 10}, RoleIDs: []snowflake.ID{100}})
+
+	index := newGroupedCacheIndex(c, func(member discord.Member) []snowflake.ID {
+		return member.RoleIDs
+	}, func(member discord.Member) snowflake.ID {
+		return member.User.ID
+	})
+	assert.Equal(t, []IndexRef{{GroupID: 1, ID: 10}}, index.Refs(100))
+
+	c.Put(1, 10, discord.Member{GuildID: 1, User: discord.User{ID: 10}, RoleIDs: []snowflake.ID{101}})
+	c.Put(1, 11, discord.Member{GuildID: 1, User: discord.User{ID: 11}, RoleIDs: []snowflake.ID{101}})
+	assert.Equal(t, 0, index.Len(100))
+	assert.ElementsMatch(t, []IndexRef{{GroupID: 1, ID: 10}, {GroupID: 1, ID: 11}}, index.Refs(101))
+
+	c.GroupRemove(1)
+	assert.Equal(t, 0, index.Len(101))
+}
+
+func testChannel(t *testing.T, data string) discord.GuildChannel {
+	var channel discord.UnmarshalChannel
+	assert.NoError(t, json.Unmarshal([]byte(data), &channel))
+	return channel.Channel.(discord.GuildChannel)
+}
+
+func TestCachesVoiceStateIndex(t *testing.T) {
+	for name, caches := range map[string]Caches{
+		"indexed":   New(WithCaches(FlagsAll), WithIndexes(FlagVoiceStates)),
+		"unindexed": New(WithCaches(FlagsAll)),
+	} {
+		t.Run(name, func(t *testing.T) {
+			channel := testChannel(t, `{"id":"20","guild_id":"1","type":2,"name":"voice"}`).(discord.GuildAudioChannel)
+			channelID := channel.ID()
+			otherChannelID := snowflake.ID(21)
+			caches.AddMember(discord.Member{GuildID: 1, User: discord.User{ID: 10}})
+			caches.AddVoiceState(discord.VoiceState{GuildID: 1, UserID: 10, ChannelID: &channelID})
+			assert.Len(t, caches.AudioChannelMembers(channel), 1)
+
+			caches.AddVoiceState(discord.VoiceState{GuildID: 1, UserID: 10, ChannelID: &otherChannelID})
+			assert.Empty(t, caches.AudioChannelMembers(channel))
+		})
+	}
+}
+
+func TestCachesChannelIndex(t *testing.T) {
+	caches := New(WithCaches(FlagsAll), WithIndexes(FlagChannels))
+	caches.AddChannel(testChannel(t, `{"id":"20","guild_id":"1","type":0,"name":"text"}`))
+	caches.AddChannel(testChannel(t, `{"id":"30","guild_id":"1","type":11,"name":"thread","parent_id":"20"}`))
+	caches.AddChannel(testChannel(t, `{"id":"31","guild_id":"2","type":11,"name":"thread","parent_id":"40"}`))
+	assert.Len(t, caches.GuildThreadsInChannel(20), 1)
+
+	caches.RemoveChannelsByGuildID(1)
+	assert.Equal(t, 1, caches.ChannelsLen())
+	assert.Empty(t, caches.GuildThreadsInChannel(20))
+}
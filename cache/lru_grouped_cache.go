@@ -0,0 +1,384 @@
+package cache
+
+import (
+	"container/list"
+	"sync"
+	"time"
+	"unsafe"
+
+	"github.com/disgoorg/snowflake/v2"
+)
+
+// EvictionReason is the reason an entity was evicted from a GroupedCache created with NewLRUGroupedCache.
+type EvictionReason int
+
+const (
+	// EvictionReasonGroupLimit means the group of the entity exceeded LRUConfig.MaxPerGroup.
+	EvictionReasonGroupLimit EvictionReason = iota
+	// EvictionReasonLimit means the cache exceeded LRUConfig.MaxTotal.
+	EvictionReasonLimit
+	// EvictionReasonExpired means the entity was cached longer than LRUConfig.MaxAge.
+	EvictionReasonExpired
+)
+
+func (r EvictionReason) String() string {
+	switch r {
+	case EvictionReasonGroupLimit:
+		return "group_limit"
+	case EvictionReasonLimit:
+		return "limit"
+	case EvictionReasonExpired:
+		return "expired"
+	}
+	return "unknown"
+}
+
+// EvictionFunc is called with each entity evicted from a GroupedCache created with NewLRUGroupedCache.
+// It is called after the entity was removed and outside any cache locks, so it's safe to access the cache from it.
+type EvictionFunc[T any] func(groupID snowflake.ID, id snowflake.ID, entity T, reason EvictionReason)
+
+// LRUConfig configures the limits of a GroupedCache created with NewLRUGroupedCache. A zero value disables the respective limit.
+type LRUConfig[T any] struct {
+	// MaxPerGroup is the max number of entities per group. The least recently used entity of the group is evicted first.
+	MaxPerGroup int
+	// MaxTotal is the max number of entities in the whole cache. The least recently used entity is evicted first.
+	MaxTotal int
+	// MaxAge is how long an entity is kept after it was last put into the cache.
+	MaxAge time.Duration
+	// EvictionFunc is called with each evicted entity.
+	EvictionFunc EvictionFunc[T]
+}
+
+var (
+	_ GroupedCache[any] = (*lruGroupedCache[any])(nil)
+	_ Observable[any]   = (*lruGroupedCache[any])(nil)
+)
+
+// NewLRUGroupedCache returns a new GroupedCache with the provided flags, neededFlags and policy which evicts entities based on the given LRUConfig.
+// Get & Put mark an entity as recently used.
+func NewLRUGroupedCache[T any](flags Flags, neededFlags Flags, policy Policy[T], config LRUConfig[T]) GroupedCache[T] {
+	return &lruGroupedCache[T]{
+		flags:       flags,
+		neededFlags: neededFlags,
+		policy:      policy,
+		config:      config,
+		groups:      make(map[snowflake.ID]*lruGroup[T]),
+		lru:         list.New(),
+		age:         list.New(),
+		now:         time.Now,
+	}
+}
+
+type lruEntry[T any] struct {
+	groupID  snowflake.ID
+	id       snowflake.ID
+	entity   T
+	putAt    time.Time
+	lruElem  *list.Element
+	ageElem  *list.Element
+	groupElm *list.Element
+}
+
+type lruGroup[T any] struct {
+	entries map[snowflake.ID]*lruEntry[T]
+	lru     *list.List
+}
+
+type eviction[T any] struct {
+	entry  *lruEntry[T]
+	reason EvictionReason
+}
+
+type lruGroupedCache[T any] struct {
+	mu          sync.Mutex
+	flags       Flags
+	neededFlags Flags
+	policy      Policy[T]
+	config      LRUConfig[T]
+
+	groups map[snowflake.ID]*lruGroup[T]
+	// lru orders all entries from least to most recently used
+	lru *list.List
+	// age orders all entries from oldest to newest put
+	age       *list.List
+	now       func() time.Time
+	observers Observers[T]
+	stats     statsCounter
+}
+
+func (c *lruGroupedCache[T]) Get(groupID snowflake.ID, id snowflake.ID) (T, bool) {
+	c.mu.Lock()
+	evictions := c.expire()
+	c.notifyEvicted(evictions)
+	var (
+		entity T
+		ok     bool
+	)
+	if group, groupOK := c.groups[groupID]; groupOK {
+		if entry, entryOK := group.entries[id]; entryOK {
+			c.lru.MoveToBack(entry.lruElem)
+			group.lru.MoveToBack(entry.groupElm)
+			entity, ok = entry.entity, true
+		}
+	}
+	c.mu.Unlock()
+
+	c.stats.get(ok)
+	c.evicted(evictions)
+	return entity, ok
+}
+
+func (c *lruGroupedCache[T]) Put(groupID snowflake.ID, id snowflake.ID, entity T) {
+	if c.flags.Missing(c.neededFlags) {
+		return
+	}
+	if c.policy != nil && !c.policy(entity) {
+		c.stats.policyRejections.Add(1)
+		return
+	}
+	c.stats.puts.Add(1)
+	c.mu.Lock()
+	evictions := c.expire()
+	c.notifyEvicted(evictions)
+	expired := len(evictions)
+
+	group, ok := c.groups[groupID]
+	if !ok {
+		group = &lruGroup[T]{
+			entries: make(map[snowflake.ID]*lruEntry[T]),
+			lru:     list.New(),
+		}
+		c.groups[groupID] = group
+	}
+
+	var (
+		oldEntity T
+		oldOK     bool
+	)
+	if entry, ok := group.entries[id]; ok {
+		oldEntity, oldOK = entry.entity, true
+		entry.entity = entity
+		entry.putAt = c.now()
+		c.lru.MoveToBack(entry.lruElem)
+		c.age.MoveToBack(entry.ageElem)
+		group.lru.MoveToBack(entry.groupElm)
+	} else {
+		entry = &lruEntry[T]{
+			groupID: groupID,
+			id:      id,
+			entity:  entity,
+			putAt:   c.now(),
+		}
+		entry.lruElem = c.lru.PushBack(entry)
+		entry.ageElem = c.age.PushBack(entry)
+		entry.groupElm = group.lru.PushBack(entry)
+		group.entries[id] = entry
+	}
+
+	if c.config.MaxPerGroup > 0 {
+		for len(group.entries) > c.config.MaxPerGroup {
+			entry := group.lru.Front().Value.(*lruEntry[T])
+			c.remove(entry)
+			evictions = append(evictions, eviction[T]{entry: entry, reason: EvictionReasonGroupLimit})
+		}
+	}
+	if c.config.MaxTotal > 0 {
+		for c.lru.Len() > c.config.MaxTotal {
+			entry := c.lru.Front().Value.(*lruEntry[T])
+			c.remove(entry)
+			evictions = append(evictions, eviction[T]{entry: entry, reason: EvictionReasonLimit})
+		}
+	}
+	c.observers.Notify(PutChange(groupID, id, oldEntity, oldOK, entity))
+	c.notifyEvicted(evictions[expired:])
+	c.mu.Unlock()
+
+	c.evicted(evictions)
+}
+
+func (c *lruGroupedCache[T]) Remove(groupID snowflake.ID, id snowflake.ID) (T, bool) {
+	c.mu.Lock()
+	var (
+		entity T
+		ok     bool
+	)
+	if group, groupOK := c.groups[groupID]; groupOK {
+		if entry, entryOK := group.entries[id]; entryOK {
+			c.remove(entry)
+			entity, ok = entry.entity, true
+		}
+	}
+	if ok {
+		c.stats.removes.Add(1)
+		c.observers.Notify(RemoveChange(groupID, id, entity))
+	}
+	c.mu.Unlock()
+
+	return entity, ok
+}
+
+func (c *lruGroupedCache[T]) GroupRemove(groupID snowflake.ID) {
+	c.GroupRemoveIf(groupID, func(_ snowflake.ID, _ T) bool {
+		return true
+	})
+}
+
+func (c *lruGroupedCache[T]) RemoveIf(filterFunc GroupedFilterFunc[T]) {
+	var changes []Change[T]
+
+	c.mu.Lock()
+	for groupID, group := range c.groups {
+		changes = c.groupRemoveIf(groupID, group, filterFunc, changes)
+	}
+	c.observers.Notify(changes...)
+	c.mu.Unlock()
+}
+
+func (c *lruGroupedCache[T]) GroupRemoveIf(groupID snowflake.ID, filterFunc GroupedFilterFunc[T]) {
+	var changes []Change[T]
+
+	c.mu.Lock()
+	if group, ok := c.groups[groupID]; ok {
+		changes = c.groupRemoveIf(groupID, group, filterFunc, changes)
+	}
+	c.observers.Notify(changes...)
+	c.mu.Unlock()
+}
+
+// groupRemoveIf removes all entries of the group passing the filterFunc and appends their changes. It must be called with mu held.
+func (c *lruGroupedCache[T]) groupRemoveIf(groupID snowflake.ID, group *lruGroup[T], filterFunc GroupedFilterFunc[T], changes []Change[T]) []Change[T] {
+	for _, entry := range group.entries {
+		if filterFunc(groupID, entry.entity) {
+			c.remove(entry)
+			c.stats.removes.Add(1)
+			changes = append(changes, RemoveChange(groupID, entry.id, entry.entity))
+		}
+	}
+	return changes
+}
+
+func (c *lruGroupedCache[T]) Len() int {
+	c.mu.Lock()
+	evictions := c.expire()
+	c.notifyEvicted(evictions)
+	length := c.lru.Len()
+	c.mu.Unlock()
+
+	c.evicted(evictions)
+	return length
+}
+
+func (c *lruGroupedCache[T]) GroupLen(groupID snowflake.ID) int {
+	c.mu.Lock()
+	evictions := c.expire()
+	c.notifyEvicted(evictions)
+	var length int
+	if group, ok := c.groups[groupID]; ok {
+		length = len(group.entries)
+	}
+	c.mu.Unlock()
+
+	c.evicted(evictions)
+	return length
+}
+
+func (c *lruGroupedCache[T]) ForEach(forEachFunc func(groupID snowflake.ID, entity T)) {
+	c.mu.Lock()
+	evictions := c.expire()
+	c.notifyEvicted(evictions)
+	entries := make([]*lruEntry[T], 0, c.lru.Len())
+	for elem := c.lru.Front(); elem != nil; elem = elem.Next() {
+		entries = append(entries, elem.Value.(*lruEntry[T]))
+	}
+	c.mu.Unlock()
+
+	c.evicted(evictions)
+	for _, entry := range entries {
+		forEachFunc(entry.groupID, entry.entity)
+	}
+}
+
+func (c *lruGroupedCache[T]) GroupForEach(groupID snowflake.ID, forEachFunc func(entity T)) {
+	c.mu.Lock()
+	evictions := c.expire()
+	c.notifyEvicted(evictions)
+	var entities []T
+	if group, ok := c.groups[groupID]; ok {
+		entities = make([]T, 0, len(group.entries))
+		for elem := group.lru.Front(); elem != nil; elem = elem.Next() {
+			entities = append(entities, elem.Value.(*lruEntry[T]).entity)
+		}
+	}
+	c.mu.Unlock()
+
+	c.evicted(evictions)
+	for _, entity := range entities {
+		forEachFunc(entity)
+	}
+}
+
+// expire removes all entries older than the max age. It must be called with mu held.
+func (c *lruGroupedCache[T]) expire() []eviction[T] {
+	if c.config.MaxAge <= 0 {
+		return nil
+	}
+	var evictions []eviction[T]
+	deadline := c.now().Add(-c.config.MaxAge)
+	for elem := c.age.Front(); elem != nil; elem = c.age.Front() {
+		entry := elem.Value.(*lruEntry[T])
+		if entry.putAt.After(deadline) {
+			break
+		}
+		c.remove(entry)
+		evictions = append(evictions, eviction[T]{entry: entry, reason: EvictionReasonExpired})
+	}
+	return evictions
+}
+
+// remove removes the entry from all lists. It must be called with mu held.
+func (c *lruGroupedCache[T]) remove(entry *lruEntry[T]) {
+	c.lru.Remove(entry.lruElem)
+	c.age.Remove(entry.ageElem)
+	group := c.groups[entry.groupID]
+	group.lru.Remove(entry.groupElm)
+	delete(group.entries, entry.id)
+	if len(group.entries) == 0 {
+		delete(c.groups, entry.groupID)
+	}
+}
+
+// notifyEvicted notifies the observers of the given evictions. It must be called with mu held.
+func (c *lruGroupedCache[T]) notifyEvicted(evictions []eviction[T]) {
+	for _, e := range evictions {
+		c.observers.Notify(RemoveChange(e.entry.groupID, e.entry.id, e.entry.entity))
+	}
+}
+
+// evicted calls the EvictionFunc for the given evictions. It must be called without mu held, so the EvictionFunc can access the cache.
+func (c *lruGroupedCache[T]) evicted(evictions []eviction[T]) {
+	c.stats.evictions.Add(uint64(len(evictions)))
+	if c.config.EvictionFunc == nil {
+		return
+	}
+	for _, e := range evictions {
+		c.config.EvictionFunc(e.entry.groupID, e.entry.id, e.entry.entity, e.reason)
+	}
+}
+
+func (c *lruGroupedCache[T]) AddObserver(observer Observer[T]) func() {
+	return c.observers.Add(observer)
+}
+
+func (c *lruGroupedCache[T]) Stats() Stats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var sampler sizeSampler
+	for elem := c.lru.Front(); elem != nil; elem = elem.Next() {
+		if !sampler.sample(elem.Value.(*lruEntry[T]).entity) {
+			break
+		}
+	}
+	// each entry is also referenced by three list elements
+	return c.stats.stats(c.lru.Len(), sampler.estimate(c.lru.Len())+int64(c.lru.Len())*3*int64(unsafe.Sizeof(list.Element{})))
+}
@@ -0,0 +1,70 @@
+package cache
+
+import (
+	"testing"
+	"time"
+
+	"github.com/disgoorg/snowflake/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+type evicted struct {
+	id     snowflake.ID
+	reason EvictionReason
+}
+
+func newTestLRUGroupedCache(config LRUConfig[string], evictions *[]evicted) *lruGroupedCache[string] {
+	config.EvictionFunc = func(_ snowflake.ID, id snowflake.ID, _ string, reason EvictionReason) {
+		*evictions = append(*evictions, evicted{id: id, reason: reason})
+	}
+	return NewLRUGroupedCache[string](FlagsAll, FlagsNone, nil, config).(*lruGroupedCache[string])
+}
+
+func TestLRUGroupedCacheMaxPerGroup(t *testing.T) {
+	var evictions []evicted
+	c := newTestLRUGroupedCache(LRUConfig[string]{MaxPerGroup: 2}, &evictions)
+
+	c.Put(1, 1, "a")
+	c.Put(1, 2, "b")
+	c.Get(1, 1)
+	c.Put(1, 3, "c")
+	c.Put(2, 4, "d")
+
+	assert.Equal(t, []evicted{{id: 2, reason: EvictionReasonGroupLimit}}, evictions)
+	assert.Equal(t, 2, c.GroupLen(1))
+	assert.Equal(t, 3, c.Len())
+}
+
+func TestLRUGroupedCacheMaxTotal(t *testing.T) {
+	var evictions []evicted
+	c := newTestLRUGroupedCache(LRUConfig[string]{MaxTotal: 2}, &evictions)
+
+	c.Put(1, 1, "a")
+	c.Put(2, 2, "b")
+	c.Put(1, 1, "a2")
+	c.Put(3, 3, "c")
+
+	assert.Equal(t, []evicted{{id: 2, reason: EvictionReasonLimit}}, evictions)
+	assert.Equal(t, 0, c.GroupLen(2))
+	entity, ok := c.Get(1, 1)
+	assert.True(t, ok)
+	assert.Equal(t, "a2", entity)
+}
+
+func TestLRUGroupedCacheMaxAge(t *testing.T) {
+	var evictions []evicted
+	c := newTestLRUGroupedCache(LRUConfig[string]{MaxAge: time.Minute}, &evictions)
+	now := time.Now()
+	c.now = func() time.Time { return now }
+
+	c.Put(1, 1, "a")
+	now = now.Add(30 * time.Second)
+	c.Put(1, 2, "b")
+	now = now.Add(45 * time.Second)
+
+	_, ok := c.Get(1, 1)
+	assert.False(t, ok)
+	_, ok = c.Get(1, 2)
+	assert.True(t, ok)
+	assert.Equal(t, []evicted{{id: 1, reason: EvictionReasonExpired}}, evictions)
+}
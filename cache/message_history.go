@@ -0,0 +1,114 @@
+package cache
+
+import (
+	"container/list"
+	"sync"
+
+	"github.com/disgoorg/snowflake/v2"
+
+	"github.com/disgoorg/disgo/discord"
+)
+
+// MessageHistory stores the revisions of messages so update & delete events can expose all known versions of a message.
+// It is opt-in and can be set with bot.WithMessageHistory. Implementations other than the in-memory one returned by NewMessageHistory
+// can be used to keep the history in an external or embedded store.
+// The revision of a created message is only added once the message is updated while it is still cached, or right away if the message is not cached.
+type MessageHistory interface {
+	// AddRevision adds the given discord.Message as the newest revision of the message and returns all revisions, oldest first.
+	AddRevision(message discord.Message) []discord.Message
+
+	// Revisions returns all revisions of the message, oldest first.
+	Revisions(channelID snowflake.ID, messageID snowflake.ID) []discord.Message
+
+	// RemoveRevisions removes all revisions of the message and returns them, oldest first.
+	RemoveRevisions(channelID snowflake.ID, messageID snowflake.ID) []discord.Message
+}
+
+var _ MessageHistory = (*messageHistoryImpl)(nil)
+
+// NewMessageHistory returns a new in-memory MessageHistory which keeps up to maxRevisions revisions for up to maxMessages messages.
+// Once maxRevisions is reached the oldest revision is dropped, once maxMessages is reached the message added first is dropped.
+// Zero means no limit.
+func NewMessageHistory(maxRevisions int, maxMessages int) MessageHistory {
+	return &messageHistoryImpl{
+		maxRevisions: maxRevisions,
+		maxMessages:  maxMessages,
+		messages:     map[messageKey]*list.Element{},
+		order:        list.New(),
+	}
+}
+
+type messageKey struct {
+	channelID snowflake.ID
+	messageID snowflake.ID
+}
+
+type messageRevisions struct {
+	key       messageKey
+	revisions []discord.Message
+}
+
+type messageHistoryImpl struct {
+	mu           sync.Mutex
+	maxRevisions int
+	maxMessages  int
+	messages     map[messageKey]*list.Element
+	// order contains the *messageRevisions in the order their messages were added
+	order *list.List
+}
+
+func (h *messageHistoryImpl) AddRevision(message discord.Message) []discord.Message {
+	key := messageKey{channelID: message.ChannelID, messageID: message.ID}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	elem, ok := h.messages[key]
+	if !ok {
+		elem = h.order.PushBack(&messageRevisions{key: key})
+		h.messages[key] = elem
+		if h.maxMessages > 0 && h.order.Len() > h.maxMessages {
+			oldest := h.order.Front()
+			h.order.Remove(oldest)
+			delete(h.messages, oldest.Value.(*messageRevisions).key)
+		}
+	}
+
+	entry := elem.Value.(*messageRevisions)
+	entry.revisions = append(entry.revisions, message)
+	if h.maxRevisions > 0 && len(entry.revisions) > h.maxRevisions {
+		entry.revisions = append(entry.revisions[:0:0], entry.revisions[len(entry.revisions)-h.maxRevisions:]...)
+	}
+	return copyRevisions(entry.revisions)
+}
+
+func (h *messageHistoryImpl) Revisions(channelID snowflake.ID, messageID snowflake.ID) []discord.Message {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	elem, ok := h.messages[messageKey{channelID: channelID, messageID: messageID}]
+	if !ok {
+		return nil
+	}
+	return copyRevisions(elem.Value.(*messageRevisions).revisions)
+}
+
+func (h *messageHistoryImpl) RemoveRevisions(channelID snowflake.ID, messageID snowflake.ID) []discord.Message {
+	key := messageKey{channelID: channelID, messageID: messageID}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	elem, ok := h.messages[key]
+	if !ok {
+		return nil
+	}
+	h.order.Remove(elem)
+	delete(h.messages, key)
+	return elem.Value.(*messageRevisions).revisions
+}
+
+// copyRevisions returns a copy of the given revisions so callers can't modify the stored ones.
+func copyRevisions(revisions []discord.Message) []discord.Message {
+	return append([]discord.Message(nil), revisions...)
+}
@@ -0,0 +1,27 @@
+package cache
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/disgoorg/disgo/discord"
+)
+
+func TestMessageHistory(t *testing.T) {
+	h := NewMessageHistory(2, 1)
+
+	h.AddRevision(discord.Message{ID: 1, ChannelID: 1, Content: "a"})
+	h.AddRevision(discord.Message{ID: 1, ChannelID: 1, Content: "b"})
+	revisions := h.AddRevision(discord.Message{ID: 1, ChannelID: 1, Content: "c"})
+	if assert.Len(t, revisions, 2) {
+		assert.Equal(t, "b", revisions[0].Content)
+		assert.Equal(t, "c", revisions[1].Content)
+	}
+
+	h.AddRevision(discord.Message{ID: 2, ChannelID: 1, Content: "d"})
+	assert.Nil(t, h.Revisions(1, 1), "the oldest message should be dropped once maxMessages is reached")
+
+	assert.Len(t, h.RemoveRevisions(1, 2), 1)
+	assert.Nil(t, h.Revisions(1, 2))
+}
@@ -13,6 +13,8 @@ var (
 	ErrShardNotConnected       = errors.New("shard is not connected")
 	ErrShardNotFound           = errors.New("shard not found in shard manager")
 	ErrGatewayCompressedData   = errors.New("disgo does not currently support compressed gateway data")
+	ErrGatewayNoSession        = errors.New("gateway has no session")
+	ErrHandoffNotSupported     = errors.New("handoff is not supported")
 	ErrNoHTTPServer            = errors.New("no http server configured")
 
 	ErrNoDisgoInstance = errors.New("no disgo instance injected")
@@ -31,5 +33,6 @@ var (
 
 	ErrMemberMustBeConnectedToChannel = errors.New("the member must be connected to the channel")
 
-	ErrStickerTypeGuild = errors.New("sticker type must be of type StickerTypeGuild")
+	ErrStickerTypeGuild  = errors.New("sticker type must be of type StickerTypeGuild")
+	ErrStickerNotInGuild = errors.New("sticker does not belong to the guild")
 )
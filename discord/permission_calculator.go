@@ -0,0 +1,249 @@
+package discord
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/disgoorg/snowflake/v2"
+)
+
+// PermissionsTimedOut are the Permissions a timed out Member keeps.
+const PermissionsTimedOut = PermissionViewChannel | PermissionReadMessageHistory
+
+// permissionsRequiringSendMessages are implicitly denied without PermissionSendMessages (or PermissionSendMessagesInThreads in GuildThread(s)).
+const permissionsRequiringSendMessages = PermissionMentionEveryone | PermissionSendTTSMessages | PermissionAttachFiles | PermissionEmbedLinks
+
+// permissionsRequiringConnect are implicitly denied without PermissionConnect in a GuildAudioChannel.
+const permissionsRequiringConnect = PermissionSpeak | PermissionStream | PermissionMuteMembers | PermissionDeafenMembers | PermissionMoveMembers |
+	PermissionUseVAD | PermissionPrioritySpeaker | PermissionRequestToSpeak | PermissionUseSoundboard | PermissionUseExternalSounds | PermissionUseEmbeddedActivities
+
+// PermissionInput contains everything needed to compute the Permissions of a Member with ComputePermissions.
+type PermissionInput struct {
+	// GuildID is the ID of the Guild, which is also the ID of its @everyone Role.
+	GuildID snowflake.ID
+	// OwnerID is the ID of the Guild owner.
+	OwnerID snowflake.ID
+	// Member is the Member to compute the Permissions for. Only the User.ID, RoleIDs & CommunicationDisabledUntil are used.
+	Member Member
+	// Roles are the Role(s) of the Guild. It has to contain at least the @everyone Role & the Role(s) of the Member, all others are ignored.
+	Roles []Role
+	// Channel is the GuildChannel to compute the Permissions in. The Guild level Permissions are computed if it is nil.
+	Channel GuildChannel
+	// ParentChannel is the parent GuildChannel of the Channel if it is a GuildThread, as threads inherit the PermissionOverwrites of their parent.
+	// Without it no PermissionOverwrites are applied to a GuildThread.
+	ParentChannel GuildChannel
+	// Now is used to check whether the Member is timed out. Defaults to time.Now().
+	Now time.Time
+}
+
+// PermissionSource is the source of a PermissionStep.
+type PermissionSource int
+
+const (
+	// PermissionSourceOwner grants all Permissions to the Guild owner.
+	PermissionSourceOwner PermissionSource = iota
+	// PermissionSourceRole grants the Permissions of a Role. The @everyone Role has the ID of the Guild.
+	PermissionSourceRole
+	// PermissionSourceAdministrator grants all Permissions to Members with PermissionAdministrator.
+	PermissionSourceAdministrator
+	// PermissionSourceEveryoneOverwrite is the RolePermissionOverwrite of the @everyone Role.
+	PermissionSourceEveryoneOverwrite
+	// PermissionSourceRoleOverwrite is a RolePermissionOverwrite of a Role of the Member.
+	// The denied Permissions of all Role(s) are applied before the allowed ones, so an allow always wins over a deny.
+	PermissionSourceRoleOverwrite
+	// PermissionSourceMemberOverwrite is the MemberPermissionOverwrite of the Member.
+	PermissionSourceMemberOverwrite
+	// PermissionSourceImplicit denies Permissions which can't be used without another Permission, e.g. PermissionSendMessages without PermissionViewChannel.
+	PermissionSourceImplicit
+	// PermissionSourceTimeout denies all Permissions except PermissionsTimedOut for timed out Members.
+	PermissionSourceTimeout
+)
+
+func (s PermissionSource) String() string {
+	switch s {
+	case PermissionSourceOwner:
+		return "owner"
+	case PermissionSourceRole:
+		return "role"
+	case PermissionSourceAdministrator:
+		return "administrator"
+	case PermissionSourceEveryoneOverwrite:
+		return "@everyone overwrite"
+	case PermissionSourceRoleOverwrite:
+		return "role overwrite"
+	case PermissionSourceMemberOverwrite:
+		return "member overwrite"
+	case PermissionSourceImplicit:
+		return "implicit"
+	case PermissionSourceTimeout:
+		return "timeout"
+	default:
+		return "unknown"
+	}
+}
+
+// PermissionStep is a single step of a PermissionTrace. It removes the Deny Permissions before adding the Allow Permissions.
+type PermissionStep struct {
+	Source PermissionSource
+	// ID is the ID of the Role or Member for role & overwrite sources.
+	ID snowflake.ID
+	// ChannelID is the ID of the GuildChannel for overwrite & implicit sources. Overwrites of GuildThread(s) are from their parent channel.
+	ChannelID snowflake.ID
+	// Required is the Permission whose absence caused a PermissionSourceImplicit step.
+	Required Permissions
+	Allow    Permissions
+	Deny     Permissions
+	// Result are the Permissions after this step was applied.
+	Result Permissions
+}
+
+func (s PermissionStep) String() string {
+	var str string
+	switch s.Source {
+	case PermissionSourceRole, PermissionSourceRoleOverwrite, PermissionSourceMemberOverwrite:
+		str = fmt.Sprintf("%s %s", s.Source, s.ID)
+	case PermissionSourceImplicit:
+		str = fmt.Sprintf("implicit (missing %s)", s.Required)
+	default:
+		str = s.Source.String()
+	}
+	if s.Deny != 0 {
+		str += fmt.Sprintf(" denied [%s]", s.Deny)
+	}
+	if s.Allow != 0 {
+		str += fmt.Sprintf(" allowed [%s]", s.Allow)
+	}
+	return str
+}
+
+// PermissionTrace is the result of ComputePermissions. It contains the computed Permissions & every step which led to them.
+type PermissionTrace struct {
+	Permissions Permissions
+	Steps       []PermissionStep
+}
+
+// Explain returns all steps which allowed or denied the given Permissions in the order they were applied.
+// The last step decides whether the Permissions are granted.
+func (t PermissionTrace) Explain(permissions Permissions) []PermissionStep {
+	var steps []PermissionStep
+	for _, step := range t.Steps {
+		if (step.Allow|step.Deny)&permissions != 0 {
+			steps = append(steps, step)
+		}
+	}
+	return steps
+}
+
+func (t *PermissionTrace) apply(step PermissionStep) {
+	t.Permissions = t.Permissions.Remove(step.Deny).Add(step.Allow)
+	step.Result = t.Permissions
+	t.Steps = append(t.Steps, step)
+}
+
+// ComputePermissions computes the Permissions of a Member in a Guild or GuildChannel from the given PermissionInput.
+// It follows https://discord.com/developers/docs/topics/permissions#permission-overwrites & the implicit permission rules.
+// Membership of private GuildThread(s) is not checked.
+func ComputePermissions(input PermissionInput) PermissionTrace {
+	var trace PermissionTrace
+	if input.Member.User.ID == input.OwnerID {
+		trace.apply(PermissionStep{Source: PermissionSourceOwner, Allow: PermissionsAll})
+		return trace
+	}
+
+	roles := make(map[snowflake.ID]Role, len(input.Roles))
+	for _, role := range input.Roles {
+		roles[role.ID] = role
+	}
+	if everyone, ok := roles[input.GuildID]; ok {
+		trace.apply(PermissionStep{Source: PermissionSourceRole, ID: everyone.ID, Allow: everyone.Permissions})
+	}
+	for _, roleID := range input.Member.RoleIDs {
+		if roleID == input.GuildID {
+			continue
+		}
+		if role, ok := roles[roleID]; ok {
+			trace.apply(PermissionStep{Source: PermissionSourceRole, ID: role.ID, Allow: role.Permissions})
+		}
+	}
+	if trace.Permissions.Has(PermissionAdministrator) {
+		trace.apply(PermissionStep{Source: PermissionSourceAdministrator, Allow: PermissionsAll})
+		return trace
+	}
+
+	if input.Channel != nil {
+		applyOverwrites(&trace, input)
+		applyImplicitPermissions(&trace, input.Channel)
+	}
+
+	now := input.Now
+	if now.IsZero() {
+		now = time.Now()
+	}
+	if until := input.Member.CommunicationDisabledUntil; until != nil && until.After(now) {
+		if deny := trace.Permissions.Remove(PermissionsTimedOut); deny != 0 {
+			trace.apply(PermissionStep{Source: PermissionSourceTimeout, Deny: deny})
+		}
+	}
+	return trace
+}
+
+func applyOverwrites(trace *PermissionTrace, input PermissionInput) {
+	channel := input.Channel
+	if _, ok := channel.(GuildThread); ok {
+		if input.ParentChannel == nil {
+			return
+		}
+		channel = input.ParentChannel
+	}
+	overwrites := channel.PermissionOverwrites()
+
+	if overwrite, ok := overwrites.Role(input.GuildID); ok && (overwrite.Allow != 0 || overwrite.Deny != 0) {
+		trace.apply(PermissionStep{Source: PermissionSourceEveryoneOverwrite, ID: input.GuildID, ChannelID: channel.ID(), Allow: overwrite.Allow, Deny: overwrite.Deny})
+	}
+
+	var roleOverwrites []RolePermissionOverwrite
+	for _, roleID := range input.Member.RoleIDs {
+		if roleID == input.GuildID {
+			continue
+		}
+		if overwrite, ok := overwrites.Role(roleID); ok {
+			roleOverwrites = append(roleOverwrites, overwrite)
+		}
+	}
+	for _, overwrite := range roleOverwrites {
+		if overwrite.Deny != 0 {
+			trace.apply(PermissionStep{Source: PermissionSourceRoleOverwrite, ID: overwrite.RoleID, ChannelID: channel.ID(), Deny: overwrite.Deny})
+		}
+	}
+	for _, overwrite := range roleOverwrites {
+		if overwrite.Allow != 0 {
+			trace.apply(PermissionStep{Source: PermissionSourceRoleOverwrite, ID: overwrite.RoleID, ChannelID: channel.ID(), Allow: overwrite.Allow})
+		}
+	}
+
+	if overwrite, ok := overwrites.Member(input.Member.User.ID); ok && (overwrite.Allow != 0 || overwrite.Deny != 0) {
+		trace.apply(PermissionStep{Source: PermissionSourceMemberOverwrite, ID: overwrite.UserID, ChannelID: channel.ID(), Allow: overwrite.Allow, Deny: overwrite.Deny})
+	}
+}
+
+func applyImplicitPermissions(trace *PermissionTrace, channel GuildChannel) {
+	deny := func(required Permissions, denied Permissions) {
+		if !trace.Permissions.Has(required) && trace.Permissions&denied != 0 {
+			trace.apply(PermissionStep{Source: PermissionSourceImplicit, ChannelID: channel.ID(), Required: required, Deny: trace.Permissions & denied})
+		}
+	}
+
+	// without PermissionViewChannel no other permission in the channel can be used
+	deny(PermissionViewChannel, trace.Permissions)
+
+	sendMessages := PermissionSendMessages
+	if _, ok := channel.(GuildThread); ok {
+		sendMessages = PermissionSendMessagesInThreads
+	}
+	if _, ok := channel.(GuildMessageChannel); ok {
+		deny(sendMessages, permissionsRequiringSendMessages)
+	}
+	if _, ok := channel.(GuildAudioChannel); ok {
+		deny(PermissionConnect, permissionsRequiringConnect)
+	}
+}
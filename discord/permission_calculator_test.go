@@ -0,0 +1,74 @@
+package discord
+
+import (
+	"testing"
+	"time"
+
+	"github.com/disgoorg/json"
+	"github.com/disgoorg/snowflake/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func unmarshalTestChannel(t *testing.T, data string) GuildChannel {
+	var channel UnmarshalChannel
+	assert.NoError(t, json.Unmarshal([]byte(data), &channel))
+	return channel.Channel.(GuildChannel)
+}
+
+func TestComputePermissions(t *testing.T) {
+	roles := []Role{
+		{ID: 1, Permissions: PermissionViewChannel | PermissionSendMessages | PermissionEmbedLinks},
+		{ID: 2, Permissions: PermissionKickMembers},
+		{ID: 3, Permissions: PermissionAdministrator},
+	}
+	// @everyone can't send messages, role 2 can
+	channel := unmarshalTestChannel(t, `{"id":"10","type":0,"guild_id":"1","permission_overwrites":[
+		{"id":"1","type":0,"allow":"0","deny":"2048"},
+		{"id":"2","type":0,"allow":"2048","deny":"0"}
+	]}`)
+	thread := unmarshalTestChannel(t, `{"id":"11","type":11,"guild_id":"1","parent_id":"10","thread_metadata":{}}`)
+
+	member := Member{User: User{ID: 100}, RoleIDs: []snowflake.ID{2}}
+
+	trace := ComputePermissions(PermissionInput{GuildID: 1, OwnerID: 99, Member: member, Roles: roles})
+	assert.Equal(t, PermissionViewChannel|PermissionSendMessages|PermissionEmbedLinks|PermissionKickMembers, trace.Permissions)
+
+	trace = ComputePermissions(PermissionInput{GuildID: 1, OwnerID: 99, Member: member, Roles: roles, Channel: channel})
+	assert.True(t, trace.Permissions.Has(PermissionSendMessages))
+	steps := trace.Explain(PermissionSendMessages)
+	if assert.Len(t, steps, 3) {
+		assert.Equal(t, PermissionSourceRole, steps[0].Source)
+		assert.Equal(t, PermissionSourceEveryoneOverwrite, steps[1].Source)
+		assert.Equal(t, PermissionSourceRoleOverwrite, steps[2].Source)
+		assert.Equal(t, snowflake.ID(2), steps[2].ID)
+	}
+
+	// without role 2 SendMessages is denied & EmbedLinks implicitly with it
+	trace = ComputePermissions(PermissionInput{GuildID: 1, OwnerID: 99, Member: Member{User: User{ID: 100}}, Roles: roles, Channel: channel})
+	assert.False(t, trace.Permissions.Has(PermissionSendMessages))
+	assert.False(t, trace.Permissions.Has(PermissionEmbedLinks))
+	steps = trace.Explain(PermissionEmbedLinks)
+	if assert.Len(t, steps, 2) {
+		assert.Equal(t, PermissionSourceImplicit, steps[1].Source)
+		assert.Equal(t, PermissionSendMessages, steps[1].Required)
+	}
+
+	// threads inherit the overwrites of their parent & require SendMessagesInThreads
+	trace = ComputePermissions(PermissionInput{GuildID: 1, OwnerID: 99, Member: member, Roles: roles, Channel: thread, ParentChannel: channel})
+	assert.True(t, trace.Permissions.Has(PermissionSendMessages))
+	assert.False(t, trace.Permissions.Has(PermissionEmbedLinks))
+
+	now := time.Now()
+	until := now.Add(time.Hour)
+	member.CommunicationDisabledUntil = &until
+	trace = ComputePermissions(PermissionInput{GuildID: 1, OwnerID: 99, Member: member, Roles: roles, Channel: channel, Now: now})
+	assert.Equal(t, PermissionViewChannel, trace.Permissions)
+	assert.Equal(t, PermissionSourceTimeout, trace.Steps[len(trace.Steps)-1].Source)
+
+	member.RoleIDs = []snowflake.ID{3}
+	trace = ComputePermissions(PermissionInput{GuildID: 1, OwnerID: 99, Member: member, Roles: roles, Channel: channel, Now: now})
+	assert.Equal(t, PermissionsAll, trace.Permissions)
+
+	trace = ComputePermissions(PermissionInput{GuildID: 1, OwnerID: 100, Member: Member{User: User{ID: 100}}})
+	assert.Equal(t, PermissionsAll, trace.Permissions)
+}
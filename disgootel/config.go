@@ -0,0 +1,57 @@
+package disgootel
+
+import (
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// DefaultConfig returns a Config with sensible defaults.
+func DefaultConfig() *Config {
+	return &Config{
+		TracerProvider: otel.GetTracerProvider(),
+		MeterProvider:  otel.GetMeterProvider(),
+	}
+}
+
+// Config lets you configure the Instrumentation.
+type Config struct {
+	// TracerProvider is used to create the trace.Tracer. Defaults to otel.GetTracerProvider().
+	TracerProvider trace.TracerProvider
+	// MeterProvider is used to create the metric.Meter. Defaults to otel.GetMeterProvider().
+	MeterProvider metric.MeterProvider
+	// Attributes are added to all spans & metrics, e.g. to distinguish multiple bots.
+	Attributes []attribute.KeyValue
+}
+
+// ConfigOpt can be used to supply optional parameters to New.
+type ConfigOpt func(config *Config)
+
+// Apply applies the given ConfigOpt(s) to the Config.
+func (c *Config) Apply(opts []ConfigOpt) {
+	for _, opt := range opts {
+		opt(c)
+	}
+}
+
+// WithTracerProvider sets the trace.TracerProvider used to create spans.
+func WithTracerProvider(tracerProvider trace.TracerProvider) ConfigOpt {
+	return func(config *Config) {
+		config.TracerProvider = tracerProvider
+	}
+}
+
+// WithMeterProvider sets the metric.MeterProvider used to create metrics.
+func WithMeterProvider(meterProvider metric.MeterProvider) ConfigOpt {
+	return func(config *Config) {
+		config.MeterProvider = meterProvider
+	}
+}
+
+// WithAttributes adds attributes to all spans & metrics.
+func WithAttributes(attributes ...attribute.KeyValue) ConfigOpt {
+	return func(config *Config) {
+		config.Attributes = append(config.Attributes, attributes...)
+	}
+}
@@ -0,0 +1,104 @@
+// Package disgootel provides OpenTelemetry tracing & metrics for disgo.
+//
+// It instruments:
+//   - rest requests with spans & a duration histogram via a rest.Middleware
+//   - gateway dispatch latency & reconnects via gateway.ConfigOpt(s)
+//   - the execution time of bot.EventListener(s) via a bot.ListenerMiddleware
+//   - handler.Mux routes with spans & a duration histogram via a handler.Middleware
+//
+// The span of an interaction is set as handler.InteractionEvent.Ctx & handler.InteractionEvent.RestCtx, which is passed to all REST calls made through the event.
+// This makes responses & follow-ups children of the interaction span.
+//
+//	instrumentation, err := disgootel.New()
+//	client, err := disgo.New(token, instrumentation.ConfigOpts()...)
+//	mux := handler.New()
+//	mux.Use(instrumentation.HandlerMiddleware())
+package disgootel
+
+import (
+	"fmt"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/disgoorg/disgo"
+	"github.com/disgoorg/disgo/bot"
+)
+
+// ScopeName is the instrumentation scope name of all spans & metrics.
+const ScopeName = "github.com/disgoorg/disgo/disgootel"
+
+// New returns a new Instrumentation with the given ConfigOpt(s) applied.
+func New(opts ...ConfigOpt) (*Instrumentation, error) {
+	config := DefaultConfig()
+	config.Apply(opts)
+
+	tracer := config.TracerProvider.Tracer(ScopeName, trace.WithInstrumentationVersion(disgo.Version))
+	meter := config.MeterProvider.Meter(ScopeName, metric.WithInstrumentationVersion(disgo.Version))
+
+	i := &Instrumentation{
+		tracer:     tracer,
+		attributes: config.Attributes,
+	}
+
+	var err error
+	if i.restDuration, err = meter.Float64Histogram("disgo.rest.request.duration",
+		metric.WithUnit("s"),
+		metric.WithDescription("Duration of REST requests including rate limits & retries."),
+	); err != nil {
+		return nil, fmt.Errorf("failed to create rest request duration histogram: %w", err)
+	}
+	if i.dispatchDuration, err = meter.Float64Histogram("disgo.gateway.dispatch.duration",
+		metric.WithUnit("s"),
+		metric.WithDescription("Duration from receiving a gateway dispatch event until it was handled."),
+	); err != nil {
+		return nil, fmt.Errorf("failed to create gateway dispatch duration histogram: %w", err)
+	}
+	if i.reconnects, err = meter.Int64Counter("disgo.gateway.reconnects",
+		metric.WithUnit("{reconnect}"),
+		metric.WithDescription("Number of attempts to reconnect to the gateway."),
+	); err != nil {
+		return nil, fmt.Errorf("failed to create gateway reconnects counter: %w", err)
+	}
+	if i.listenerDuration, err = meter.Float64Histogram("disgo.event.listener.duration",
+		metric.WithUnit("s"),
+		metric.WithDescription("Execution time of event listeners."),
+	); err != nil {
+		return nil, fmt.Errorf("failed to create event listener duration histogram: %w", err)
+	}
+	if i.interactionDuration, err = meter.Float64Histogram("disgo.interaction.duration",
+		metric.WithUnit("s"),
+		metric.WithDescription("Duration of handling interactions by the handler.Mux."),
+	); err != nil {
+		return nil, fmt.Errorf("failed to create interaction duration histogram: %w", err)
+	}
+	return i, nil
+}
+
+// Instrumentation creates the spans & metrics of disgo.
+type Instrumentation struct {
+	tracer     trace.Tracer
+	attributes []attribute.KeyValue
+
+	restDuration        metric.Float64Histogram
+	dispatchDuration    metric.Float64Histogram
+	reconnects          metric.Int64Counter
+	listenerDuration    metric.Float64Histogram
+	interactionDuration metric.Float64Histogram
+}
+
+// ConfigOpts returns the bot.ConfigOpt(s) which instrument the rest client, the gateway & the bot.EventManager of a bot.Client.
+// The gateway.ConfigOpt(s) of a sharding.ShardManager have to be set with GatewayConfigOpts instead.
+func (i *Instrumentation) ConfigOpts() []bot.ConfigOpt {
+	return []bot.ConfigOpt{
+		bot.WithRestClientConfigOpts(i.RestConfigOpts()...),
+		bot.WithGatewayConfigOpts(i.GatewayConfigOpts()...),
+		bot.WithEventManagerConfigOpts(i.EventManagerConfigOpts()...),
+	}
+}
+
+// with returns the given attributes with the configured attributes appended.
+func (i *Instrumentation) with(attributes ...attribute.KeyValue) []attribute.KeyValue {
+	return append(attributes, i.attributes...)
+}
@@ -0,0 +1,170 @@
+package disgootel
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/attribute"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/disgoorg/disgo/bot"
+	"github.com/disgoorg/disgo/discord"
+	"github.com/disgoorg/disgo/events"
+	"github.com/disgoorg/disgo/handler"
+	"github.com/disgoorg/disgo/rest"
+)
+
+const slashCommandInteraction = `{
+	"type": 2,
+	"token": "token",
+	"id": "786008729715212338",
+	"application_id": "771825006014889990",
+	"guild_id": "290926798626357999",
+	"channel_id": "645027906669510667",
+	"member": {
+		"user": {"id": "53908232506183680", "username": "test"},
+		"roles": [],
+		"permissions": "0",
+		"joined_at": "2017-03-13T19:19:14.040000+00:00"
+	},
+	"data": {"type": 1, "name": "foo", "id": "771825006014889984", "options": [{"type": 1, "name": "bar"}]}
+}`
+
+func newTestInstrumentation(t *testing.T) (*Instrumentation, *tracetest.SpanRecorder, *sdkmetric.ManualReader) {
+	spans := tracetest.NewSpanRecorder()
+	reader := sdkmetric.NewManualReader()
+	instrumentation, err := New(
+		WithTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(spans))),
+		WithMeterProvider(sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))),
+		WithAttributes(attribute.String("bot", "test")),
+	)
+	assert.NoError(t, err)
+	return instrumentation, spans, reader
+}
+
+// metricNames returns the names of all metrics collected by the reader.
+func metricNames(t *testing.T, reader *sdkmetric.ManualReader) []string {
+	var data metricdata.ResourceMetrics
+	assert.NoError(t, reader.Collect(context.Background(), &data))
+	var names []string
+	for _, scope := range data.ScopeMetrics {
+		for _, m := range scope.Metrics {
+			names = append(names, m.Name)
+		}
+	}
+	return names
+}
+
+func attributeValue(attributes []attribute.KeyValue, key attribute.Key) attribute.Value {
+	for _, kv := range attributes {
+		if kv.Key == key {
+			return kv.Value
+		}
+	}
+	return attribute.Value{}
+}
+
+func TestRestMiddleware(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("X-RateLimit-Bucket", "abc")
+		w.Header().Set("X-RateLimit-Limit", "5")
+		w.Header().Set("X-RateLimit-Remaining", "4")
+		w.Header().Set("X-RateLimit-Reset-After", "1")
+		_, _ = w.Write([]byte(`{"id":"1","content":"test"}`))
+	}))
+	t.Cleanup(server.Close)
+
+	instrumentation, spans, reader := newTestInstrumentation(t)
+	client := rest.NewClient("token", append(instrumentation.RestConfigOpts(), rest.WithURL(server.URL))...)
+
+	ctx, parent := instrumentation.tracer.Start(context.Background(), "parent")
+	_, err := rest.NewChannels(client).GetMessage(1, 2, rest.WithCtx(ctx))
+	parent.End()
+	assert.NoError(t, err)
+
+	ended := spans.Ended()
+	if !assert.Len(t, ended, 2) {
+		return
+	}
+	span := ended[0]
+	assert.Equal(t, "GET /channels/{channel.id}/messages/{message.id}", span.Name())
+	assert.Equal(t, trace.SpanKindClient, span.SpanKind())
+	assert.Equal(t, parent.SpanContext().SpanID(), span.Parent().SpanID())
+	assert.Equal(t, "abc", attributeValue(span.Attributes(), RestBucketKey).AsString())
+	assert.Equal(t, int64(http.StatusOK), attributeValue(span.Attributes(), "http.response.status_code").AsInt64())
+	assert.Equal(t, "test", attributeValue(span.Attributes(), "bot").AsString())
+	assert.Contains(t, metricNames(t, reader), "disgo.rest.request.duration")
+}
+
+func TestHandlerMiddleware(t *testing.T) {
+	instrumentation, spans, reader := newTestInstrumentation(t)
+
+	mux := handler.New()
+	// the event context is canceled once the handler returned
+	mux.Use(func(next handler.Handler) handler.Handler {
+		return func(event *handler.InteractionEvent) error {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			event.Ctx = ctx
+			return next(event)
+		}
+	})
+	mux.Use(instrumentation.HandlerMiddleware())
+	mux.Route("/foo", func(r handler.Router) {
+		r.Command("/bar", func(e *handler.CommandEvent) error {
+			return e.CreateMessage(discord.MessageCreate{Content: "test"})
+		})
+	})
+
+	interaction, err := discord.UnmarshalInteraction([]byte(slashCommandInteraction))
+	if !assert.NoError(t, err) {
+		return
+	}
+	var respondCtx context.Context
+	mux.OnEvent(&events.InteractionCreate{
+		GenericEvent: events.NewGenericEvent(nil, 0, 0),
+		Interaction:  interaction,
+		Respond: func(responseType discord.InteractionResponseType, data discord.InteractionResponseData, opts ...rest.RequestOpt) error {
+			config := rest.DefaultRequestConfig(&http.Request{Header: http.Header{}})
+			config.Apply(opts)
+			respondCtx = config.Ctx
+			return nil
+		},
+	})
+
+	ended := spans.Ended()
+	if !assert.Len(t, ended, 1) {
+		return
+	}
+	span := ended[0]
+	assert.Equal(t, "interaction /foo/bar", span.Name())
+	assert.Equal(t, trace.SpanKindServer, span.SpanKind())
+	assert.Equal(t, "/foo/bar", attributeValue(span.Attributes(), InteractionPatternKey).AsString())
+	assert.Equal(t, "290926798626357999", attributeValue(span.Attributes(), GuildIDKey).AsString())
+	if assert.NotNil(t, respondCtx) {
+		assert.Equal(t, span.SpanContext().SpanID(), trace.SpanContextFromContext(respondCtx).SpanID())
+		assert.NoError(t, respondCtx.Err())
+	}
+	assert.Contains(t, metricNames(t, reader), "disgo.interaction.duration")
+}
+
+func TestListenerMiddleware(t *testing.T) {
+	instrumentation, _, reader := newTestInstrumentation(t)
+
+	var called bool
+	eventManager := bot.NewEventManager(nil, append(instrumentation.EventManagerConfigOpts(), bot.WithListenerFunc(func(e *events.Ready) {
+		called = true
+	}))...)
+	eventManager.DispatchEvent(&events.Ready{GenericEvent: events.NewGenericEvent(nil, 0, 0)})
+
+	assert.True(t, called)
+	assert.Contains(t, metricNames(t, reader), "disgo.event.listener.duration")
+}
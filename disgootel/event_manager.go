@@ -0,0 +1,40 @@
+package disgootel
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+
+	"github.com/disgoorg/disgo/bot"
+)
+
+const (
+	// EventTypeKey is the attribute key of the Go type of a bot.Event, e.g. *events.MessageCreate.
+	EventTypeKey = attribute.Key("disgo.event.type")
+	// EventListenerKey is the attribute key of the Go type of a bot.EventListener.
+	EventListenerKey = attribute.Key("disgo.event.listener")
+)
+
+// EventManagerConfigOpts returns the bot.EventManagerConfigOpt(s) which record the execution time of bot.EventListener(s).
+func (i *Instrumentation) EventManagerConfigOpts() []bot.EventManagerConfigOpt {
+	return []bot.EventManagerConfigOpt{bot.WithListenerMiddlewares(i.ListenerMiddleware())}
+}
+
+// ListenerMiddleware returns a bot.ListenerMiddleware which records the execution time of bot.EventListener(s) by event & listener type.
+func (i *Instrumentation) ListenerMiddleware() bot.ListenerMiddleware {
+	return func(next bot.ListenerHandler) bot.ListenerHandler {
+		return func(listener bot.EventListener, event bot.Event) {
+			start := time.Now()
+			defer func() {
+				i.listenerDuration.Record(context.Background(), time.Since(start).Seconds(), metric.WithAttributes(i.with(
+					EventTypeKey.String(fmt.Sprintf("%T", event)),
+					EventListenerKey.String(fmt.Sprintf("%T", listener)),
+				)...))
+			}()
+			next(listener, event)
+		}
+	}
+}
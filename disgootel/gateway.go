@@ -0,0 +1,41 @@
+package disgootel
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+
+	"github.com/disgoorg/disgo/gateway"
+)
+
+const (
+	// GatewayShardIDKey is the attribute key of the shard ID of a gateway metric.
+	GatewayShardIDKey = attribute.Key("discord.gateway.shard_id")
+	// GatewayEventTypeKey is the attribute key of the gateway.EventType of a dispatch event.
+	GatewayEventTypeKey = attribute.Key("discord.gateway.event_type")
+	// GatewayReconnectSuccessKey is the attribute key of whether a reconnect attempt succeeded.
+	GatewayReconnectSuccessKey = attribute.Key("discord.gateway.reconnect.success")
+)
+
+// GatewayConfigOpts returns the gateway.ConfigOpt(s) which record the dispatch latency & reconnects of a gateway.Gateway.
+func (i *Instrumentation) GatewayConfigOpts() []gateway.ConfigOpt {
+	return []gateway.ConfigOpt{
+		gateway.WithDispatchHook(i.onDispatch),
+		gateway.WithReconnectHook(i.onReconnect),
+	}
+}
+
+func (i *Instrumentation) onDispatch(event gateway.DispatchEvent) {
+	i.dispatchDuration.Record(context.Background(), event.Duration.Seconds(), metric.WithAttributes(i.with(
+		GatewayShardIDKey.Int(event.ShardID),
+		GatewayEventTypeKey.String(string(event.EventType)),
+	)...))
+}
+
+func (i *Instrumentation) onReconnect(event gateway.ReconnectEvent) {
+	i.reconnects.Add(context.Background(), 1, metric.WithAttributes(i.with(
+		GatewayShardIDKey.Int(event.ShardID),
+		GatewayReconnectSuccessKey.Bool(event.Err == nil),
+	)...))
+}
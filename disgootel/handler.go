@@ -0,0 +1,86 @@
+package disgootel
+
+import (
+	"context"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/disgoorg/disgo/handler"
+)
+
+const (
+	// InteractionIDKey is the attribute key of the ID of an interaction.
+	InteractionIDKey = attribute.Key("discord.interaction.id")
+	// InteractionTypeKey is the attribute key of the discord.InteractionType of an interaction.
+	InteractionTypeKey = attribute.Key("discord.interaction.type")
+	// InteractionPatternKey is the attribute key of the pattern of the handler.Mux route which handled an interaction.
+	InteractionPatternKey = attribute.Key("discord.interaction.pattern")
+	// GuildIDKey is the attribute key of the ID of the guild an interaction was created in.
+	GuildIDKey = attribute.Key("discord.guild.id")
+	// ChannelIDKey is the attribute key of the ID of the channel an interaction was created in.
+	ChannelIDKey = attribute.Key("discord.channel.id")
+	// UserIDKey is the attribute key of the ID of the user who created an interaction.
+	UserIDKey = attribute.Key("discord.user.id")
+)
+
+// HandlerMiddleware returns a handler.Middleware which creates a span for each interaction & records its duration.
+// The span is set as handler.InteractionEvent.Ctx. It is also set as handler.InteractionEvent.RestCtx without the cancellation of the event context,
+// so all REST calls made through the event are its children, even followup messages sent after the handler returned.
+// It should be the first middleware of the root handler.Mux to cover the whole routing.
+func (i *Instrumentation) HandlerMiddleware() handler.Middleware {
+	return func(next handler.Handler) handler.Handler {
+		return func(event *handler.InteractionEvent) error {
+			parent := event.Ctx
+			if parent == nil {
+				parent = context.Background()
+			}
+
+			spanAttributes := []attribute.KeyValue{
+				InteractionIDKey.String(event.Interaction.ID().String()),
+				InteractionTypeKey.Int(int(event.Type())),
+				UserIDKey.String(event.User().ID.String()),
+			}
+			if guildID := event.GuildID(); guildID != nil {
+				spanAttributes = append(spanAttributes, GuildIDKey.String(guildID.String()))
+			}
+			if channelID := event.ChannelID(); channelID != 0 {
+				spanAttributes = append(spanAttributes, ChannelIDKey.String(channelID.String()))
+			}
+
+			ctx, span := i.tracer.Start(parent, "interaction",
+				trace.WithSpanKind(trace.SpanKindServer),
+				trace.WithAttributes(i.with(spanAttributes...)...),
+			)
+			defer span.End()
+			event.Ctx = ctx
+			restParent := event.RestCtx
+			if restParent == nil {
+				restParent = context.Background()
+			}
+			event.RestCtx = trace.ContextWithSpan(restParent, span)
+
+			start := time.Now()
+			err := next(event)
+			duration := time.Since(start)
+
+			attributes := i.with(
+				InteractionTypeKey.Int(int(event.Type())),
+				InteractionPatternKey.String(event.Pattern),
+			)
+			if event.Pattern != "" {
+				span.SetName("interaction " + event.Pattern)
+			}
+			if err != nil {
+				span.RecordError(err)
+				span.SetStatus(codes.Error, err.Error())
+			}
+			span.SetAttributes(InteractionPatternKey.String(event.Pattern))
+			i.interactionDuration.Record(ctx, duration.Seconds(), metric.WithAttributes(append(attributes, attribute.Bool("error", err != nil))...))
+			return err
+		}
+	}
+}
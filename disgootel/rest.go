@@ -0,0 +1,81 @@
+package disgootel
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/disgoorg/disgo/rest"
+)
+
+// RestBucketKey is the attribute key of the rate limit bucket of a REST request.
+const RestBucketKey = attribute.Key("discord.rest.bucket")
+
+// RestConfigOpts returns the rest.ConfigOpt(s) which instrument a rest.Client.
+func (i *Instrumentation) RestConfigOpts() []rest.ConfigOpt {
+	return []rest.ConfigOpt{rest.WithMiddleware(i.RestMiddleware())}
+}
+
+// RestMiddleware returns a rest.Middleware which creates a span for each request & records its duration.
+// The span is a child of the context set with rest.WithCtx.
+func (i *Instrumentation) RestMiddleware() rest.Middleware {
+	return func(next rest.Handler) rest.Handler {
+		return func(call *rest.Call) error {
+			endpoint := call.Endpoint.Endpoint
+			attributes := i.with(
+				semconv.HTTPRequestMethodKey.String(endpoint.Method),
+				semconv.HTTPRoute(endpoint.Route),
+			)
+
+			ctx, span := i.tracer.Start(call.Ctx(), endpoint.Method+" "+endpoint.Route,
+				trace.WithSpanKind(trace.SpanKindClient),
+				trace.WithAttributes(attributes...),
+			)
+			defer span.End()
+			call.SetCtx(ctx)
+
+			start := time.Now()
+			err := next(call)
+			duration := time.Since(start)
+
+			if rs := call.Response; rs != nil {
+				attributes = append(attributes, semconv.HTTPResponseStatusCode(rs.StatusCode))
+				if bucket := rs.Header.Get("X-RateLimit-Bucket"); bucket != "" {
+					span.SetAttributes(RestBucketKey.String(bucket))
+				}
+			}
+			if err != nil {
+				span.RecordError(err)
+				span.SetStatus(codes.Error, err.Error())
+				attributes = append(attributes, semconv.ErrorTypeKey.String(errorType(err)))
+			}
+			span.SetAttributes(attributes...)
+			i.restDuration.Record(ctx, duration.Seconds(), metric.WithAttributes(attributes...))
+			return err
+		}
+	}
+}
+
+// errorType returns the error.type attribute value of a failed request.
+func errorType(err error) string {
+	var restErr *rest.Error
+	switch {
+	case errors.As(err, &restErr) && restErr.Response != nil:
+		return strconv.Itoa(restErr.Response.StatusCode)
+	case errors.Is(err, context.DeadlineExceeded):
+		return "timeout"
+	case errors.Is(err, context.Canceled):
+		return "canceled"
+	case errors.Is(err, rest.ErrTooManyInvalidRequests):
+		return "too_many_invalid_requests"
+	default:
+		return semconv.ErrorTypeOther.Value.AsString()
+	}
+}
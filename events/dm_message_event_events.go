@@ -23,9 +23,15 @@ type DMMessageCreate struct {
 type DMMessageUpdate struct {
 	*GenericDMMessage
 	OldMessage discord.Message
+	// Revisions contains all revisions of the message known to the cache.MessageHistory, oldest first and ending with the updated message.
+	// It is nil if no cache.MessageHistory is configured.
+	Revisions []discord.Message
 }
 
 // DMMessageDelete is called upon deleting a discord.Message in a Channel (requires gateway.IntentsDirectMessage)
 type DMMessageDelete struct {
 	*GenericDMMessage
+	// Revisions contains all revisions of the deleted message known to the cache.MessageHistory, oldest first.
+	// It is nil if no cache.MessageHistory is configured.
+	Revisions []discord.Message
 }
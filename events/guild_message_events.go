@@ -35,9 +35,15 @@ type GuildMessageCreate struct {
 type GuildMessageUpdate struct {
 	*GenericGuildMessage
 	OldMessage discord.Message
+	// Revisions contains all revisions of the message known to the cache.MessageHistory, oldest first and ending with the updated message.
+	// It is nil if no cache.MessageHistory is configured.
+	Revisions []discord.Message
 }
 
 // GuildMessageDelete is called upon deleting a discord.Message in a Channel
 type GuildMessageDelete struct {
 	*GenericGuildMessage
+	// Revisions contains all revisions of the deleted message known to the cache.MessageHistory, oldest first.
+	// It is nil if no cache.MessageHistory is configured.
+	Revisions []discord.Message
 }
@@ -17,6 +17,9 @@ type ListenerAdapter struct {
 	// heartbeat ack event
 	OnHeartbeatAck func(event *HeartbeatAck)
 
+	// session start limit event
+	OnSessionStartLimit func(event *SessionStartLimit)
+
 	// GuildApplicationCommandPermissionsUpdate
 	OnGuildApplicationCommandPermissionsUpdate func(event *GuildApplicationCommandPermissionsUpdate)
 
@@ -205,6 +208,11 @@ func (l *ListenerAdapter) OnEvent(event bot.Event) {
 			listener(e)
 		}
 
+	case *SessionStartLimit:
+		if listener := l.OnSessionStartLimit; listener != nil {
+			listener(e)
+		}
+
 	case *GuildApplicationCommandPermissionsUpdate:
 		if listener := l.OnGuildApplicationCommandPermissionsUpdate; listener != nil {
 			listener(e)
@@ -37,9 +37,15 @@ type MessageCreate struct {
 type MessageUpdate struct {
 	*GenericMessage
 	OldMessage discord.Message
+	// Revisions contains all revisions of the message known to the cache.MessageHistory, oldest first and ending with the updated message.
+	// It is nil if no cache.MessageHistory is configured.
+	Revisions []discord.Message
 }
 
 // MessageDelete indicates that a discord.Message got deleted
 type MessageDelete struct {
 	*GenericMessage
+	// Revisions contains all revisions of the deleted message known to the cache.MessageHistory, oldest first.
+	// It is nil if no cache.MessageHistory is configured.
+	Revisions []discord.Message
 }
@@ -0,0 +1,9 @@
+package events
+
+import "github.com/disgoorg/disgo/gateway"
+
+// SessionStartLimit is dispatched when the sharding.ShardManager fetched the remaining session starts before opening its shards.
+type SessionStartLimit struct {
+	*GenericEvent
+	gateway.EventSessionStartLimit
+}
@@ -83,6 +83,7 @@ type Gateway interface {
 	Open(ctx context.Context) error
 
 	// Close gracefully closes the Gateway with the websocket.CloseNormalClosure code.
+	// If a SessionStore is configured, the Gateway is closed with the websocket.CloseServiceRestart code instead and the session is saved, so it can be resumed later.
 	// If the context is done, the Gateway connection will be killed.
 	Close(ctx context.Context)
 
@@ -104,3 +105,18 @@ type Gateway interface {
 	// Presence returns the current presence of the Gateway.
 	Presence() *MessageDataPresenceUpdate
 }
+
+// Handoffer is implemented by Gateways which can hand off their Session to another Gateway.
+// Use a type assertion to check whether a Gateway supports it.
+type Handoffer interface {
+	// Handoff stops dispatching events and returns the current Session, so another Gateway can resume it.
+	// Events received afterward are replayed by Discord to the Gateway resuming the Session.
+	// The Gateway stays connected without reconnecting until it's closed, which always uses the websocket.CloseServiceRestart code to keep the Session resumable.
+	// Returns discord.ErrGatewayNoSession if the Gateway has no Session yet.
+	Handoff() (Session, error)
+
+	// CancelHandoff takes back the Session after a failed Handoff.
+	// The Gateway reconnects & resumes the Session, so Discord replays the events received since Handoff.
+	// It must not be called after another Gateway resumed the Session.
+	CancelHandoff(ctx context.Context) error
+}
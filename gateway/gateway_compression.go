@@ -0,0 +1,168 @@
+package gateway
+
+import (
+	"bytes"
+	"compress/zlib"
+	"errors"
+	"io"
+	"sync"
+)
+
+// CompressionType is the transport compression the Gateway requests from Discord.
+// See here for more information: https://discord.com/developers/docs/topics/gateway#transport-compression
+type CompressionType string
+
+const (
+	// CompressionNone disables transport compression. Payload compression can still be enabled via WithCompress.
+	CompressionNone CompressionType = ""
+
+	// CompressionZlibStream enables zlib-stream transport compression.
+	// All payloads of a connection share one inflate context which is reset on every reconnect.
+	CompressionZlibStream CompressionType = "zlib-stream"
+)
+
+// zlibHeader is the first byte of zlib data using deflate with a 32K window, which is what Discord uses for payload compression.
+const zlibHeader = 0x78
+
+// zlibSuffix is the Z_SYNC_FLUSH suffix Discord appends to the end of every complete zlib-stream payload.
+var zlibSuffix = []byte{0x00, 0x00, 0xff, 0xff}
+
+var errZlibStreamClosed = errors.New("zlib-stream inflater closed")
+
+var _ io.ByteReader = (*zlibStreamInflater)(nil)
+
+// newZlibStreamInflater creates a new zlibStreamInflater and starts its inflate goroutine.
+// A zlibStreamInflater must only be used for a single connection and closed afterward.
+func newZlibStreamInflater() *zlibStreamInflater {
+	i := &zlibStreamInflater{
+		payloads: make(chan []byte),
+		flushed:  make(chan struct{}),
+		closed:   make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+	go i.run()
+	return i
+}
+
+// zlibStreamInflater keeps one shared zlib inflate context for all payloads of a connection.
+//
+// compress/flate treats running out of input as a fatal error, so the inflate context lives in its own goroutine and blocks
+// when it needs more input. As every payload ends with a Z_SYNC_FLUSH, the inflate context asking for more input means
+// all output of the current payload has been produced.
+type zlibStreamInflater struct {
+	// buf holds incomplete payloads until the zlibSuffix is received.
+	buf []byte
+
+	payloads chan []byte
+	flushed  chan struct{}
+	closed   chan struct{}
+	closeMu  sync.Once
+	done     chan struct{}
+
+	// the fields below are owned by the inflate goroutine while a payload is being inflated.
+	started bool
+	current []byte
+	out     []byte
+	err     error
+}
+
+// inflate feeds the given websocket payload into the shared inflate context.
+// It returns false if the payload is not complete yet and more websocket payloads are required.
+// The returned data is only valid until the next call to inflate.
+func (i *zlibStreamInflater) inflate(data []byte) ([]byte, bool, error) {
+	i.buf = append(i.buf, data...)
+	if !bytes.HasSuffix(i.buf, zlibSuffix) {
+		return nil, false, nil
+	}
+	defer func() {
+		i.buf = i.buf[:0]
+	}()
+
+	i.out = i.out[:0]
+	select {
+	case i.payloads <- i.buf:
+	case <-i.done:
+		return nil, false, i.err
+	}
+
+	select {
+	case <-i.flushed:
+		return i.out, true, nil
+	case <-i.done:
+		return nil, false, i.err
+	}
+}
+
+// close stops the inflate goroutine. It is safe to call close multiple times.
+func (i *zlibStreamInflater) close() {
+	i.closeMu.Do(func() {
+		close(i.closed)
+	})
+}
+
+func (i *zlibStreamInflater) run() {
+	defer close(i.done)
+
+	r, err := zlib.NewReader(i)
+	if err != nil {
+		i.err = err
+		return
+	}
+	defer r.Close()
+
+	buf := make([]byte, 32*1024)
+	for {
+		n, err := r.Read(buf)
+		i.out = append(i.out, buf[:n]...)
+		if err != nil {
+			i.err = err
+			return
+		}
+	}
+}
+
+// next blocks until the next payload is available.
+// If the inflate context already consumed a payload, this signals that all output for it has been produced.
+func (i *zlibStreamInflater) next() error {
+	if i.started {
+		select {
+		case i.flushed <- struct{}{}:
+		case <-i.closed:
+			return errZlibStreamClosed
+		}
+	}
+
+	select {
+	case payload := <-i.payloads:
+		i.started = true
+		i.current = payload
+		return nil
+	case <-i.closed:
+		return errZlibStreamClosed
+	}
+}
+
+// Read implements io.Reader for the inflate context.
+func (i *zlibStreamInflater) Read(p []byte) (int, error) {
+	for len(i.current) == 0 {
+		if err := i.next(); err != nil {
+			return 0, err
+		}
+	}
+	n := copy(p, i.current)
+	i.current = i.current[n:]
+	return n, nil
+}
+
+// ReadByte implements io.ByteReader for the inflate context.
+// This prevents compress/flate from wrapping the inflater in a bufio.Reader which would read ahead.
+func (i *zlibStreamInflater) ReadByte() (byte, error) {
+	for len(i.current) == 0 {
+		if err := i.next(); err != nil {
+			return 0, err
+		}
+	}
+	b := i.current[0]
+	i.current = i.current[1:]
+	return b, nil
+}
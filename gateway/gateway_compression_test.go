@@ -0,0 +1,61 @@
+package gateway
+
+import (
+	"bytes"
+	"compress/zlib"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestZlibStreamInflater(t *testing.T) {
+	buf := new(bytes.Buffer)
+	w := zlib.NewWriter(buf)
+
+	compress := func(data []byte) []byte {
+		buf.Reset()
+		_, err := w.Write(data)
+		assert.NoError(t, err)
+		assert.NoError(t, w.Flush())
+		return bytes.Clone(buf.Bytes())
+	}
+
+	random := make([]byte, 100*1024)
+	rand.New(rand.NewSource(0)).Read(random)
+
+	payloads := [][]byte{
+		[]byte(`{"op":10,"d":{"heartbeat_interval":41250}}`),
+		[]byte(`{"op":11}`),
+		bytes.Repeat([]byte(`{"op":0,"t":"GUILD_CREATE"}`), 4096),
+		random,
+		[]byte(`{"op":11}`),
+	}
+
+	inflater := newZlibStreamInflater()
+	defer inflater.close()
+
+	for _, payload := range payloads {
+		compressed := compress(payload)
+
+		// split the payload to make sure we wait for the suffix
+		half := len(compressed) / 2
+		_, ok, err := inflater.inflate(compressed[:half])
+		assert.NoError(t, err)
+		assert.False(t, ok)
+
+		data, ok, err := inflater.inflate(compressed[half:])
+		assert.NoError(t, err)
+		assert.True(t, ok)
+		assert.Equal(t, payload, data)
+	}
+}
+
+func TestZlibStreamInflater_Corrupted(t *testing.T) {
+	inflater := newZlibStreamInflater()
+	defer inflater.close()
+
+	_, ok, err := inflater.inflate(append([]byte{0x78, 0x9c, 0xff, 0xff}, zlibSuffix...))
+	assert.Error(t, err)
+	assert.False(t, ok)
+}
@@ -14,6 +14,7 @@ func DefaultConfig() *Config {
 		LargeThreshold:  50,
 		Intents:         IntentsDefault,
 		Compress:        true,
+		Encoding:        NewJSONEncoding(),
 		URL:             "wss://gateway.discord.gg",
 		ShardID:         0,
 		ShardCount:      1,
@@ -35,6 +36,11 @@ type Config struct {
 	Intents Intents
 	// Compress is whether the Gateway should compress payloads. Defaults to true.
 	Compress bool
+	// Compression is the transport compression of the Gateway. Defaults to CompressionNone.
+	// Transport compression takes precedence over Compress.
+	Compression CompressionType
+	// Encoding is the Encoding of the Gateway. Defaults to NewJSONEncoding().
+	Encoding Encoding
 	// URL is the URL of the Gateway. Defaults to fetch from Discord.
 	URL string
 	// ShardID is the shardID of the Gateway. Defaults to 0.
@@ -47,6 +53,8 @@ type Config struct {
 	ResumeURL *string
 	// LastSequenceReceived is the last sequence received by the Gateway. Defaults to nil (no resume).
 	LastSequenceReceived *int
+	// SessionStore is used to persist the session on Close and to resume it on Open. Defaults to nil (no persistence).
+	SessionStore SessionStore
 	// AutoReconnect is whether the Gateway should automatically reconnect or call the CloseHandlerFunc. Defaults to true.
 	AutoReconnect bool
 	// EnableRawEvents is whether the Gateway should emit EventRaw. Defaults to false.
@@ -65,6 +73,10 @@ type Config struct {
 	Browser string
 	// Device is the Device it should send on login. Defaults to "disgo".
 	Device string
+	// OnDispatch is called after each dispatch event was handled. It can be used for metrics. Defaults to nil.
+	OnDispatch func(event DispatchEvent)
+	// OnReconnect is called after each attempt to reconnect the Gateway. It can be used for metrics. Defaults to nil.
+	OnReconnect func(event ReconnectEvent)
 }
 
 // ConfigOpt is a type alias for a function that takes a Config and is used to configure your Server.
@@ -118,6 +130,23 @@ func WithCompress(compress bool) ConfigOpt {
 	}
 }
 
+// WithCompression sets the transport compression for the Gateway.
+// Transport compression takes precedence over payload compression set via WithCompress.
+// See here for more information: https://discord.com/developers/docs/topics/gateway#transport-compression
+func WithCompression(compression CompressionType) ConfigOpt {
+	return func(config *Config) {
+		config.Compression = compression
+	}
+}
+
+// WithEncoding sets the Encoding for the Gateway.
+// See here for more information: https://discord.com/developers/docs/topics/gateway#encoding-and-compression
+func WithEncoding(encoding Encoding) ConfigOpt {
+	return func(config *Config) {
+		config.Encoding = encoding
+	}
+}
+
 // WithURL sets the Gateway URL for the Gateway.
 func WithURL(url string) ConfigOpt {
 	return func(config *Config) {
@@ -157,6 +186,26 @@ func WithSequence(sequence int) ConfigOpt {
 	}
 }
 
+// WithSession sets the Session ID, last sequence received and resume URL for the Gateway from the given Session.
+// The Gateway will try to resume the Session while connecting.
+func WithSession(session Session) ConfigOpt {
+	return func(config *Config) {
+		config.SessionID = &session.ID
+		config.LastSequenceReceived = &session.Sequence
+		if session.ResumeURL != "" {
+			config.ResumeURL = &session.ResumeURL
+		}
+	}
+}
+
+// WithSessionStore sets the SessionStore for the Gateway.
+// On Close the session is saved to the SessionStore and on Open the Gateway tries to resume the saved session before identifying.
+func WithSessionStore(sessionStore SessionStore) ConfigOpt {
+	return func(config *Config) {
+		config.SessionStore = sessionStore
+	}
+}
+
 // WithAutoReconnect sets whether the Gateway should automatically reconnect to Discord.
 func WithAutoReconnect(autoReconnect bool) ConfigOpt {
 	return func(config *Config) {
@@ -226,3 +275,17 @@ func WithDevice(device string) ConfigOpt {
 		config.Device = device
 	}
 }
+
+// WithDispatchHook sets a function which is called after each dispatch event was handled.
+func WithDispatchHook(onDispatch func(event DispatchEvent)) ConfigOpt {
+	return func(config *Config) {
+		config.OnDispatch = onDispatch
+	}
+}
+
+// WithReconnectHook sets a function which is called after each attempt to reconnect the Gateway.
+func WithReconnectHook(onReconnect func(event ReconnectEvent)) ConfigOpt {
+	return func(config *Config) {
+		config.OnReconnect = onReconnect
+	}
+}
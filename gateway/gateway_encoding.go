@@ -0,0 +1,47 @@
+package gateway
+
+import (
+	"github.com/disgoorg/json"
+	"github.com/gorilla/websocket"
+)
+
+// Encoding is used by the Gateway to encode sent and decode received messages.
+// See here for more information: https://discord.com/developers/docs/topics/gateway#encoding-and-compression
+type Encoding interface {
+	// Name returns the name of the Encoding which is sent to Discord via the encoding query parameter.
+	Name() string
+
+	// MessageType returns the websocket message type which is used to send encoded messages.
+	MessageType() int
+
+	// Marshal encodes the given value.
+	Marshal(v any) ([]byte, error)
+
+	// Unmarshal decodes the given data into v.
+	Unmarshal(data []byte, v any) error
+}
+
+var _ Encoding = (*jsonEncoding)(nil)
+
+// NewJSONEncoding returns the default json Encoding.
+func NewJSONEncoding() Encoding {
+	return &jsonEncoding{}
+}
+
+type jsonEncoding struct{}
+
+func (e *jsonEncoding) Name() string {
+	return "json"
+}
+
+func (e *jsonEncoding) MessageType() int {
+	return websocket.TextMessage
+}
+
+func (e *jsonEncoding) Marshal(v any) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+func (e *jsonEncoding) Unmarshal(data []byte, v any) error {
+	return json.Unmarshal(data, v)
+}
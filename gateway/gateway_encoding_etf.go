@@ -0,0 +1,547 @@
+package gateway
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/binary"
+	stdjson "encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"math/big"
+	"strconv"
+	"unicode/utf8"
+
+	"github.com/disgoorg/json"
+	"github.com/gorilla/websocket"
+)
+
+// External Term Format tags.
+// See here for more information: https://www.erlang.org/doc/apps/erts/erl_ext_dist.html
+const (
+	etfVersion = 131
+
+	etfNewFloat      = 70
+	etfCompressed    = 80
+	etfSmallInteger  = 97
+	etfInteger       = 98
+	etfFloat         = 99
+	etfAtom          = 100
+	etfSmallTuple    = 104
+	etfLargeTuple    = 105
+	etfNil           = 106
+	etfString        = 107
+	etfList          = 108
+	etfBinary        = 109
+	etfSmallBig      = 110
+	etfLargeBig      = 111
+	etfSmallAtom     = 115
+	etfMap           = 116
+	etfAtomUTF8      = 118
+	etfSmallAtomUTF8 = 119
+)
+
+// etfMaxUncompressedSize is the maximum size of a compressed term after decompression.
+const etfMaxUncompressedSize = 64 << 20
+
+var (
+	errETFInvalidVersion = errors.New("invalid etf version")
+	errETFUnexpectedEnd  = errors.New("unexpected end of etf data")
+	errETFTooLarge       = errors.New("compressed etf term is too large")
+)
+
+var _ Encoding = (*etfEncoding)(nil)
+
+// NewETFEncoding returns an Encoding which uses Erlang's External Term Format.
+//
+// Received terms are converted to JSON before being decoded, which means all Message and EventData types work the same as with the json Encoding
+// and EventRaw payloads stay JSON.
+// As Discord sends snowflakes as integers when using ETF, integers which do not fit into the precision of a float64 are converted to quoted strings
+// like the json Encoding sends snowflakes, regardless of the field they are in.
+//
+// Because of the conversion, decoding ETF takes longer than decoding the same payload as JSON (about 20% for a GUILD_MEMBERS_CHUNK).
+// ETF only reduces the size of received payloads, which is useful when the connection and not the CPU is the bottleneck.
+// See here for more information: https://discord.com/developers/docs/topics/gateway#etfjson
+func NewETFEncoding() Encoding {
+	return &etfEncoding{}
+}
+
+type etfEncoding struct{}
+
+func (e *etfEncoding) Name() string {
+	return "etf"
+}
+
+func (e *etfEncoding) MessageType() int {
+	return websocket.BinaryMessage
+}
+
+func (e *etfEncoding) Marshal(v any) ([]byte, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	return jsonToETF(data)
+}
+
+func (e *etfEncoding) Unmarshal(data []byte, v any) error {
+	data, err := etfToJSON(data)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
+
+// etfToJSON converts the given External Term Format data into JSON.
+func etfToJSON(data []byte) ([]byte, error) {
+	if len(data) == 0 || data[0] != etfVersion {
+		return nil, errETFInvalidVersion
+	}
+	d := &etfDecoder{data: data[1:]}
+	out, err := d.decode(make([]byte, 0, len(data)*2))
+	if err != nil {
+		return nil, err
+	}
+	if len(d.data) != 0 {
+		return nil, fmt.Errorf("%d trailing bytes after etf term", len(d.data))
+	}
+	return out, nil
+}
+
+type etfDecoder struct {
+	data []byte
+}
+
+func (d *etfDecoder) read(n int) ([]byte, error) {
+	if n < 0 || len(d.data) < n {
+		return nil, errETFUnexpectedEnd
+	}
+	b := d.data[:n]
+	d.data = d.data[n:]
+	return b, nil
+}
+
+func (d *etfDecoder) readUint8() (int, error) {
+	b, err := d.read(1)
+	if err != nil {
+		return 0, err
+	}
+	return int(b[0]), nil
+}
+
+func (d *etfDecoder) readUint16() (int, error) {
+	b, err := d.read(2)
+	if err != nil {
+		return 0, err
+	}
+	return int(binary.BigEndian.Uint16(b)), nil
+}
+
+func (d *etfDecoder) readUint32() (int, error) {
+	b, err := d.read(4)
+	if err != nil {
+		return 0, err
+	}
+	return int(binary.BigEndian.Uint32(b)), nil
+}
+
+// decode appends the next term as JSON.
+func (d *etfDecoder) decode(out []byte) ([]byte, error) {
+	tag, err := d.readUint8()
+	if err != nil {
+		return nil, err
+	}
+
+	switch tag {
+	case etfSmallInteger:
+		i, err := d.readUint8()
+		if err != nil {
+			return nil, err
+		}
+		return strconv.AppendInt(out, int64(i), 10), nil
+
+	case etfInteger:
+		b, err := d.read(4)
+		if err != nil {
+			return nil, err
+		}
+		return strconv.AppendInt(out, int64(int32(binary.BigEndian.Uint32(b))), 10), nil
+
+	case etfNewFloat:
+		b, err := d.read(8)
+		if err != nil {
+			return nil, err
+		}
+		return strconv.AppendFloat(out, math.Float64frombits(binary.BigEndian.Uint64(b)), 'g', -1, 64), nil
+
+	case etfFloat:
+		b, err := d.read(31)
+		if err != nil {
+			return nil, err
+		}
+		f, err := strconv.ParseFloat(string(bytes.TrimRight(b, "\x00")), 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid etf float: %w", err)
+		}
+		return strconv.AppendFloat(out, f, 'g', -1, 64), nil
+
+	case etfSmallBig, etfLargeBig:
+		var n int
+		if tag == etfSmallBig {
+			n, err = d.readUint8()
+		} else {
+			n, err = d.readUint32()
+		}
+		if err != nil {
+			return nil, err
+		}
+		return d.decodeBig(out, n)
+
+	case etfAtom, etfAtomUTF8, etfSmallAtom, etfSmallAtomUTF8:
+		var n int
+		if tag == etfSmallAtom || tag == etfSmallAtomUTF8 {
+			n, err = d.readUint8()
+		} else {
+			n, err = d.readUint16()
+		}
+		if err != nil {
+			return nil, err
+		}
+		atom, err := d.read(n)
+		if err != nil {
+			return nil, err
+		}
+		switch string(atom) {
+		case "nil", "null":
+			return append(out, "null"...), nil
+		case "true":
+			return append(out, "true"...), nil
+		case "false":
+			return append(out, "false"...), nil
+		}
+		return appendJSONString(out, atom), nil
+
+	case etfBinary:
+		n, err := d.readUint32()
+		if err != nil {
+			return nil, err
+		}
+		b, err := d.read(n)
+		if err != nil {
+			return nil, err
+		}
+		return appendJSONString(out, b), nil
+
+	case etfNil:
+		return append(out, "[]"...), nil
+
+	case etfString:
+		// STRING_EXT is how Erlang encodes lists of small integers
+		n, err := d.readUint16()
+		if err != nil {
+			return nil, err
+		}
+		b, err := d.read(n)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, '[')
+		for i, c := range b {
+			if i > 0 {
+				out = append(out, ',')
+			}
+			out = strconv.AppendInt(out, int64(c), 10)
+		}
+		return append(out, ']'), nil
+
+	case etfList:
+		n, err := d.readUint32()
+		if err != nil {
+			return nil, err
+		}
+		if out, err = d.decodeArray(out, n); err != nil {
+			return nil, err
+		}
+		// proper lists end with a NIL_EXT tail
+		tail, err := d.readUint8()
+		if err != nil {
+			return nil, err
+		}
+		if tail != etfNil {
+			return nil, fmt.Errorf("improper etf lists are not supported")
+		}
+		return out, nil
+
+	case etfSmallTuple:
+		n, err := d.readUint8()
+		if err != nil {
+			return nil, err
+		}
+		return d.decodeArray(out, n)
+
+	case etfLargeTuple:
+		n, err := d.readUint32()
+		if err != nil {
+			return nil, err
+		}
+		return d.decodeArray(out, n)
+
+	case etfMap:
+		n, err := d.readUint32()
+		if err != nil {
+			return nil, err
+		}
+		return d.decodeMap(out, n)
+
+	case etfCompressed:
+		size, err := d.readUint32()
+		if err != nil {
+			return nil, err
+		}
+		if size > etfMaxUncompressedSize {
+			return nil, errETFTooLarge
+		}
+		r, err := zlib.NewReader(bytes.NewReader(d.data))
+		if err != nil {
+			return nil, fmt.Errorf("failed to decompress etf term: %w", err)
+		}
+		defer r.Close()
+		uncompressed := make([]byte, size)
+		if _, err = io.ReadFull(r, uncompressed); err != nil {
+			return nil, fmt.Errorf("failed to decompress etf term: %w", err)
+		}
+		// a compressed term always spans the remaining data
+		d.data = nil
+		inner := &etfDecoder{data: uncompressed}
+		return inner.decode(out)
+
+	default:
+		return nil, fmt.Errorf("unsupported etf tag: %d", tag)
+	}
+}
+
+// etfMaxExactInt is the largest integer a float64 can represent exactly.
+// Discord sends snowflakes as bigs which are always larger, so larger integers are appended as quoted strings.
+const etfMaxExactInt = 1 << 53
+
+// decodeBig appends a big as JSON number or, if it does not fit into the precision of a float64, as quoted string.
+// Small bigs like timestamps in milliseconds stay numbers.
+func (d *etfDecoder) decodeBig(out []byte, n int) ([]byte, error) {
+	sign, err := d.readUint8()
+	if err != nil {
+		return nil, err
+	}
+	digits, err := d.read(n)
+	if err != nil {
+		return nil, err
+	}
+
+	// most bigs are snowflakes which fit into an uint64
+	if n <= 8 {
+		var u uint64
+		for i := n - 1; i >= 0; i-- {
+			u = u<<8 | uint64(digits[i])
+		}
+		quote := u > etfMaxExactInt
+		if quote {
+			out = append(out, '"')
+		}
+		if sign != 0 {
+			out = append(out, '-')
+		}
+		out = strconv.AppendUint(out, u, 10)
+		if quote {
+			out = append(out, '"')
+		}
+		return out, nil
+	}
+
+	// digits are little endian while big.Int expects big endian
+	be := make([]byte, n)
+	for i, b := range digits {
+		be[n-1-i] = b
+	}
+	i := new(big.Int).SetBytes(be)
+	if sign != 0 {
+		i.Neg(i)
+	}
+	out = append(out, '"')
+	out = i.Append(out, 10)
+	return append(out, '"'), nil
+}
+
+func (d *etfDecoder) decodeArray(out []byte, n int) ([]byte, error) {
+	var err error
+	out = append(out, '[')
+	for i := 0; i < n; i++ {
+		if i > 0 {
+			out = append(out, ',')
+		}
+		if out, err = d.decode(out); err != nil {
+			return nil, err
+		}
+	}
+	return append(out, ']'), nil
+}
+
+func (d *etfDecoder) decodeMap(out []byte, n int) ([]byte, error) {
+	var err error
+	out = append(out, '{')
+	for i := 0; i < n; i++ {
+		if i > 0 {
+			out = append(out, ',')
+		}
+		start := len(out)
+		if out, err = d.decode(out); err != nil {
+			return nil, err
+		}
+		// JSON object keys have to be strings
+		if key := out[start:]; len(key) == 0 || key[0] != '"' {
+			quoted := appendJSONString(nil, key)
+			out = append(out[:start], quoted...)
+		}
+		out = append(out, ':')
+		if out, err = d.decode(out); err != nil {
+			return nil, err
+		}
+	}
+	return append(out, '}'), nil
+}
+
+const hexDigits = "0123456789abcdef"
+
+// appendJSONString appends the given bytes as a quoted JSON string.
+func appendJSONString(out []byte, s []byte) []byte {
+	out = append(out, '"')
+	for len(s) > 0 {
+		c := s[0]
+		if c < utf8.RuneSelf {
+			switch {
+			case c == '"' || c == '\\':
+				out = append(out, '\\', c)
+			case c == '\n':
+				out = append(out, '\\', 'n')
+			case c == '\r':
+				out = append(out, '\\', 'r')
+			case c == '\t':
+				out = append(out, '\\', 't')
+			case c < 0x20:
+				out = append(out, '\\', 'u', '0', '0', hexDigits[c>>4], hexDigits[c&0xf])
+			default:
+				out = append(out, c)
+			}
+			s = s[1:]
+			continue
+		}
+		r, size := utf8.DecodeRune(s)
+		if r == utf8.RuneError && size == 1 {
+			out = append(out, `�`...)
+		} else {
+			out = append(out, s[:size]...)
+		}
+		s = s[size:]
+	}
+	return append(out, '"')
+}
+
+// jsonToETF converts the given JSON into External Term Format data.
+func jsonToETF(data []byte) ([]byte, error) {
+	decoder := stdjson.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	var v any
+	if err := decoder.Decode(&v); err != nil {
+		return nil, err
+	}
+	return appendETF([]byte{etfVersion}, v)
+}
+
+func appendETF(out []byte, v any) ([]byte, error) {
+	var err error
+	switch v := v.(type) {
+	case nil:
+		return appendETFAtom(out, "nil"), nil
+
+	case bool:
+		if v {
+			return appendETFAtom(out, "true"), nil
+		}
+		return appendETFAtom(out, "false"), nil
+
+	case string:
+		out = append(out, etfBinary)
+		out = binary.BigEndian.AppendUint32(out, uint32(len(v)))
+		return append(out, v...), nil
+
+	case stdjson.Number:
+		if i, err := v.Int64(); err == nil {
+			return appendETFInt(out, i), nil
+		}
+		if u, err := strconv.ParseUint(string(v), 10, 64); err == nil {
+			out = append(out, etfSmallBig, 8, 0)
+			return binary.LittleEndian.AppendUint64(out, u), nil
+		}
+		f, err := v.Float64()
+		if err != nil {
+			return nil, fmt.Errorf("invalid json number %s: %w", v, err)
+		}
+		out = append(out, etfNewFloat)
+		return binary.BigEndian.AppendUint64(out, math.Float64bits(f)), nil
+
+	case []any:
+		if len(v) == 0 {
+			return append(out, etfNil), nil
+		}
+		out = append(out, etfList)
+		out = binary.BigEndian.AppendUint32(out, uint32(len(v)))
+		for _, e := range v {
+			if out, err = appendETF(out, e); err != nil {
+				return nil, err
+			}
+		}
+		return append(out, etfNil), nil
+
+	case map[string]any:
+		out = append(out, etfMap)
+		out = binary.BigEndian.AppendUint32(out, uint32(len(v)))
+		for key, value := range v {
+			if out, err = appendETF(out, key); err != nil {
+				return nil, err
+			}
+			if out, err = appendETF(out, value); err != nil {
+				return nil, err
+			}
+		}
+		return out, nil
+
+	default:
+		return nil, fmt.Errorf("unsupported etf type: %T", v)
+	}
+}
+
+func appendETFAtom(out []byte, atom string) []byte {
+	out = append(out, etfSmallAtomUTF8, byte(len(atom)))
+	return append(out, atom...)
+}
+
+func appendETFInt(out []byte, i int64) []byte {
+	switch {
+	case i >= 0 && i <= math.MaxUint8:
+		return append(out, etfSmallInteger, byte(i))
+
+	case i >= math.MinInt32 && i <= math.MaxInt32:
+		out = append(out, etfInteger)
+		return binary.BigEndian.AppendUint32(out, uint32(int32(i)))
+
+	default:
+		var sign byte
+		u := uint64(i)
+		if i < 0 {
+			sign = 1
+			u = uint64(-i)
+		}
+		out = append(out, etfSmallBig, 8, sign)
+		return binary.LittleEndian.AppendUint64(out, u)
+	}
+}
@@ -0,0 +1,178 @@
+package gateway
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/disgoorg/snowflake/v2"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/disgoorg/disgo/discord"
+)
+
+func TestETFEncoding_Unmarshal(t *testing.T) {
+	encoding := NewETFEncoding()
+
+	// snowflakes are sent as integers, guild_id is nil
+	data, err := jsonToETF([]byte(`{"op":0,"s":42,"t":"MESSAGE_DELETE","d":{"id":1234567890123456789,"channel_id":987654321987654321,"guild_id":null}}`))
+	assert.NoError(t, err)
+
+	var message Message
+	assert.NoError(t, encoding.Unmarshal(data, &message))
+	assert.Equal(t, OpcodeDispatch, message.Op)
+	assert.Equal(t, 42, message.S)
+	assert.Equal(t, EventTypeMessageDelete, message.T)
+	assert.Equal(t, EventMessageDelete{
+		ID:        snowflake.ID(1234567890123456789),
+		ChannelID: snowflake.ID(987654321987654321),
+	}, message.D)
+	assert.False(t, snowflake.AllowUnquoted)
+}
+
+func TestETFEncoding_UnmarshalSnowflakes(t *testing.T) {
+	encoding := NewETFEncoding()
+
+	// snowflakes outside of id positions are quoted as well
+	data, err := jsonToETF([]byte(`{"op":0,"s":1,"t":"GUILD_MEMBERS_CHUNK","d":{"guild_id":1234567890123456789,"members":[{"user":{"id":1234567890123456790},"roles":[987654321987654321,987654321987654322],"joined_at":"2024-01-01T00:00:00Z"}],"chunk_index":0,"chunk_count":1,"not_found":[1234567890123456791,1234567890123456792]}}`))
+	assert.NoError(t, err)
+
+	var message Message
+	assert.NoError(t, encoding.Unmarshal(data, &message))
+	chunk, ok := message.D.(EventGuildMembersChunk)
+	if !assert.True(t, ok) {
+		return
+	}
+	assert.Equal(t, []snowflake.ID{1234567890123456791, 1234567890123456792}, chunk.NotFound)
+	if assert.Len(t, chunk.Members, 1) {
+		assert.Equal(t, snowflake.ID(1234567890123456790), chunk.Members[0].User.ID)
+		assert.Equal(t, []snowflake.ID{987654321987654321, 987654321987654322}, chunk.Members[0].RoleIDs)
+	}
+
+	var command discord.SlashCommand
+	data, err = jsonToETF([]byte(`{"id":1234567890123456789,"type":1,"application_id":1234567890123456790,"name":"test","description":"test","version":1234567890123456791}`))
+	assert.NoError(t, err)
+	assert.NoError(t, encoding.Unmarshal(data, &command))
+	assert.Equal(t, snowflake.ID(1234567890123456791), command.Version())
+}
+
+func TestETFEncoding_Marshal(t *testing.T) {
+	encoding := NewETFEncoding()
+
+	data, err := encoding.Marshal(Message{
+		Op: OpcodeHeartbeat,
+		D:  MessageDataHeartbeat(300),
+	})
+	assert.NoError(t, err)
+
+	rawJSON, err := etfToJSON(data)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"op":1,"d":300}`, string(rawJSON))
+}
+
+func TestETFToJSON(t *testing.T) {
+	tt := []struct {
+		Name string
+		Data []byte
+		JSON string
+	}{
+		{
+			Name: "atoms",
+			Data: []byte{etfVersion, etfSmallTuple, 3, etfSmallAtomUTF8, 4, 't', 'r', 'u', 'e', etfAtom, 0, 3, 'n', 'i', 'l', etfSmallAtom, 3, 'f', 'o', 'o'},
+			JSON: `[true,null,"foo"]`,
+		},
+		{
+			Name: "string ext",
+			Data: []byte{etfVersion, etfString, 0, 2, 0, 1},
+			JSON: `[0,1]`,
+		},
+		{
+			Name: "negative integer",
+			Data: []byte{etfVersion, etfInteger, 0xff, 0xff, 0xff, 0xfe},
+			JSON: `-2`,
+		},
+		{
+			Name: "binary with escapes",
+			Data: []byte{etfVersion, etfBinary, 0, 0, 0, 3, '"', '\n', 'a'},
+			JSON: `"\"\na"`,
+		},
+		{
+			Name: "map with atom keys",
+			Data: []byte{etfVersion, etfMap, 0, 0, 0, 1, etfSmallAtomUTF8, 1, 'a', etfNil},
+			JSON: `{"a":[]}`,
+		},
+		{
+			Name: "snowflakes",
+			Data: mustJSONToETF(`{"id":1234567890123456789,"role_ids":[[987654321987654321]],"not_found":[-1234567890123456789],"answer_id":1,"start":1700000000000}`),
+			JSON: `{"id":"1234567890123456789","role_ids":[["987654321987654321"]],"not_found":["-1234567890123456789"],"answer_id":1,"start":1700000000000}`,
+		},
+		{
+			Name: "large big",
+			Data: []byte{etfVersion, etfSmallBig, 9, 0, 0, 0, 0, 0, 0, 0, 0, 0, 1},
+			JSON: `"18446744073709551616"`,
+		},
+		{
+			Name: "map with integer keys",
+			Data: []byte{etfVersion, etfMap, 0, 0, 0, 1, etfSmallInteger, 1, etfSmallInteger, 2},
+			JSON: `{"1":2}`,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.Name, func(t *testing.T) {
+			data, err := etfToJSON(tc.Data)
+			assert.NoError(t, err)
+			assert.JSONEq(t, tc.JSON, string(data))
+		})
+	}
+}
+
+func TestETFToJSON_Invalid(t *testing.T) {
+	_, err := etfToJSON([]byte{etfVersion, etfBinary, 0, 0, 0, 10, 'a'})
+	assert.ErrorIs(t, err, errETFUnexpectedEnd)
+
+	_, err = etfToJSON([]byte{'{', '}'})
+	assert.ErrorIs(t, err, errETFInvalidVersion)
+
+	_, err = etfToJSON([]byte{etfVersion, etfCompressed, 0xff, 0xff, 0xff, 0xff})
+	assert.ErrorIs(t, err, errETFTooLarge)
+}
+
+func BenchmarkEncoding_Unmarshal(b *testing.B) {
+	rawJSON := []byte(`{"op":0,"s":1,"t":"GUILD_MEMBERS_CHUNK","d":{"guild_id":1234567890123456789,"members":[` +
+		strings.Repeat(`{"user":{"id":1234567890123456790,"username":"test","global_name":"Test","avatar":null},"roles":[987654321987654321,987654321987654322],"joined_at":"2024-01-01T00:00:00Z","deaf":false,"mute":false},`, 99) +
+		`{"user":{"id":1234567890123456790,"username":"test","global_name":"Test","avatar":null},"roles":[987654321987654321,987654321987654322],"joined_at":"2024-01-01T00:00:00Z","deaf":false,"mute":false}` +
+		`],"chunk_index":0,"chunk_count":1}}`)
+	etfData := mustJSONToETF(string(rawJSON))
+	jsonData, err := etfToJSON(etfData)
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	for _, bc := range []struct {
+		Name     string
+		Encoding Encoding
+		Data     []byte
+	}{
+		{Name: "json", Encoding: NewJSONEncoding(), Data: jsonData},
+		{Name: "etf", Encoding: NewETFEncoding(), Data: etfData},
+	} {
+		b.Run(bc.Name, func(b *testing.B) {
+			b.SetBytes(int64(len(bc.Data)))
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				var message Message
+				if err := bc.Encoding.Unmarshal(bc.Data, &message); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func mustJSONToETF(s string) []byte {
+	data, err := jsonToETF([]byte(s))
+	if err != nil {
+		panic(err)
+	}
+	return data
+}
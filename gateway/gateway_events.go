@@ -730,6 +730,17 @@ type EventHeartbeatAck struct {
 func (EventHeartbeatAck) messageData() {}
 func (EventHeartbeatAck) eventData()   {}
 
+// EventSessionStartLimit is not sent by Discord, but by the sharding.ShardManager to report how many session starts (identifies) are left before they reset.
+// It's dispatched with the lowest shard ID of the sharding.ShardManager.
+type EventSessionStartLimit struct {
+	discord.SessionStartLimit
+	// ShardsToOpen is the number of shards the sharding.ShardManager is about to open. If it's more than Remaining, no shards are opened.
+	ShardsToOpen int
+}
+
+func (EventSessionStartLimit) messageData() {}
+func (EventSessionStartLimit) eventData()   {}
+
 type EventEntitlementCreate struct {
 	discord.Entitlement
 }
@@ -0,0 +1,29 @@
+package gateway
+
+import (
+	"time"
+)
+
+// DispatchEvent is passed to Config.OnDispatch after a dispatch event was handled by the EventHandlerFunc.
+type DispatchEvent struct {
+	// ShardID is the ID of the shard which received the event.
+	ShardID int
+	// EventType is the EventType of the event.
+	EventType EventType
+	// Sequence is the sequence number of the event.
+	Sequence int
+	// ReceivedAt is the time the event was received at.
+	ReceivedAt time.Time
+	// Duration is the time it took from receiving the event until the EventHandlerFunc returned, including decoding the payload.
+	Duration time.Duration
+}
+
+// ReconnectEvent is passed to Config.OnReconnect after each attempt to reconnect the Gateway.
+type ReconnectEvent struct {
+	// ShardID is the ID of the shard which reconnected.
+	ShardID int
+	// Try is the number of the attempt, starting at 1.
+	Try int
+	// Err is the error of the attempt. It is nil if the Gateway reconnected.
+	Err error
+}
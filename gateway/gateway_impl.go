@@ -13,13 +13,20 @@ import (
 	"syscall"
 	"time"
 
-	"github.com/disgoorg/json"
 	"github.com/gorilla/websocket"
 
 	"github.com/disgoorg/disgo/discord"
 )
 
-var _ Gateway = (*gatewayImpl)(nil)
+var (
+	_ Gateway   = (*gatewayImpl)(nil)
+	_ Handoffer = (*gatewayImpl)(nil)
+)
+
+var (
+	errIncompletePayload = errors.New("incomplete gateway payload")
+	errInflateFailed     = errors.New("failed to inflate zlib-stream")
+)
 
 // New creates a new Gateway instance with the provided token, eventHandlerFunc, closeHandlerFunc and ConfigOpt(s).
 func New(token string, eventHandlerFunc EventHandlerFunc, closeHandlerFunc CloseHandlerFunc, opts ...ConfigOpt) Gateway {
@@ -44,12 +51,18 @@ type gatewayImpl struct {
 
 	conn            *websocket.Conn
 	connMu          sync.Mutex
+	inflater        *zlibStreamInflater
 	heartbeatCancel context.CancelFunc
 	status          Status
 
 	heartbeatInterval     time.Duration
 	lastHeartbeatSent     time.Time
 	lastHeartbeatReceived time.Time
+
+	// handedOff is set once the session was handed off to another Gateway.
+	// handedOffMu also guards writes to the session of the config, so Handoff returns a consistent Session.
+	handedOff   bool
+	handedOffMu sync.Mutex
 }
 
 func (g *gatewayImpl) ShardID() int {
@@ -73,7 +86,83 @@ func (g *gatewayImpl) Intents() Intents {
 }
 
 func (g *gatewayImpl) Open(ctx context.Context) error {
-	return g.reconnectTry(ctx, 0)
+	g.handedOffMu.Lock()
+	if g.handedOff {
+		// the session belongs to another Gateway now
+		g.handedOff = false
+		g.config.SessionID = nil
+		g.config.ResumeURL = nil
+		g.config.LastSequenceReceived = nil
+	}
+	g.handedOffMu.Unlock()
+
+	g.loadSession(ctx)
+	return g.reconnectTry(ctx, 0, false)
+}
+
+// loadSession loads the session from the SessionStore if we don't have one yet.
+// The stored session is removed as it can only be resumed once.
+func (g *gatewayImpl) loadSession(ctx context.Context) {
+	if g.config.SessionStore == nil || g.config.SessionID != nil {
+		return
+	}
+
+	session, ok, err := g.config.SessionStore.Get(ctx, g.config.ShardID)
+	if err != nil {
+		g.config.Logger.Error("failed to load session", slog.Any("err", err))
+		return
+	}
+	if !ok {
+		return
+	}
+	if err = g.config.SessionStore.Delete(ctx, g.config.ShardID); err != nil {
+		g.config.Logger.Error("failed to delete session", slog.Any("err", err))
+	}
+
+	if session.ShardCount != g.config.ShardCount {
+		g.config.Logger.Debug("ignoring stored session with different shard count", slog.Int("session_shard_count", session.ShardCount))
+		return
+	}
+
+	g.config.Logger.Debug("loaded session", slog.String("session_id", session.ID), slog.Int("sequence", session.Sequence))
+	g.handedOffMu.Lock()
+	defer g.handedOffMu.Unlock()
+	g.config.SessionID = &session.ID
+	g.config.LastSequenceReceived = &session.Sequence
+	if session.ResumeURL != "" {
+		g.config.ResumeURL = &session.ResumeURL
+	}
+}
+
+// session returns the current Session if there is one.
+func (g *gatewayImpl) session() (Session, bool) {
+	if g.config.SessionID == nil || g.config.LastSequenceReceived == nil {
+		return Session{}, false
+	}
+
+	session := Session{
+		ID:         *g.config.SessionID,
+		Sequence:   *g.config.LastSequenceReceived,
+		ShardCount: g.config.ShardCount,
+	}
+	if g.config.ResumeURL != nil {
+		session.ResumeURL = *g.config.ResumeURL
+	}
+	return session, true
+}
+
+// saveSession saves the current session to the SessionStore.
+func (g *gatewayImpl) saveSession(ctx context.Context) {
+	session, ok := g.session()
+	if !ok {
+		return
+	}
+
+	if err := g.config.SessionStore.Put(ctx, g.config.ShardID, session); err != nil {
+		g.config.Logger.Error("failed to save session", slog.Any("err", err))
+		return
+	}
+	g.config.Logger.Debug("saved session", slog.String("session_id", session.ID), slog.Int("sequence", session.Sequence))
 }
 
 func (g *gatewayImpl) open(ctx context.Context) error {
@@ -90,7 +179,10 @@ func (g *gatewayImpl) open(ctx context.Context) error {
 	if g.config.ResumeURL != nil && g.config.EnableResumeURL {
 		wsURL = *g.config.ResumeURL
 	}
-	gatewayURL := fmt.Sprintf("%s?v=%d&encoding=json", wsURL, Version)
+	gatewayURL := fmt.Sprintf("%s?v=%d&encoding=%s", wsURL, Version, g.config.Encoding.Name())
+	if g.config.Compression != CompressionNone {
+		gatewayURL += "&compress=" + string(g.config.Compression)
+	}
 	g.lastHeartbeatSent = time.Now().UTC()
 	conn, rs, err := g.config.Dialer.DialContext(ctx, gatewayURL, nil)
 	if err != nil {
@@ -116,21 +208,46 @@ func (g *gatewayImpl) open(ctx context.Context) error {
 
 	g.conn = conn
 
+	// every connection needs a fresh inflate context
+	var inflater *zlibStreamInflater
+	if g.config.Compression == CompressionZlibStream {
+		inflater = newZlibStreamInflater()
+	}
+	g.inflater = inflater
+
 	// reset rate limiter when connecting
 	g.config.RateLimiter.Reset()
 
 	g.status = StatusWaitingForHello
 
-	go g.listen(conn)
+	go g.listen(conn, inflater)
 
 	return nil
 }
 
 func (g *gatewayImpl) Close(ctx context.Context) {
-	g.CloseWithCode(ctx, websocket.CloseNormalClosure, "Shutting down")
+	if g.isHandedOff() {
+		// the session is resumed by another Gateway, so we neither invalidate nor save it
+		g.CloseWithCode(ctx, websocket.CloseServiceRestart, "Handed off")
+		return
+	}
+
+	if g.config.SessionStore == nil {
+		g.CloseWithCode(ctx, websocket.CloseNormalClosure, "Shutting down")
+		return
+	}
+
+	// closing with a normal closure would invalidate the session
+	g.CloseWithCode(ctx, websocket.CloseServiceRestart, "Shutting down")
+	g.saveSession(ctx)
 }
 
 func (g *gatewayImpl) CloseWithCode(ctx context.Context, code int, message string) {
+	if (code == websocket.CloseNormalClosure || code == websocket.CloseGoingAway) && g.isHandedOff() {
+		// closing gracefully would invalidate the session another Gateway resumes
+		code = websocket.CloseServiceRestart
+	}
+
 	if g.heartbeatCancel != nil {
 		g.config.Logger.Debug("closing heartbeat goroutines...")
 		g.heartbeatCancel()
@@ -147,11 +264,14 @@ func (g *gatewayImpl) CloseWithCode(ctx context.Context, code int, message strin
 		_ = g.conn.Close()
 		g.conn = nil
 
+		if g.inflater != nil {
+			g.inflater.close()
+			g.inflater = nil
+		}
+
 		// clear resume data as we closed gracefully
 		if code == websocket.CloseNormalClosure || code == websocket.CloseGoingAway {
-			g.config.SessionID = nil
-			g.config.ResumeURL = nil
-			g.config.LastSequenceReceived = nil
+			g.clearSession()
 		}
 	}
 }
@@ -163,14 +283,14 @@ func (g *gatewayImpl) Status() Status {
 }
 
 func (g *gatewayImpl) Send(ctx context.Context, op Opcode, d MessageData) error {
-	data, err := json.Marshal(Message{
+	data, err := g.config.Encoding.Marshal(Message{
 		Op: op,
 		D:  d,
 	})
 	if err != nil {
 		return err
 	}
-	return g.send(ctx, websocket.TextMessage, data)
+	return g.send(ctx, g.config.Encoding.MessageType(), data)
 }
 
 func (g *gatewayImpl) send(ctx context.Context, messageType int, data []byte) error {
@@ -199,7 +319,52 @@ func (g *gatewayImpl) Presence() *MessageDataPresenceUpdate {
 	return g.config.Presence
 }
 
-func (g *gatewayImpl) reconnectTry(ctx context.Context, try int) error {
+func (g *gatewayImpl) Handoff() (Session, error) {
+	g.handedOffMu.Lock()
+	defer g.handedOffMu.Unlock()
+
+	session, ok := g.session()
+	if !ok {
+		return Session{}, discord.ErrGatewayNoSession
+	}
+	g.handedOff = true
+	g.config.Logger.Debug("handed off session", slog.String("session_id", session.ID), slog.Int("sequence", session.Sequence))
+	return session, nil
+}
+
+func (g *gatewayImpl) CancelHandoff(ctx context.Context) error {
+	g.handedOffMu.Lock()
+	if !g.handedOff {
+		g.handedOffMu.Unlock()
+		return nil
+	}
+	g.handedOff = false
+	g.handedOffMu.Unlock()
+
+	// events received since the handoff were dropped without updating the sequence, resuming makes Discord replay them
+	g.config.Logger.Debug("cancelling session handoff")
+	g.CloseWithCode(ctx, websocket.CloseServiceRestart, "Handoff cancelled")
+	return g.reconnectTry(ctx, 0, true)
+}
+
+// clearSession removes the session, so the next connection identifies again.
+func (g *gatewayImpl) clearSession() {
+	g.handedOffMu.Lock()
+	defer g.handedOffMu.Unlock()
+	g.config.SessionID = nil
+	g.config.ResumeURL = nil
+	g.config.LastSequenceReceived = nil
+}
+
+func (g *gatewayImpl) isHandedOff() bool {
+	g.handedOffMu.Lock()
+	defer g.handedOffMu.Unlock()
+	return g.handedOff
+}
+
+// reconnectTry opens the Gateway & retries with an increasing delay until it succeeds or the context is done.
+// reconnecting is whether the Gateway was connected before, attempts are reported to Config.OnReconnect then.
+func (g *gatewayImpl) reconnectTry(ctx context.Context, try int, reconnecting bool) error {
 	delay := time.Duration(try) * 2 * time.Second
 	if delay > 30*time.Second {
 		delay = 30 * time.Second
@@ -214,19 +379,31 @@ func (g *gatewayImpl) reconnectTry(ctx context.Context, try int) error {
 	case <-timer.C:
 	}
 
-	if err := g.open(ctx); err != nil {
+	err := g.open(ctx)
+	if g.config.OnReconnect != nil && (reconnecting || try > 0) && !errors.Is(err, discord.ErrGatewayAlreadyConnected) {
+		g.config.OnReconnect(ReconnectEvent{
+			ShardID: g.config.ShardID,
+			Try:     try + 1,
+			Err:     err,
+		})
+	}
+	if err != nil {
 		if errors.Is(err, discord.ErrGatewayAlreadyConnected) {
 			return err
 		}
 		g.config.Logger.Error("failed to reconnect gateway", slog.Any("err", err))
 		g.status = StatusDisconnected
-		return g.reconnectTry(ctx, try+1)
+		return g.reconnectTry(ctx, try+1, reconnecting)
 	}
 	return nil
 }
 
 func (g *gatewayImpl) reconnect() {
-	err := g.reconnectTry(context.Background(), 0)
+	if g.isHandedOff() {
+		g.config.Logger.Debug("not reconnecting as the session was handed off")
+		return
+	}
+	err := g.reconnectTry(context.Background(), 0, true)
 	if err != nil {
 		g.config.Logger.Error("failed to reopen gateway", slog.Any("err", err))
 	}
@@ -281,7 +458,8 @@ func (g *gatewayImpl) identify() {
 			Browser: g.config.Browser,
 			Device:  g.config.Device,
 		},
-		Compress:       g.config.Compress,
+		// payload compression can't be used together with transport compression
+		Compress:       g.config.Compress && g.config.Compression == CompressionNone,
 		LargeThreshold: g.config.LargeThreshold,
 		Intents:        g.config.Intents,
 		Presence:       g.config.Presence,
@@ -312,11 +490,12 @@ func (g *gatewayImpl) resume() {
 	}
 }
 
-func (g *gatewayImpl) listen(conn *websocket.Conn) {
+func (g *gatewayImpl) listen(conn *websocket.Conn, inflater *zlibStreamInflater) {
 	defer g.config.Logger.Debug("exiting listen goroutine")
 loop:
 	for {
 		mt, r, err := conn.NextReader()
+		receivedAt := time.Now()
 		if err != nil {
 			g.connMu.Lock()
 			sameConnection := g.conn == conn
@@ -334,9 +513,7 @@ loop:
 				reconnect = closeCode.Reconnect
 
 				if closeCode == CloseEventCodeInvalidSeq {
-					g.config.LastSequenceReceived = nil
-					g.config.SessionID = nil
-					g.config.ResumeURL = nil
+					g.clearSession()
 				}
 				msg := "gateway close received"
 				args := []any{
@@ -360,7 +537,7 @@ loop:
 			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 			g.CloseWithCode(ctx, websocket.CloseServiceRestart, "reconnecting")
 			cancel()
-			if g.config.AutoReconnect && reconnect {
+			if g.config.AutoReconnect && reconnect && !g.isHandedOff() {
 				go g.reconnect()
 			} else if g.closeHandlerFunc != nil {
 				go g.closeHandlerFunc(g, err)
@@ -369,7 +546,28 @@ loop:
 			break loop
 		}
 
-		message, err := g.parseMessage(mt, r)
+		message, err := g.parseMessage(mt, r, inflater)
+		if errors.Is(err, errIncompletePayload) {
+			continue
+		}
+		if errors.Is(err, errInflateFailed) {
+			g.connMu.Lock()
+			sameConnection := g.conn == conn
+			g.connMu.Unlock()
+
+			// the connection was closed by the user, which also closes the inflate context
+			if !sameConnection {
+				return
+			}
+
+			// the shared inflate context is broken, the only way to recover is a new connection
+			g.config.Logger.Error("error while inflating gateway message", slog.Any("err", err))
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			g.CloseWithCode(ctx, websocket.CloseServiceRestart, "inflate failed")
+			cancel()
+			go g.reconnect()
+			break loop
+		}
 		if err != nil {
 			g.config.Logger.Error("error while parsing gateway message", slog.Any("err", err))
 			continue
@@ -388,8 +586,15 @@ loop:
 			}
 
 		case OpcodeDispatch:
+			g.handedOffMu.Lock()
+			if g.handedOff {
+				// the Gateway resuming our session receives this event
+				g.handedOffMu.Unlock()
+				continue
+			}
 			// set last sequence received
 			g.config.LastSequenceReceived = &message.S
+			g.handedOffMu.Unlock()
 
 			eventData, ok := message.D.(EventData)
 			if !ok && message.D != nil {
@@ -399,11 +604,17 @@ loop:
 
 			// get session id here
 			if readyEvent, ok := eventData.(EventReady); ok {
+				g.handedOffMu.Lock()
 				g.config.SessionID = &readyEvent.SessionID
 				g.config.ResumeURL = &readyEvent.ResumeGatewayURL
+				g.handedOffMu.Unlock()
 				g.status = StatusReady
 				g.config.Logger.Debug("ready message received")
 			}
+			if message.T == EventTypeResumed {
+				g.status = StatusReady
+				g.config.Logger.Debug("resumed message received")
+			}
 
 			if unknownEvent, ok := eventData.(EventUnknown); ok {
 				g.config.Logger.Debug("unknown event received", slog.String("event", string(message.T)), slog.String("data", string(unknownEvent)))
@@ -418,6 +629,15 @@ loop:
 				})
 			}
 			g.eventHandlerFunc(message.T, message.S, g.config.ShardID, eventData)
+			if g.config.OnDispatch != nil {
+				g.config.OnDispatch(DispatchEvent{
+					ShardID:    g.config.ShardID,
+					EventType:  message.T,
+					Sequence:   message.S,
+					ReceivedAt: receivedAt,
+					Duration:   time.Since(receivedAt),
+				})
+			}
 
 		case OpcodeHeartbeat:
 			g.sendHeartbeat()
@@ -437,9 +657,7 @@ loop:
 				code = websocket.CloseServiceRestart
 			} else {
 				// clear resume info
-				g.config.SessionID = nil
-				g.config.LastSequenceReceived = nil
-				g.config.ResumeURL = nil
+				g.clearSession()
 			}
 
 			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
@@ -463,29 +681,43 @@ loop:
 	}
 }
 
-func (g *gatewayImpl) parseMessage(mt int, r io.Reader) (Message, error) {
-	if mt == websocket.BinaryMessage {
+func (g *gatewayImpl) parseMessage(mt int, r io.Reader, inflater *zlibStreamInflater) (Message, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return Message{}, fmt.Errorf("failed to read message: %w", err)
+	}
+
+	if inflater != nil {
+		inflated, ok, err := inflater.inflate(data)
+		if err != nil {
+			return Message{}, fmt.Errorf("%w: %w", errInflateFailed, err)
+		}
+		if !ok {
+			return Message{}, errIncompletePayload
+		}
+		data = inflated
+	} else if mt == websocket.BinaryMessage && len(data) > 0 && data[0] == zlibHeader {
+		// binary encodings also send uncompressed binary messages, so we check for the zlib header
 		g.config.Logger.Debug("binary message received. decompressing")
 
-		reader, err := zlib.NewReader(r)
+		reader, err := zlib.NewReader(bytes.NewReader(data))
 		if err != nil {
 			return Message{}, fmt.Errorf("failed to decompress zlib: %w", err)
 		}
 		defer reader.Close()
-		r = reader
+		if data, err = io.ReadAll(reader); err != nil {
+			return Message{}, fmt.Errorf("failed to decompress zlib: %w", err)
+		}
 	}
 
 	if g.config.Logger.Enabled(context.Background(), slog.LevelDebug) {
-		buff := new(bytes.Buffer)
-		tr := io.TeeReader(r, buff)
-		data, err := io.ReadAll(tr)
-		if err != nil {
-			return Message{}, fmt.Errorf("failed to read message: %w", err)
+		if g.config.Encoding.MessageType() == websocket.TextMessage {
+			g.config.Logger.Debug("received gateway message", slog.String("data", string(data)))
+		} else {
+			g.config.Logger.Debug("received gateway message", slog.String("encoding", g.config.Encoding.Name()), slog.Int("size", len(data)))
 		}
-		g.config.Logger.Debug("received gateway message", slog.String("data", string(data)))
-		r = buff
 	}
 
 	var message Message
-	return message, json.NewDecoder(r).Decode(&message)
+	return message, g.config.Encoding.Unmarshal(data, &message)
 }
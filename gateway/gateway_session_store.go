@@ -0,0 +1,172 @@
+package gateway
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+
+	"github.com/disgoorg/json"
+)
+
+// Session holds everything needed to resume a Gateway session.
+type Session struct {
+	// ID is the session ID received in the EventReady.
+	ID string `json:"id"`
+	// Sequence is the last sequence number received.
+	Sequence int `json:"sequence"`
+	// ResumeURL is the resume gateway url received in the EventReady.
+	ResumeURL string `json:"resume_url"`
+	// ShardCount is the shard count the session was created with. Sessions can't be resumed with a different shard count.
+	ShardCount int `json:"shard_count"`
+}
+
+// SessionStore persists Gateway sessions, so they can be resumed after a process restart.
+// Implementations must be safe for concurrent use as all shards of a ShardManager share one SessionStore.
+type SessionStore interface {
+	// Get returns the Session for the given shard ID and whether it was found.
+	Get(ctx context.Context, shardID int) (Session, bool, error)
+
+	// Put stores the Session for the given shard ID.
+	Put(ctx context.Context, shardID int, session Session) error
+
+	// Delete removes the Session for the given shard ID.
+	Delete(ctx context.Context, shardID int) error
+}
+
+var _ SessionStore = (*memorySessionStore)(nil)
+
+// NewMemorySessionStore returns a SessionStore which keeps sessions in memory.
+// This is useful to resume sessions after re-creating a Gateway within the same process.
+func NewMemorySessionStore() SessionStore {
+	return &memorySessionStore{
+		sessions: map[int]Session{},
+	}
+}
+
+type memorySessionStore struct {
+	mu       sync.Mutex
+	sessions map[int]Session
+}
+
+func (s *memorySessionStore) Get(_ context.Context, shardID int) (Session, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	session, ok := s.sessions[shardID]
+	return session, ok, nil
+}
+
+func (s *memorySessionStore) Put(_ context.Context, shardID int, session Session) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.sessions[shardID] = session
+	return nil
+}
+
+func (s *memorySessionStore) Delete(_ context.Context, shardID int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.sessions, shardID)
+	return nil
+}
+
+var _ SessionStore = (*fileSessionStore)(nil)
+
+// NewFileSessionStore returns a SessionStore which keeps the sessions of all shards as JSON in the file at the given path.
+// The file is replaced atomically on every write.
+func NewFileSessionStore(path string) SessionStore {
+	return &fileSessionStore{
+		path: path,
+	}
+}
+
+type fileSessionStore struct {
+	mu   sync.Mutex
+	path string
+}
+
+func (s *fileSessionStore) read() (map[string]Session, error) {
+	data, err := os.ReadFile(s.path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return map[string]Session{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	sessions := map[string]Session{}
+	if len(data) == 0 {
+		return sessions, nil
+	}
+	if err = json.Unmarshal(data, &sessions); err != nil {
+		return nil, err
+	}
+	return sessions, nil
+}
+
+func (s *fileSessionStore) write(sessions map[string]Session) error {
+	data, err := json.Marshal(sessions)
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(s.path), filepath.Base(s.path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = os.Remove(tmp.Name())
+	}()
+
+	if _, err = tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err = tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), s.path)
+}
+
+func (s *fileSessionStore) Get(_ context.Context, shardID int) (Session, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	sessions, err := s.read()
+	if err != nil {
+		return Session{}, false, err
+	}
+	session, ok := sessions[strconv.Itoa(shardID)]
+	return session, ok, nil
+}
+
+func (s *fileSessionStore) Put(_ context.Context, shardID int, session Session) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	sessions, err := s.read()
+	if err != nil {
+		return err
+	}
+	sessions[strconv.Itoa(shardID)] = session
+	return s.write(sessions)
+}
+
+func (s *fileSessionStore) Delete(_ context.Context, shardID int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	sessions, err := s.read()
+	if err != nil {
+		return err
+	}
+	key := strconv.Itoa(shardID)
+	if _, ok := sessions[key]; !ok {
+		return nil
+	}
+	delete(sessions, key)
+	return s.write(sessions)
+}
@@ -0,0 +1,39 @@
+package gateway
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFileSessionStore(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "sessions.json")
+
+	store := NewFileSessionStore(path)
+	_, ok, err := store.Get(ctx, 0)
+	assert.NoError(t, err)
+	assert.False(t, ok)
+
+	session := Session{
+		ID:         "abc",
+		Sequence:   42,
+		ResumeURL:  "wss://gateway-us-east1-b.discord.gg",
+		ShardCount: 2,
+	}
+	assert.NoError(t, store.Put(ctx, 1, session))
+
+	// a new store reading the same file sees the session
+	store = NewFileSessionStore(path)
+	stored, ok, err := store.Get(ctx, 1)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, session, stored)
+
+	assert.NoError(t, store.Delete(ctx, 1))
+	_, ok, err = store.Get(ctx, 1)
+	assert.NoError(t, err)
+	assert.False(t, ok)
+}
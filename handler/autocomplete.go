@@ -14,20 +14,22 @@ type AutocompleteEvent struct {
 	*events.AutocompleteInteractionCreate
 	Vars map[string]string
 	Ctx  context.Context
+
+	restCtx context.Context
 }
 
 func (e *AutocompleteEvent) GetFollowupMessage(messageID snowflake.ID, opts ...rest.RequestOpt) (*discord.Message, error) {
-	return e.Client().Rest().GetFollowupMessage(e.ApplicationID(), e.Token(), messageID, opts...)
+	return e.Client().Rest().GetFollowupMessage(e.ApplicationID(), e.Token(), messageID, withCtx(e.restCtx, opts)...)
 }
 
 func (e *AutocompleteEvent) CreateFollowupMessage(messageCreate discord.MessageCreate, opts ...rest.RequestOpt) (*discord.Message, error) {
-	return e.Client().Rest().CreateFollowupMessage(e.ApplicationID(), e.Token(), messageCreate, opts...)
+	return e.Client().Rest().CreateFollowupMessage(e.ApplicationID(), e.Token(), messageCreate, withCtx(e.restCtx, opts)...)
 }
 
 func (e *AutocompleteEvent) UpdateFollowupMessage(messageID snowflake.ID, messageUpdate discord.MessageUpdate, opts ...rest.RequestOpt) (*discord.Message, error) {
-	return e.Client().Rest().UpdateFollowupMessage(e.ApplicationID(), e.Token(), messageID, messageUpdate, opts...)
+	return e.Client().Rest().UpdateFollowupMessage(e.ApplicationID(), e.Token(), messageID, messageUpdate, withCtx(e.restCtx, opts)...)
 }
 
 func (e *AutocompleteEvent) DeleteFollowupMessage(messageID snowflake.ID, opts ...rest.RequestOpt) error {
-	return e.Client().Rest().DeleteFollowupMessage(e.ApplicationID(), e.Token(), messageID, opts...)
+	return e.Client().Rest().DeleteFollowupMessage(e.ApplicationID(), e.Token(), messageID, withCtx(e.restCtx, opts)...)
 }
@@ -18,6 +18,7 @@
 package handler
 
 import (
+	"context"
 	"errors"
 	"slices"
 	"strings"
@@ -73,6 +74,8 @@ func (h *handlerHolder[T]) Match(path string, t discord.InteractionType, t2 int)
 
 func (h *handlerHolder[T]) Handle(path string, event *InteractionEvent) error {
 	parseVariables(path, h.pattern, event.Vars)
+	event.Pattern += h.pattern
+	respond := withCtxResponder(event.RestCtx, event.Respond)
 
 	switch handler := any(h.handler).(type) {
 	case InteractionHandler:
@@ -82,10 +85,11 @@ func (h *handlerHolder[T]) Handle(path string, event *InteractionEvent) error {
 			ApplicationCommandInteractionCreate: &events.ApplicationCommandInteractionCreate{
 				GenericEvent:                  event.GenericEvent,
 				ApplicationCommandInteraction: event.Interaction.(discord.ApplicationCommandInteraction),
-				Respond:                       event.Respond,
+				Respond:                       respond,
 			},
-			Vars: event.Vars,
-			Ctx:  event.Ctx,
+			Vars:    event.Vars,
+			Ctx:     event.Ctx,
+			restCtx: event.RestCtx,
 		})
 	case SlashCommandHandler:
 		commandInteraction := event.Interaction.(discord.ApplicationCommandInteraction)
@@ -93,10 +97,11 @@ func (h *handlerHolder[T]) Handle(path string, event *InteractionEvent) error {
 			ApplicationCommandInteractionCreate: &events.ApplicationCommandInteractionCreate{
 				GenericEvent:                  event.GenericEvent,
 				ApplicationCommandInteraction: commandInteraction,
-				Respond:                       event.Respond,
+				Respond:                       respond,
 			},
-			Vars: event.Vars,
-			Ctx:  event.Ctx,
+			Vars:    event.Vars,
+			Ctx:     event.Ctx,
+			restCtx: event.RestCtx,
 		})
 	case UserCommandHandler:
 		commandInteraction := event.Interaction.(discord.ApplicationCommandInteraction)
@@ -104,10 +109,11 @@ func (h *handlerHolder[T]) Handle(path string, event *InteractionEvent) error {
 			ApplicationCommandInteractionCreate: &events.ApplicationCommandInteractionCreate{
 				GenericEvent:                  event.GenericEvent,
 				ApplicationCommandInteraction: commandInteraction,
-				Respond:                       event.Respond,
+				Respond:                       respond,
 			},
-			Vars: event.Vars,
-			Ctx:  event.Ctx,
+			Vars:    event.Vars,
+			Ctx:     event.Ctx,
+			restCtx: event.RestCtx,
 		})
 	case MessageCommandHandler:
 		commandInteraction := event.Interaction.(discord.ApplicationCommandInteraction)
@@ -115,30 +121,33 @@ func (h *handlerHolder[T]) Handle(path string, event *InteractionEvent) error {
 			ApplicationCommandInteractionCreate: &events.ApplicationCommandInteractionCreate{
 				GenericEvent:                  event.GenericEvent,
 				ApplicationCommandInteraction: commandInteraction,
-				Respond:                       event.Respond,
+				Respond:                       respond,
 			},
-			Vars: event.Vars,
-			Ctx:  event.Ctx,
+			Vars:    event.Vars,
+			Ctx:     event.Ctx,
+			restCtx: event.RestCtx,
 		})
 	case AutocompleteHandler:
 		return handler(&AutocompleteEvent{
 			AutocompleteInteractionCreate: &events.AutocompleteInteractionCreate{
 				GenericEvent:            event.GenericEvent,
 				AutocompleteInteraction: event.Interaction.(discord.AutocompleteInteraction),
-				Respond:                 event.Respond,
+				Respond:                 respond,
 			},
-			Vars: event.Vars,
-			Ctx:  event.Ctx,
+			Vars:    event.Vars,
+			Ctx:     event.Ctx,
+			restCtx: event.RestCtx,
 		})
 	case ComponentHandler:
 		return handler(&ComponentEvent{
 			ComponentInteractionCreate: &events.ComponentInteractionCreate{
 				GenericEvent:         event.GenericEvent,
 				ComponentInteraction: event.Interaction.(discord.ComponentInteraction),
-				Respond:              event.Respond,
+				Respond:              respond,
 			},
-			Vars: event.Vars,
-			Ctx:  event.Ctx,
+			Vars:    event.Vars,
+			Ctx:     event.Ctx,
+			restCtx: event.RestCtx,
 		})
 	case ButtonComponentHandler:
 		componentInteraction := event.Interaction.(discord.ComponentInteraction)
@@ -146,10 +155,11 @@ func (h *handlerHolder[T]) Handle(path string, event *InteractionEvent) error {
 			ComponentInteractionCreate: &events.ComponentInteractionCreate{
 				GenericEvent:         event.GenericEvent,
 				ComponentInteraction: componentInteraction,
-				Respond:              event.Respond,
+				Respond:              respond,
 			},
-			Vars: event.Vars,
-			Ctx:  event.Ctx,
+			Vars:    event.Vars,
+			Ctx:     event.Ctx,
+			restCtx: event.RestCtx,
 		})
 	case SelectMenuComponentHandler:
 		componentInteraction := event.Interaction.(discord.ComponentInteraction)
@@ -157,21 +167,39 @@ func (h *handlerHolder[T]) Handle(path string, event *InteractionEvent) error {
 			ComponentInteractionCreate: &events.ComponentInteractionCreate{
 				GenericEvent:         event.GenericEvent,
 				ComponentInteraction: componentInteraction,
-				Respond:              event.Respond,
+				Respond:              respond,
 			},
-			Vars: event.Vars,
-			Ctx:  event.Ctx,
+			Vars:    event.Vars,
+			Ctx:     event.Ctx,
+			restCtx: event.RestCtx,
 		})
 	case ModalHandler:
 		return handler(&ModalEvent{
 			ModalSubmitInteractionCreate: &events.ModalSubmitInteractionCreate{
 				GenericEvent:           event.GenericEvent,
 				ModalSubmitInteraction: event.Interaction.(discord.ModalSubmitInteraction),
-				Respond:                event.Respond,
+				Respond:                respond,
 			},
-			Vars: event.Vars,
-			Ctx:  event.Ctx,
+			Vars:    event.Vars,
+			Ctx:     event.Ctx,
+			restCtx: event.RestCtx,
 		})
 	}
 	return errors.New("unknown handler type")
 }
+
+// withCtx prepends rest.WithCtx with the InteractionEvent.RestCtx to the RequestOpt(s), so REST calls made for an interaction carry it.
+// A context passed by the caller takes precedence.
+func withCtx(ctx context.Context, opts []rest.RequestOpt) []rest.RequestOpt {
+	if ctx == nil {
+		return opts
+	}
+	return append([]rest.RequestOpt{rest.WithCtx(ctx)}, opts...)
+}
+
+// withCtxResponder returns an events.InteractionResponderFunc which passes the context to the interaction response request.
+func withCtxResponder(ctx context.Context, respond events.InteractionResponderFunc) events.InteractionResponderFunc {
+	return func(responseType discord.InteractionResponseType, data discord.InteractionResponseData, opts ...rest.RequestOpt) error {
+		return respond(responseType, data, withCtx(ctx, opts)...)
+	}
+}
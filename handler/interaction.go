@@ -14,11 +14,17 @@ type InteractionEvent struct {
 	*events.InteractionCreate
 	Vars map[string]string
 	Ctx  context.Context
+	// RestCtx is passed to the REST calls made through the event, e.g. responses & followup messages.
+	// It is nil by default. Followup messages can be sent after the Handler returned, so it should not be canceled with the Handler.
+	RestCtx context.Context
+	// Pattern is the full pattern of the route which handles the event, e.g. /test/{id}.
+	// It is set while routing, so middlewares can read it after calling the next Handler.
+	Pattern string
 }
 
 // CreateMessage responds to the interaction with a new message.
 func (e *InteractionEvent) CreateMessage(messageCreate discord.MessageCreate, opts ...rest.RequestOpt) error {
-	return e.Respond(discord.InteractionResponseTypeCreateMessage, messageCreate, opts...)
+	return e.Respond(discord.InteractionResponseTypeCreateMessage, messageCreate, withCtx(e.RestCtx, opts)...)
 }
 
 // DeferCreateMessage responds to the interaction with a "bot is thinking..." message which should be edited later.
@@ -27,58 +33,58 @@ func (e *InteractionEvent) DeferCreateMessage(ephemeral bool, opts ...rest.Reque
 	if ephemeral {
 		data = discord.MessageCreate{Flags: discord.MessageFlagEphemeral}
 	}
-	return e.Respond(discord.InteractionResponseTypeDeferredCreateMessage, data, opts...)
+	return e.Respond(discord.InteractionResponseTypeDeferredCreateMessage, data, withCtx(e.RestCtx, opts)...)
 }
 
 // UpdateMessage responds to the interaction with updating the message the component is from.
 func (e *InteractionEvent) UpdateMessage(messageUpdate discord.MessageUpdate, opts ...rest.RequestOpt) error {
-	return e.Respond(discord.InteractionResponseTypeUpdateMessage, messageUpdate, opts...)
+	return e.Respond(discord.InteractionResponseTypeUpdateMessage, messageUpdate, withCtx(e.RestCtx, opts)...)
 }
 
 // DeferUpdateMessage responds to the interaction with nothing.
 func (e *InteractionEvent) DeferUpdateMessage(opts ...rest.RequestOpt) error {
-	return e.Respond(discord.InteractionResponseTypeDeferredUpdateMessage, nil, opts...)
+	return e.Respond(discord.InteractionResponseTypeDeferredUpdateMessage, nil, withCtx(e.RestCtx, opts)...)
 }
 
 // Deprecated: Respond with a discord.ButtonStylePremium button instead.
 // PremiumRequired responds to the interaction with an upgrade button if available.
 func (e *InteractionEvent) PremiumRequired(opts ...rest.RequestOpt) error {
-	return e.Respond(discord.InteractionResponseTypePremiumRequired, nil, opts...)
+	return e.Respond(discord.InteractionResponseTypePremiumRequired, nil, withCtx(e.RestCtx, opts)...)
 }
 
 // Modal responds to the interaction with a new modal.
 func (e *InteractionEvent) Modal(modalCreate discord.ModalCreate, opts ...rest.RequestOpt) error {
-	return e.Respond(discord.InteractionResponseTypeModal, modalCreate, opts...)
+	return e.Respond(discord.InteractionResponseTypeModal, modalCreate, withCtx(e.RestCtx, opts)...)
 }
 
 func (e *InteractionEvent) AutocompleteResult(choices []discord.AutocompleteChoice, opts ...rest.RequestOpt) error {
-	return e.Respond(discord.InteractionResponseTypeAutocompleteResult, discord.AutocompleteResult{Choices: choices}, opts...)
+	return e.Respond(discord.InteractionResponseTypeAutocompleteResult, discord.AutocompleteResult{Choices: choices}, withCtx(e.RestCtx, opts)...)
 }
 
 func (e *InteractionEvent) GetInteractionResponse(opts ...rest.RequestOpt) (*discord.Message, error) {
-	return e.Client().Rest().GetInteractionResponse(e.ApplicationID(), e.Token(), opts...)
+	return e.Client().Rest().GetInteractionResponse(e.ApplicationID(), e.Token(), withCtx(e.RestCtx, opts)...)
 }
 
 func (e *InteractionEvent) UpdateInteractionResponse(messageUpdate discord.MessageUpdate, opts ...rest.RequestOpt) (*discord.Message, error) {
-	return e.Client().Rest().UpdateInteractionResponse(e.ApplicationID(), e.Token(), messageUpdate, opts...)
+	return e.Client().Rest().UpdateInteractionResponse(e.ApplicationID(), e.Token(), messageUpdate, withCtx(e.RestCtx, opts)...)
 }
 
 func (e *InteractionEvent) DeleteInteractionResponse(opts ...rest.RequestOpt) error {
-	return e.Client().Rest().DeleteInteractionResponse(e.ApplicationID(), e.Token(), opts...)
+	return e.Client().Rest().DeleteInteractionResponse(e.ApplicationID(), e.Token(), withCtx(e.RestCtx, opts)...)
 }
 
 func (e *InteractionEvent) GetFollowupMessage(messageID snowflake.ID, opts ...rest.RequestOpt) (*discord.Message, error) {
-	return e.Client().Rest().GetFollowupMessage(e.ApplicationID(), e.Token(), messageID, opts...)
+	return e.Client().Rest().GetFollowupMessage(e.ApplicationID(), e.Token(), messageID, withCtx(e.RestCtx, opts)...)
 }
 
 func (e *InteractionEvent) CreateFollowupMessage(messageCreate discord.MessageCreate, opts ...rest.RequestOpt) (*discord.Message, error) {
-	return e.Client().Rest().CreateFollowupMessage(e.ApplicationID(), e.Token(), messageCreate, opts...)
+	return e.Client().Rest().CreateFollowupMessage(e.ApplicationID(), e.Token(), messageCreate, withCtx(e.RestCtx, opts)...)
 }
 
 func (e *InteractionEvent) UpdateFollowupMessage(messageID snowflake.ID, messageUpdate discord.MessageUpdate, opts ...rest.RequestOpt) (*discord.Message, error) {
-	return e.Client().Rest().UpdateFollowupMessage(e.ApplicationID(), e.Token(), messageID, messageUpdate, opts...)
+	return e.Client().Rest().UpdateFollowupMessage(e.ApplicationID(), e.Token(), messageID, messageUpdate, withCtx(e.RestCtx, opts)...)
 }
 
 func (e *InteractionEvent) DeleteFollowupMessage(messageID snowflake.ID, opts ...rest.RequestOpt) error {
-	return e.Client().Rest().DeleteFollowupMessage(e.ApplicationID(), e.Token(), messageID, opts...)
+	return e.Client().Rest().DeleteFollowupMessage(e.ApplicationID(), e.Token(), messageID, withCtx(e.RestCtx, opts)...)
 }
@@ -110,6 +110,7 @@ func (r *Mux) Match(path string, t discord.InteractionType, t2 int) bool {
 func (r *Mux) Handle(path string, event *InteractionEvent) error {
 	handlerChain := Handler(func(event *InteractionEvent) error {
 		path = parseVariables(path, r.pattern, event.Vars)
+		event.Pattern += r.pattern
 
 		t := event.Type()
 		var t2 int
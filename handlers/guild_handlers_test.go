@@ -0,0 +1,46 @@
+package handlers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/disgoorg/disgo/bot"
+	"github.com/disgoorg/disgo/events"
+	"github.com/disgoorg/disgo/gateway"
+)
+
+func TestGuildCreate_AvailableAfterUnavailable(t *testing.T) {
+	client, err := bot.BuildClient("MTIzNDU2Nzg5.token.secret", bot.DefaultConfig(GetGatewayHandlers(), nil), DefaultGatewayEventHandlerFunc, nil, "", "", "", "")
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	var dispatched []bot.Event
+	client.AddEventListeners(bot.NewListenerFunc(func(e bot.Event) {
+		switch e.(type) {
+		case *events.GuildJoin, *events.GuildAvailable, *events.GuildUnavailable:
+			dispatched = append(dispatched, e)
+		}
+	}))
+
+	var guildCreate gateway.EventGuildCreate
+	guildCreate.ID = 1
+
+	var guildDelete gateway.EventGuildDelete
+	guildDelete.ID = 1
+	guildDelete.Unavailable = true
+
+	gatewayHandlerGuildCreate(client, 0, 0, guildCreate)
+	gatewayHandlerGuildDelete(client, 0, 0, guildDelete)
+	assert.True(t, client.Caches().IsGuildUnavailable(1))
+
+	gatewayHandlerGuildCreate(client, 0, 0, guildCreate)
+	assert.False(t, client.Caches().IsGuildUnavailable(1))
+
+	if assert.Len(t, dispatched, 3) {
+		assert.IsType(t, &events.GuildJoin{}, dispatched[0])
+		assert.IsType(t, &events.GuildUnavailable{}, dispatched[1])
+		assert.IsType(t, &events.GuildAvailable{}, dispatched[2])
+	}
+}
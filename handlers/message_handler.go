@@ -20,6 +20,12 @@ func gatewayHandlerMessageCreate(client bot.Client, sequenceNumber int, shardID
 	}
 
 	client.Caches().AddMessage(event.Message)
+	if history := client.MessageHistory(); history != nil {
+		// cached messages get their first revision on their first update, so only messages the cache didn't keep are added right away
+		if _, ok := client.Caches().Message(event.ChannelID, event.ID); !ok {
+			history.AddRevision(event.Message)
+		}
+	}
 
 	if channel, ok := client.Caches().GuildMessageChannel(event.ChannelID); ok {
 		client.Caches().AddChannel(discord.ApplyLastMessageIDToChannel(channel, event.ID))
@@ -65,9 +71,21 @@ func gatewayHandlerMessageCreate(client bot.Client, sequenceNumber int, shardID
 }
 
 func gatewayHandlerMessageUpdate(client bot.Client, sequenceNumber int, shardID int, event gateway.EventMessageUpdate) {
-	oldMessage, _ := client.Caches().Message(event.ChannelID, event.ID)
+	oldMessage, ok := client.Caches().Message(event.ChannelID, event.ID)
 	client.Caches().AddMessage(event.Message)
 
+	var revisions []discord.Message
+	if history := client.MessageHistory(); history != nil {
+		if ok && len(history.Revisions(event.ChannelID, event.ID)) == 0 {
+			history.AddRevision(oldMessage)
+		}
+		revisions = history.AddRevision(event.Message)
+		// fall back to the previous revision if the message is no longer cached
+		if !ok && len(revisions) > 1 {
+			oldMessage = revisions[len(revisions)-2]
+		}
+	}
+
 	genericEvent := events.NewGenericEvent(client, sequenceNumber, shardID)
 	client.EventManager().DispatchEvent(&events.MessageUpdate{
 		GenericMessage: &events.GenericMessage{
@@ -78,6 +96,7 @@ func gatewayHandlerMessageUpdate(client bot.Client, sequenceNumber int, shardID
 			GuildID:      event.GuildID,
 		},
 		OldMessage: oldMessage,
+		Revisions:  revisions,
 	})
 
 	if event.GuildID == nil {
@@ -89,6 +108,7 @@ func gatewayHandlerMessageUpdate(client bot.Client, sequenceNumber int, shardID
 				ChannelID:    event.ChannelID,
 			},
 			OldMessage: oldMessage,
+			Revisions:  revisions,
 		})
 	} else {
 		client.EventManager().DispatchEvent(&events.GuildMessageUpdate{
@@ -100,6 +120,7 @@ func gatewayHandlerMessageUpdate(client bot.Client, sequenceNumber int, shardID
 				GuildID:      *event.GuildID,
 			},
 			OldMessage: oldMessage,
+			Revisions:  revisions,
 		})
 	}
 }
@@ -117,7 +138,16 @@ func gatewayHandlerMessageDeleteBulk(client bot.Client, sequenceNumber int, shar
 func handleMessageDelete(client bot.Client, sequenceNumber int, shardID int, messageID snowflake.ID, channelID snowflake.ID, guildID *snowflake.ID) {
 	genericEvent := events.NewGenericEvent(client, sequenceNumber, shardID)
 
-	message, _ := client.Caches().RemoveMessage(channelID, messageID)
+	message, ok := client.Caches().RemoveMessage(channelID, messageID)
+
+	var revisions []discord.Message
+	if history := client.MessageHistory(); history != nil {
+		revisions = history.RemoveRevisions(channelID, messageID)
+		// fall back to the newest revision if the message is no longer cached
+		if !ok && len(revisions) > 0 {
+			message = revisions[len(revisions)-1]
+		}
+	}
 
 	if channel, ok := client.Caches().GuildThread(channelID); ok {
 		if channel.MessageCount > 0 {
@@ -134,6 +164,7 @@ func handleMessageDelete(client bot.Client, sequenceNumber int, shardID int, mes
 			ChannelID:    channelID,
 			GuildID:      guildID,
 		},
+		Revisions: revisions,
 	})
 
 	if guildID == nil {
@@ -144,6 +175,7 @@ func handleMessageDelete(client bot.Client, sequenceNumber int, shardID int, mes
 				Message:      message,
 				ChannelID:    channelID,
 			},
+			Revisions: revisions,
 		})
 	} else {
 		client.EventManager().DispatchEvent(&events.GuildMessageDelete{
@@ -154,6 +186,7 @@ func handleMessageDelete(client bot.Client, sequenceNumber int, shardID int, mes
 				ChannelID:    channelID,
 				GuildID:      *guildID,
 			},
+			Revisions: revisions,
 		})
 	}
 }
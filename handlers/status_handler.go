@@ -20,6 +20,13 @@ func gatewayHandlerHeartbeatAck(client bot.Client, sequenceNumber int, shardID i
 	})
 }
 
+func gatewayHandlerSessionStartLimit(client bot.Client, sequenceNumber int, shardID int, event gateway.EventSessionStartLimit) {
+	client.EventManager().DispatchEvent(&events.SessionStartLimit{
+		GenericEvent:           events.NewGenericEvent(client, sequenceNumber, shardID),
+		EventSessionStartLimit: event,
+	})
+}
+
 func gatewayHandlerReady(client bot.Client, sequenceNumber int, shardID int, event gateway.EventReady) {
 	client.Caches().SetSelfUser(event.User)
 
@@ -158,6 +158,10 @@ func (s *channelImpl) CreateMessage(channelID snowflake.ID, messageCreate discor
 	if err != nil {
 		return
 	}
+	if messageCreate.Nonce != "" && messageCreate.EnforceNonce {
+		// Discord deduplicates messages with an enforced nonce, so they are safe to retry
+		opts = append([]RequestOpt{WithIdempotent()}, opts...)
+	}
 	err = s.client.Do(CreateMessage.Compile(nil, channelID), body, &message, opts...)
 	return
 }
@@ -20,10 +20,15 @@ func DefaultRequestConfig(rq *http.Request) *RequestConfig {
 
 // RequestConfig are additional options for the request
 type RequestConfig struct {
-	Request *http.Request
-	Ctx     context.Context
-	Checks  []Check
-	Delay   time.Duration
+	Request  *http.Request
+	Ctx      context.Context
+	Checks   []Check
+	Delay    time.Duration
+	Priority Priority
+	// Idempotent marks the request as safe to retry after transient errors
+	Idempotent bool
+
+	responseHook func(rs *http.Response)
 }
 
 // Check is a function which gets executed right before a request is made
@@ -63,6 +68,21 @@ func WithDelay(delay time.Duration) RequestOpt {
 	}
 }
 
+// WithPriority sets the Priority of the request in the RateLimiter
+func WithPriority(priority Priority) RequestOpt {
+	return func(config *RequestConfig) {
+		config.Priority = priority
+	}
+}
+
+// WithIdempotent marks the request as safe to retry after transient errors, e.g. a POST request which Discord deduplicates.
+// GET, HEAD, PUT, DELETE & OPTIONS requests are always retried
+func WithIdempotent() RequestOpt {
+	return func(config *RequestConfig) {
+		config.Idempotent = true
+	}
+}
+
 // WithHeader adds a custom header to the request
 func WithHeader(key string, value string) RequestOpt {
 	return func(config *RequestConfig) {
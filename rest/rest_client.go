@@ -23,10 +23,12 @@ func NewClient(botToken string, opts ...ConfigOpt) Client {
 
 	config.RateLimiter.Reset()
 
-	return &clientImpl{
+	client := &clientImpl{
 		botToken: botToken,
 		config:   *config,
 	}
+	client.handler = chainMiddlewares(client.handle, config.Middlewares)
+	return client
 }
 
 // Client allows doing requests to different endpoints
@@ -47,6 +49,7 @@ type Client interface {
 type clientImpl struct {
 	botToken string
 	config   Config
+	handler  Handler
 }
 
 func (c *clientImpl) Close(ctx context.Context) {
@@ -62,7 +65,8 @@ func (c *clientImpl) RateLimiter() RateLimiter {
 	return c.config.RateLimiter
 }
 
-func (c *clientImpl) retry(endpoint *CompiledEndpoint, rqBody any, rsBody any, tries int, opts []RequestOpt) error {
+// retry does the request. tries counts the rate limited attempts & retries the retries after transient errors.
+func (c *clientImpl) retry(endpoint *CompiledEndpoint, rqBody any, rsBody any, tries int, retries int, opts []RequestOpt) error {
 	var (
 		rawRqBody   []byte
 		err         error
@@ -116,6 +120,10 @@ func (c *clientImpl) retry(endpoint *CompiledEndpoint, rqBody any, rsBody any, t
 		}
 	}
 
+	if config.Priority != PriorityNormal {
+		config.Ctx = ContextWithPriority(config.Ctx, config.Priority)
+	}
+
 	// wait for rate limits
 	err = c.RateLimiter().WaitBucket(config.Ctx, endpoint)
 	if err != nil {
@@ -133,9 +141,19 @@ func (c *clientImpl) retry(endpoint *CompiledEndpoint, rqBody any, rsBody any, t
 	rs, err := c.HTTPClient().Do(config.Request)
 	if err != nil {
 		_ = c.RateLimiter().UnlockBucket(endpoint, nil)
+		if c.shouldRetry(endpoint, config, nil, err, retries) {
+			if err = c.waitRetry(endpoint, config, nil, err, retries+1); err != nil {
+				return err
+			}
+			return c.retry(endpoint, rqBody, rsBody, tries, retries+1, opts)
+		}
 		return fmt.Errorf("error doing request in rest client: %w", err)
 	}
 
+	if config.responseHook != nil {
+		config.responseHook(rs)
+	}
+
 	if err = c.RateLimiter().UnlockBucket(endpoint, rs); err != nil {
 		return fmt.Errorf("error unlocking bucket in rest client: %w", err)
 	}
@@ -145,6 +163,9 @@ func (c *clientImpl) retry(endpoint *CompiledEndpoint, rqBody any, rsBody any, t
 		if rawRsBody, err = io.ReadAll(rs.Body); err != nil {
 			return fmt.Errorf("error reading response body in rest client: %w", err)
 		}
+		_ = rs.Body.Close()
+		// allow middlewares to read the body again
+		rs.Body = io.NopCloser(bytes.NewReader(rawRsBody))
 		c.config.Logger.Debug("new response", slog.String("endpoint", endpoint.URL), slog.String("code", rs.Status), slog.String("body", string(rawRsBody)))
 	}
 
@@ -162,13 +183,62 @@ func (c *clientImpl) retry(endpoint *CompiledEndpoint, rqBody any, rsBody any, t
 		if tries >= c.RateLimiter().MaxRetries() {
 			return NewError(rq, rawRqBody, rs, rawRsBody)
 		}
-		return c.retry(endpoint, rqBody, rsBody, tries+1, opts)
+		return c.retry(endpoint, rqBody, rsBody, tries+1, retries, opts)
 
 	default:
+		if c.shouldRetry(endpoint, config, rs, nil, retries) {
+			if err = c.waitRetry(endpoint, config, rs, nil, retries+1); err != nil {
+				return err
+			}
+			return c.retry(endpoint, rqBody, rsBody, tries, retries+1, opts)
+		}
 		return NewError(rq, rawRqBody, rs, rawRsBody)
 	}
 }
 
+func (c *clientImpl) shouldRetry(endpoint *CompiledEndpoint, config *RequestConfig, rs *http.Response, err error, retries int) bool {
+	retry := c.config.Retry
+	if retries >= retry.MaxRetries || !isIdempotent(endpoint.Endpoint.Method, config) || config.Ctx.Err() != nil {
+		return false
+	}
+	if retry.ShouldRetry == nil {
+		return DefaultShouldRetry(rs, err)
+	}
+	return retry.ShouldRetry(rs, err)
+}
+
+// waitRetry calls the retry hook & waits for the backoff of the given retry.
+func (c *clientImpl) waitRetry(endpoint *CompiledEndpoint, config *RequestConfig, rs *http.Response, err error, retry int) error {
+	backoff := c.config.Retry.backoff(retry)
+	c.config.Logger.Debug("retrying request", slog.String("endpoint", endpoint.URL), slog.Int("retry", retry), slog.Duration("backoff", backoff), slog.Any("err", err))
+	if c.config.Retry.OnRetry != nil {
+		c.config.Retry.OnRetry(RetryEvent{
+			Endpoint: endpoint,
+			Response: rs,
+			Err:      err,
+			Retry:    retry,
+			Backoff:  backoff,
+		})
+	}
+
+	timer := time.NewTimer(backoff)
+	defer timer.Stop()
+	select {
+	case <-config.Ctx.Done():
+		return config.Ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
 func (c *clientImpl) Do(endpoint *CompiledEndpoint, rqBody any, rsBody any, opts ...RequestOpt) error {
-	return c.retry(endpoint, rqBody, rsBody, 1, opts)
+	return c.handler(newCall(endpoint, rqBody, rsBody, opts))
+}
+
+// handle is the innermost Handler which does the Call.
+func (c *clientImpl) handle(call *Call) error {
+	opts := append(call.Opts[:len(call.Opts):len(call.Opts)], withResponseHook(func(rs *http.Response) {
+		call.Response = rs
+	}))
+	return c.retry(call.Endpoint, call.RqBody, call.RsBody, 1, 0, opts)
 }
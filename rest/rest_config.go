@@ -13,6 +13,7 @@ func DefaultConfig() *Config {
 		Logger:     slog.Default(),
 		HTTPClient: &http.Client{Timeout: 20 * time.Second},
 		URL:        fmt.Sprintf("%sv%d", API, Version),
+		Retry:      DefaultRetryConfig(),
 	}
 }
 
@@ -24,6 +25,8 @@ type Config struct {
 	RateLimiterConfigOpts []RateLimiterConfigOpt
 	URL                   string
 	UserAgent             string
+	Retry                 RetryConfig
+	Middlewares           []Middleware
 }
 
 // ConfigOpt can be used to supply optional parameters to NewClient
@@ -80,3 +83,33 @@ func WithUserAgent(userAgent string) ConfigOpt {
 		config.UserAgent = userAgent
 	}
 }
+
+// WithRetryConfig sets the RetryConfig used to retry requests which failed because of transient errors
+func WithRetryConfig(retryConfig RetryConfig) ConfigOpt {
+	return func(config *Config) {
+		config.Retry = retryConfig
+	}
+}
+
+// WithRetries sets the maximum number of retries & the backoff range for requests which failed because of transient errors. Zero retries disable them
+func WithRetries(maxRetries int, minBackoff time.Duration, maxBackoff time.Duration) ConfigOpt {
+	return func(config *Config) {
+		config.Retry.MaxRetries = maxRetries
+		config.Retry.MinBackoff = minBackoff
+		config.Retry.MaxBackoff = maxBackoff
+	}
+}
+
+// WithRetryHook sets a function which is called before each retry of a request which failed because of a transient error
+func WithRetryHook(onRetry func(event RetryEvent)) ConfigOpt {
+	return func(config *Config) {
+		config.Retry.OnRetry = onRetry
+	}
+}
+
+// WithMiddleware adds Middleware(s) which wrap all requests of the rest client. The first Middleware is the outermost one
+func WithMiddleware(middlewares ...Middleware) ConfigOpt {
+	return func(config *Config) {
+		config.Middlewares = append(config.Middlewares, middlewares...)
+	}
+}
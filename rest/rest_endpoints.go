@@ -3,7 +3,9 @@ package rest
 import (
 	"fmt"
 	"net/http"
+	"slices"
 	"strings"
+	"sync"
 
 	"github.com/disgoorg/disgo/discord"
 )
@@ -311,22 +313,41 @@ var (
 	DeleteApplicationEmoji = NewEndpoint(http.MethodDelete, "/applications/{application.id}/emojis/{emoji.id}")
 )
 
+var (
+	endpoints   []*Endpoint
+	endpointsMu sync.Mutex
+)
+
+// Endpoints returns all Endpoint(s) created with NewEndpoint or NewNoBotAuthEndpoint in the order they were created, which includes all Endpoint(s) of this package.
+func Endpoints() []*Endpoint {
+	endpointsMu.Lock()
+	defer endpointsMu.Unlock()
+	return slices.Clone(endpoints)
+}
+
+func registerEndpoint(endpoint *Endpoint) *Endpoint {
+	endpointsMu.Lock()
+	defer endpointsMu.Unlock()
+	endpoints = append(endpoints, endpoint)
+	return endpoint
+}
+
 // NewEndpoint returns a new Endpoint which requires bot auth with the given http method & route.
 func NewEndpoint(method string, route string) *Endpoint {
-	return &Endpoint{
+	return registerEndpoint(&Endpoint{
 		Method:  method,
 		Route:   route,
 		BotAuth: true,
-	}
+	})
 }
 
 // NewNoBotAuthEndpoint returns a new Endpoint which does not require bot auth with the given http method & route.
 func NewNoBotAuthEndpoint(method string, route string) *Endpoint {
-	return &Endpoint{
+	return registerEndpoint(&Endpoint{
 		Method:  method,
 		Route:   route,
 		BotAuth: false,
-	}
+	})
 }
 
 // Endpoint represents a Discord Rest API endpoint.
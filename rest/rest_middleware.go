@@ -0,0 +1,80 @@
+package rest
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+)
+
+// Call is a single call of Client.Do passed through the Middleware(s) of the Client.
+type Call struct {
+	// Endpoint is the endpoint the request is made to.
+	Endpoint *CompiledEndpoint
+	// RqBody is the request body which is encoded as JSON, multipart or url values.
+	RqBody any
+	// RsBody is decoded from the response body if the request succeeded.
+	RsBody any
+	// Opts are the RequestOpt(s) of the request. Use SetCtx to change the context of the request.
+	Opts []RequestOpt
+	// Response is the last http.Response received for the Call. It is set once the next Handler returns & nil if no response was received.
+	// Its body can be read again.
+	Response *http.Response
+
+	// ctx is the context resolved from Opts, nil if it was not resolved yet
+	ctx context.Context
+}
+
+// newCall returns a new Call with the context resolved from the given RequestOpt(s).
+func newCall(endpoint *CompiledEndpoint, rqBody any, rsBody any, opts []RequestOpt) *Call {
+	call := &Call{
+		Endpoint: endpoint,
+		RqBody:   rqBody,
+		RsBody:   rsBody,
+		Opts:     opts,
+	}
+	call.ctx = call.resolveCtx()
+	return call
+}
+
+// Ctx returns the context of the Call set with WithCtx or SetCtx or context.TODO if none is set.
+func (c *Call) Ctx() context.Context {
+	if c.ctx == nil {
+		c.ctx = c.resolveCtx()
+	}
+	return c.ctx
+}
+
+// SetCtx sets the context of the Call, which is used for the request & returned by Ctx.
+func (c *Call) SetCtx(ctx context.Context) {
+	c.ctx = ctx
+	c.Opts = append(c.Opts[:len(c.Opts):len(c.Opts)], WithCtx(ctx))
+}
+
+func (c *Call) resolveCtx() context.Context {
+	config := DefaultRequestConfig(&http.Request{Header: http.Header{}, URL: &url.URL{}})
+	config.Apply(c.Opts)
+	return config.Ctx
+}
+
+// Handler handles a Call and returns its error.
+type Handler func(call *Call) error
+
+// Middleware wraps the Handler of the Client.Do calls. It can inspect or modify the Call before calling the next Handler
+// and inspect the Response & error after, or handle the Call itself without calling the next Handler.
+// This can be used for tracing, metrics, auditing, caching or fault injection.
+type Middleware func(next Handler) Handler
+
+// chainMiddlewares wraps the given Handler with the given Middleware(s). The first Middleware is the outermost one.
+func chainMiddlewares(handler Handler, middlewares []Middleware) Handler {
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		handler = middlewares[i](handler)
+	}
+	return handler
+}
+
+// withResponseHook sets a function which is called with each http.Response received for the request.
+func withResponseHook(hook func(rs *http.Response)) RequestOpt {
+	return func(config *RequestConfig) {
+		config.responseHook = hook
+	}
+}
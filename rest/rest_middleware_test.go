@@ -0,0 +1,101 @@
+package rest
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClientMiddleware(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id":"1","content":"test"}`))
+	}))
+	t.Cleanup(server.Close)
+
+	var order []string
+	middleware := func(name string) Middleware {
+		return func(next Handler) Handler {
+			return func(call *Call) error {
+				order = append(order, name+" before")
+				err := next(call)
+				order = append(order, name+" after")
+				return err
+			}
+		}
+	}
+
+	var (
+		call *Call
+		body []byte
+	)
+	client := NewClient("token",
+		WithURL(server.URL),
+		WithMiddleware(middleware("first"), middleware("second")),
+		WithMiddleware(func(next Handler) Handler {
+			return func(c *Call) error {
+				err := next(c)
+				call = c
+				if c.Response != nil {
+					body, _ = io.ReadAll(c.Response.Body)
+				}
+				return err
+			}
+		}),
+	)
+
+	message, err := NewChannels(client).GetMessage(1, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, "test", message.Content)
+	assert.Equal(t, []string{"first before", "second before", "second after", "first after"}, order)
+	if assert.NotNil(t, call) {
+		assert.Equal(t, GetMessage, call.Endpoint.Endpoint)
+		if assert.NotNil(t, call.Response) {
+			assert.Equal(t, http.StatusOK, call.Response.StatusCode)
+		}
+	}
+	assert.JSONEq(t, `{"id":"1","content":"test"}`, string(body))
+}
+
+func TestClientMiddlewareShortCircuit(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+	}))
+	t.Cleanup(server.Close)
+
+	injected := errors.New("injected fault")
+	client := NewClient("token",
+		WithURL(server.URL),
+		WithMiddleware(func(next Handler) Handler {
+			return func(call *Call) error {
+				if call.Endpoint.Endpoint.Method == http.MethodDelete {
+					return injected
+				}
+				return next(call)
+			}
+		}),
+	)
+
+	err := NewChannels(client).DeleteMessage(1, 2)
+	assert.ErrorIs(t, err, injected)
+	assert.Equal(t, int32(0), requests.Load())
+}
+
+func TestCallCtx(t *testing.T) {
+	type ctxKey struct{}
+	ctx := context.WithValue(context.Background(), ctxKey{}, "do")
+	call := newCall(GetMessage.Compile(nil, 1, 2), nil, nil, []RequestOpt{WithCtx(ctx)})
+	assert.Equal(t, ctx, call.Ctx())
+
+	spanCtx := context.WithValue(ctx, ctxKey{}, "span")
+	call.SetCtx(spanCtx)
+	assert.Equal(t, spanCtx, call.Ctx())
+	assert.Equal(t, spanCtx, call.resolveCtx(), "the context of the request should match Ctx")
+}
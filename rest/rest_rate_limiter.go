@@ -8,8 +8,6 @@ import (
 	"strconv"
 	"sync"
 	"time"
-
-	"github.com/sasha-s/go-csync"
 )
 
 const (
@@ -17,6 +15,15 @@ const (
 	MaxRetries = 10
 	// CleanupInterval is the interval at which the rate limiter cleans up old buckets
 	CleanupInterval = time.Second * 10
+	// MaxPrioritySkips is how often a waiting request can be skipped in favor of requests with a higher Priority
+	MaxPrioritySkips = 8
+	// GlobalRateLimit is the number of requests per second a bot can make
+	GlobalRateLimit = 50
+	// MaxInvalidRequests is the number of invalid requests after which the rate limiter stops sending requests.
+	// Discord bans the IP after 10,000 invalid requests within InvalidRequestsInterval, this leaves some margin for requests in flight.
+	MaxInvalidRequests = 9000
+	// InvalidRequestsInterval is the interval in which invalid requests are counted
+	InvalidRequestsInterval = 10 * time.Minute
 )
 
 // RateLimiter can be used to supply your own rate limit implementation
@@ -31,7 +38,8 @@ type RateLimiter interface {
 	// Reset resets the rate limiter to its initial state
 	Reset()
 
-	// WaitBucket waits for the given bucket to be available for new requests & locks it
+	// WaitBucket waits for the given bucket to be available for new requests & locks it.
+	// The Priority of the request can be read from the context with PriorityFromContext.
 	WaitBucket(ctx context.Context, endpoint *CompiledEndpoint) error
 
 	// UnlockBucket unlocks the given bucket and calculates the rate limit for the next request
@@ -49,6 +57,7 @@ func NewRateLimiter(opts ...RateLimiterConfigOpt) RateLimiter {
 		hashes:  map[*Endpoint]string{},
 		buckets: map[string]*bucket{},
 	}
+	rateLimiter.Reset()
 
 	go rateLimiter.cleanup()
 
@@ -61,6 +70,12 @@ type (
 
 		// global Rate Limit
 		global time.Time
+		// releases requests waiting for the global rate limit in priority order
+		globalMu priorityMutex
+		// proactive global requests per second budget, nil if disabled
+		budget *globalBudget
+		// circuit breaker for invalid requests, nil if disabled
+		invalidRequests *invalidRequestCounter
 
 		// APIRoute -> Hash
 		hashes   map[*Endpoint]string
@@ -108,7 +123,7 @@ func (l *rateLimiterImpl) Close(ctx context.Context) {
 		wg.Add(1)
 		b := l.buckets[i]
 		go func() {
-			_ = b.mu.CLock(ctx)
+			_ = b.mu.Lock(ctx, PriorityBackground)
 			wg.Done()
 		}()
 	}
@@ -119,6 +134,15 @@ func (l *rateLimiterImpl) Reset() {
 	l.buckets = map[string]*bucket{}
 	l.bucketsMu = sync.Mutex{}
 	l.global = time.Time{}
+	l.globalMu = priorityMutex{maxSkips: l.config.MaxPrioritySkips}
+	l.budget = nil
+	if l.config.GlobalRateLimit > 0 {
+		l.budget = &globalBudget{limit: l.config.GlobalRateLimit}
+	}
+	l.invalidRequests = nil
+	if l.config.MaxInvalidRequests > 0 {
+		l.invalidRequests = newInvalidRequestCounter(l.config.MaxInvalidRequests, l.config.InvalidRequestsInterval)
+	}
 	l.hashes = map[*Endpoint]string{}
 	l.hashesMu = sync.Mutex{}
 }
@@ -157,44 +181,76 @@ func (l *rateLimiterImpl) getBucket(endpoint *CompiledEndpoint, create bool) *bu
 			Remaining: 1,
 			// we don't know the limit yet
 			Limit: -1,
+			mu:    priorityMutex{maxSkips: l.config.MaxPrioritySkips},
 		}
 		l.buckets[hash] = b
 	}
 	return b
 }
 
+// WaitBucket waits for the given bucket to be available for new requests & locks it.
+// Requests waiting for the same bucket or the global rate limit are served in the order of their Priority, see PriorityFromContext.
+// Requests authenticated with the bot token also wait for the global requests per second budget.
+// It returns ErrTooManyInvalidRequests while too many invalid requests were made.
 func (l *rateLimiterImpl) WaitBucket(ctx context.Context, endpoint *CompiledEndpoint) error {
+	if l.invalidRequests != nil && l.invalidRequests.tripped(time.Now()) {
+		return ErrTooManyInvalidRequests
+	}
+
+	priority := PriorityFromContext(ctx)
 	b := l.getBucket(endpoint, true)
-	l.config.Logger.Debug("locking rest bucket", slog.String("id", b.ID), slog.Int("limit", b.Limit), slog.Int("remaining", b.Remaining), slog.Time("reset", b.Reset))
-	if err := b.mu.CLock(ctx); err != nil {
+	l.config.Logger.Debug("locking rest bucket", slog.String("id", b.ID), slog.Int("limit", b.Limit), slog.Int("remaining", b.Remaining), slog.Time("reset", b.Reset), slog.String("priority", priority.String()))
+	if err := b.mu.Lock(ctx, priority); err != nil {
 		return err
 	}
 
-	var until time.Time
-	now := time.Now()
-
-	if b.Remaining == 0 && b.Reset.After(now) {
-		until = b.Reset
-	} else {
-		until = l.global
+	if b.Remaining == 0 {
+		if err := waitUntil(ctx, b.Reset); err != nil {
+			b.mu.Unlock()
+			return err
+		}
 	}
 
-	if until.After(now) {
-		// TODO: do we want to return early when we know the rate limit bigger than ctx deadline?
-		if deadline, ok := ctx.Deadline(); ok && until.After(deadline) {
-			return context.DeadlineExceeded
+	// interaction & webhook endpoints are not bound to the global rate limit of the bot
+	useBudget := l.budget != nil && endpoint.Endpoint.BotAuth
+	if useBudget || l.global.After(time.Now()) {
+		if err := l.globalMu.Lock(ctx, priority); err != nil {
+			b.mu.Unlock()
+			return err
 		}
-
-		select {
-		case <-ctx.Done():
+		err := waitUntil(ctx, l.global)
+		if err == nil && useBudget {
+			err = waitUntil(ctx, l.budget.take(time.Now()))
+		}
+		l.globalMu.Unlock()
+		if err != nil {
 			b.mu.Unlock()
-			return ctx.Err()
-		case <-time.After(until.Sub(now)):
+			return err
 		}
 	}
 	return nil
 }
 
+// waitUntil waits until the given time or returns early if the context is done or its deadline is before the given time.
+func waitUntil(ctx context.Context, until time.Time) error {
+	now := time.Now()
+	if !until.After(now) {
+		return nil
+	}
+	if deadline, ok := ctx.Deadline(); ok && until.After(deadline) {
+		return context.DeadlineExceeded
+	}
+
+	timer := time.NewTimer(until.Sub(now))
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
 func (l *rateLimiterImpl) UnlockBucket(endpoint *CompiledEndpoint, rs *http.Response) error {
 	b := l.getBucket(endpoint, false)
 	if b == nil {
@@ -209,6 +265,10 @@ func (l *rateLimiterImpl) UnlockBucket(endpoint *CompiledEndpoint, rs *http.Resp
 	if rs == nil || rs.Header == nil {
 		return nil
 	}
+
+	if l.invalidRequests != nil && isInvalidRequest(rs) && l.invalidRequests.add(time.Now()) {
+		l.config.Logger.Error("too many invalid requests, pausing requests to avoid a cloudflare ban", slog.Int("max_invalid_requests", l.config.MaxInvalidRequests), slog.Duration("interval", l.config.InvalidRequestsInterval))
+	}
 	bucketHeader := rs.Header.Get("X-RateLimit-Bucket")
 
 	// if we don't have a bucket header, we can't update anything
@@ -288,7 +348,7 @@ func (l *rateLimiterImpl) UnlockBucket(endpoint *CompiledEndpoint, rs *http.Resp
 }
 
 type bucket struct {
-	mu        csync.Mutex
+	mu        priorityMutex
 	ID        string
 	Reset     time.Time
 	Remaining int
@@ -0,0 +1,112 @@
+package rest
+
+import (
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// ErrTooManyInvalidRequests is returned by the RateLimiter once too many invalid requests were made, to avoid being banned by Cloudflare.
+// See https://discord.com/developers/docs/topics/rate-limits#invalid-request-limit-aka-cloudflare-bans
+var ErrTooManyInvalidRequests = errors.New("too many invalid requests, requests are paused to avoid a cloudflare ban")
+
+// globalBudget reserves send times for requests, so no more than limit requests are sent within any second.
+// It is a sliding window over the send times of the last limit requests.
+type globalBudget struct {
+	mu    sync.Mutex
+	limit int
+	// sent is a ring of the send times of the last limit requests, next is the index of the oldest one
+	sent []time.Time
+	next int
+}
+
+// take reserves a slot for one request in the budget and returns the time the request can be sent at.
+// A request is sent at least a second after the request limit requests before it.
+func (b *globalBudget) take(now time.Time) time.Time {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.sent == nil {
+		b.sent = make([]time.Time, max(b.limit, 1))
+	}
+	at := now
+	// keep the send times in order, even if callers pass slightly older times
+	if last := b.sent[(b.next+len(b.sent)-1)%len(b.sent)]; at.Before(last) {
+		at = last
+	}
+	if oldest := b.sent[b.next]; !oldest.IsZero() && at.Before(oldest.Add(time.Second)) {
+		at = oldest.Add(time.Second)
+	}
+	b.sent[b.next] = at
+	b.next = (b.next + 1) % len(b.sent)
+	return at
+}
+
+// isInvalidRequest returns whether the response counts towards the invalid request limit of Discord.
+// 429s with the shared scope are excluded as they are not caused by us.
+func isInvalidRequest(rs *http.Response) bool {
+	switch rs.StatusCode {
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return true
+	case http.StatusTooManyRequests:
+		return rs.Header.Get("X-RateLimit-Scope") != "shared"
+	default:
+		return false
+	}
+}
+
+// invalidRequestCounter counts invalid requests in a sliding window of one second slots & trips once max is reached.
+type invalidRequestCounter struct {
+	mu    sync.Mutex
+	max   int
+	slots []int
+	// last is the unix second the window was last advanced to
+	last  int64
+	count int
+}
+
+func newInvalidRequestCounter(maxRequests int, window time.Duration) *invalidRequestCounter {
+	return &invalidRequestCounter{
+		max:   maxRequests,
+		slots: make([]int, max(int(window/time.Second), 1)),
+	}
+}
+
+// advance drops the slots which left the window until now. It must be called with mu held.
+func (c *invalidRequestCounter) advance(now time.Time) {
+	sec := now.Unix()
+	if sec <= c.last {
+		return
+	}
+	steps := sec - c.last
+	if steps > int64(len(c.slots)) {
+		steps = int64(len(c.slots))
+	}
+	for i := int64(1); i <= steps; i++ {
+		slot := int((c.last + i) % int64(len(c.slots)))
+		c.count -= c.slots[slot]
+		c.slots[slot] = 0
+	}
+	c.last = sec
+}
+
+// add counts an invalid request and returns whether this tripped the counter.
+func (c *invalidRequestCounter) add(now time.Time) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.advance(now)
+	c.slots[int(c.last%int64(len(c.slots)))]++
+	c.count++
+	return c.count == c.max
+}
+
+// tripped returns whether max invalid requests were made within the window.
+func (c *invalidRequestCounter) tripped(now time.Time) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.advance(now)
+	return c.count >= c.max
+}
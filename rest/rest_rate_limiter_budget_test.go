@@ -0,0 +1,62 @@
+package rest
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGlobalBudget(t *testing.T) {
+	b := &globalBudget{limit: 2}
+	now := time.Now()
+
+	assert.Equal(t, now, b.take(now))
+	assert.Equal(t, now.Add(100*time.Millisecond), b.take(now.Add(100*time.Millisecond)))
+	assert.Equal(t, now.Add(time.Second), b.take(now.Add(200*time.Millisecond)), "the third request should wait a second after the first one")
+	assert.Equal(t, now.Add(1100*time.Millisecond), b.take(now.Add(300*time.Millisecond)))
+	assert.Equal(t, now.Add(2*time.Second), b.take(now.Add(400*time.Millisecond)))
+
+	later := now.Add(5 * time.Second)
+	assert.Equal(t, later, b.take(later))
+}
+
+func TestGlobalBudgetWindowBoundary(t *testing.T) {
+	b := &globalBudget{limit: 2}
+	now := time.Now()
+
+	// a fixed window would allow two more requests right after the first window ended
+	b.take(now.Add(900 * time.Millisecond))
+	b.take(now.Add(950 * time.Millisecond))
+	assert.Equal(t, now.Add(1900*time.Millisecond), b.take(now.Add(time.Second)))
+	assert.Equal(t, now.Add(1950*time.Millisecond), b.take(now.Add(time.Second)))
+}
+
+func TestInvalidRequestCounter(t *testing.T) {
+	c := newInvalidRequestCounter(3, 10*time.Second)
+	now := time.Unix(1000, 0)
+
+	assert.False(t, c.add(now))
+	assert.False(t, c.add(now.Add(5*time.Second)))
+	assert.False(t, c.tripped(now.Add(5*time.Second)))
+	assert.True(t, c.add(now.Add(6*time.Second)))
+	assert.True(t, c.tripped(now.Add(9*time.Second)))
+
+	// the first request left the window
+	assert.False(t, c.tripped(now.Add(10*time.Second)))
+	// all requests left the window
+	assert.False(t, c.tripped(now.Add(time.Hour)))
+	assert.Equal(t, 0, c.count)
+}
+
+func TestIsInvalidRequest(t *testing.T) {
+	shared := http.Header{}
+	shared.Set("X-RateLimit-Scope", "shared")
+
+	assert.True(t, isInvalidRequest(&http.Response{StatusCode: http.StatusUnauthorized}))
+	assert.True(t, isInvalidRequest(&http.Response{StatusCode: http.StatusForbidden}))
+	assert.True(t, isInvalidRequest(&http.Response{StatusCode: http.StatusTooManyRequests, Header: http.Header{}}))
+	assert.False(t, isInvalidRequest(&http.Response{StatusCode: http.StatusTooManyRequests, Header: shared}))
+	assert.False(t, isInvalidRequest(&http.Response{StatusCode: http.StatusNotFound}))
+}
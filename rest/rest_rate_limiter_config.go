@@ -8,9 +8,13 @@ import (
 // DefaultRateLimiterConfig is the configuration which is used by default.
 func DefaultRateLimiterConfig() *RateLimiterConfig {
 	return &RateLimiterConfig{
-		Logger:          slog.Default(),
-		MaxRetries:      MaxRetries,
-		CleanupInterval: CleanupInterval,
+		Logger:                  slog.Default(),
+		MaxRetries:              MaxRetries,
+		CleanupInterval:         CleanupInterval,
+		MaxPrioritySkips:        MaxPrioritySkips,
+		GlobalRateLimit:         GlobalRateLimit,
+		MaxInvalidRequests:      MaxInvalidRequests,
+		InvalidRequestsInterval: InvalidRequestsInterval,
 	}
 }
 
@@ -19,6 +23,14 @@ type RateLimiterConfig struct {
 	Logger          *slog.Logger
 	MaxRetries      int
 	CleanupInterval time.Duration
+	// MaxPrioritySkips is how often a waiting request can be skipped in favor of requests with a higher Priority before it is served next.
+	MaxPrioritySkips int
+	// GlobalRateLimit is the number of requests per second sent with the bot token. Zero disables the proactive global rate limit.
+	GlobalRateLimit int
+	// MaxInvalidRequests is the number of 401, 403 & 429 responses within InvalidRequestsInterval after which requests fail with ErrTooManyInvalidRequests.
+	// Zero disables this circuit breaker.
+	MaxInvalidRequests      int
+	InvalidRequestsInterval time.Duration
 }
 
 // RateLimiterConfigOpt can be used to supply optional parameters to NewRateLimiter.
@@ -51,3 +63,28 @@ func WithCleanupInterval(cleanupInterval time.Duration) RateLimiterConfigOpt {
 		config.CleanupInterval = cleanupInterval
 	}
 }
+
+// WithMaxPrioritySkips sets how often a waiting request can be skipped in favor of requests with a higher Priority before it is served next.
+// Zero disables this fairness guarantee, so requests with a lower Priority can be starved.
+func WithMaxPrioritySkips(maxPrioritySkips int) RateLimiterConfigOpt {
+	return func(config *RateLimiterConfig) {
+		config.MaxPrioritySkips = maxPrioritySkips
+	}
+}
+
+// WithGlobalRateLimit sets the number of requests per second sent with the bot token. Bots with a raised global rate limit should set it accordingly.
+// Zero disables the proactive global rate limit, so only 429 responses are respected.
+func WithGlobalRateLimit(requestsPerSecond int) RateLimiterConfigOpt {
+	return func(config *RateLimiterConfig) {
+		config.GlobalRateLimit = requestsPerSecond
+	}
+}
+
+// WithMaxInvalidRequests sets the number of 401, 403 & 429 responses within the given interval after which requests fail with ErrTooManyInvalidRequests
+// until enough invalid requests left the interval. Zero disables this circuit breaker.
+func WithMaxInvalidRequests(maxInvalidRequests int, interval time.Duration) RateLimiterConfigOpt {
+	return func(config *RateLimiterConfig) {
+		config.MaxInvalidRequests = maxInvalidRequests
+		config.InvalidRequestsInterval = interval
+	}
+}
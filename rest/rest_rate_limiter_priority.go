@@ -0,0 +1,159 @@
+package rest
+
+import (
+	"context"
+	"sync"
+)
+
+// Priority is the priority of a request in the RateLimiter. Requests waiting for the same bucket are served in priority order.
+// Use WithPriority to set it.
+type Priority int
+
+const (
+	// PriorityBackground is for bulk work like backfills which should not delay other requests.
+	PriorityBackground Priority = iota - 1
+	// PriorityNormal is the default Priority of a request.
+	PriorityNormal
+	// PriorityInteractive is for requests a user is waiting for like interaction follow-ups.
+	PriorityInteractive
+)
+
+// priorityCount is the number of different Priority values.
+const priorityCount = int(PriorityInteractive-PriorityBackground) + 1
+
+func (p Priority) String() string {
+	switch p {
+	case PriorityBackground:
+		return "background"
+	case PriorityNormal:
+		return "normal"
+	case PriorityInteractive:
+		return "interactive"
+	default:
+		return "unknown"
+	}
+}
+
+// index returns the index of the Priority in a priority ordered array, unknown priorities are clamped.
+func (p Priority) index() int {
+	return int(min(max(p, PriorityBackground), PriorityInteractive) - PriorityBackground)
+}
+
+type priorityKey struct{}
+
+// ContextWithPriority returns a copy of the context with the given Priority. The Client passes the Priority of WithPriority this way to the RateLimiter.
+func ContextWithPriority(ctx context.Context, priority Priority) context.Context {
+	return context.WithValue(ctx, priorityKey{}, priority)
+}
+
+// PriorityFromContext returns the Priority of the context or PriorityNormal if none is set.
+func PriorityFromContext(ctx context.Context) Priority {
+	if priority, ok := ctx.Value(priorityKey{}).(Priority); ok {
+		return priority
+	}
+	return PriorityNormal
+}
+
+// priorityMutex is a mutex which hands the lock to waiters in Priority order.
+// A waiter which was skipped maxSkips times in favor of higher priorities is served next, so lower priorities are never starved.
+type priorityMutex struct {
+	mu       sync.Mutex
+	locked   bool
+	maxSkips int
+	waiters  [priorityCount][]chan struct{}
+	skips    [priorityCount]int
+}
+
+// Lock locks the mutex or waits until it is handed to the caller or the context is done.
+func (m *priorityMutex) Lock(ctx context.Context, priority Priority) error {
+	m.mu.Lock()
+	if !m.locked {
+		m.locked = true
+		m.mu.Unlock()
+		return nil
+	}
+	i := priority.index()
+	ready := make(chan struct{})
+	m.waiters[i] = append(m.waiters[i], ready)
+	m.mu.Unlock()
+
+	select {
+	case <-ready:
+		return nil
+	case <-ctx.Done():
+		m.mu.Lock()
+		select {
+		case <-ready:
+			// the lock was handed to us in the meantime, pass it on
+			m.unlock()
+		default:
+			m.removeWaiter(i, ready)
+		}
+		m.mu.Unlock()
+		return ctx.Err()
+	}
+}
+
+// TryLock locks the mutex if it is not locked and returns whether it did.
+func (m *priorityMutex) TryLock() bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.locked {
+		return false
+	}
+	m.locked = true
+	return true
+}
+
+// Unlock hands the mutex to the next waiter or unlocks it if there is none.
+func (m *priorityMutex) Unlock() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.unlock()
+}
+
+func (m *priorityMutex) unlock() {
+	next := -1
+	// starved waiters are served first, lowest priority first
+	for i := 0; i < priorityCount; i++ {
+		if len(m.waiters[i]) > 0 && m.maxSkips > 0 && m.skips[i] >= m.maxSkips {
+			next = i
+			break
+		}
+	}
+	if next == -1 {
+		for i := priorityCount - 1; i >= 0; i-- {
+			if len(m.waiters[i]) > 0 {
+				next = i
+				break
+			}
+		}
+	}
+	if next == -1 {
+		m.locked = false
+		return
+	}
+
+	for i := 0; i < priorityCount; i++ {
+		if i == next {
+			m.skips[i] = 0
+		} else if len(m.waiters[i]) > 0 {
+			m.skips[i]++
+		}
+	}
+	ready := m.waiters[next][0]
+	m.waiters[next] = m.waiters[next][1:]
+	close(ready)
+}
+
+func (m *priorityMutex) removeWaiter(i int, ready chan struct{}) {
+	for j, waiter := range m.waiters[i] {
+		if waiter == ready {
+			m.waiters[i] = append(m.waiters[i][:j], m.waiters[i][j+1:]...)
+			break
+		}
+	}
+	if len(m.waiters[i]) == 0 {
+		m.skips[i] = 0
+	}
+}
@@ -0,0 +1,61 @@
+package rest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// lockInOrder queues a waiter for each priority & returns the order in which they got the lock.
+func lockInOrder(t *testing.T, m *priorityMutex, priorities []Priority) []Priority {
+	assert.NoError(t, m.Lock(context.Background(), PriorityNormal))
+
+	order := make(chan Priority, len(priorities))
+	for _, priority := range priorities {
+		priority := priority
+		go func() {
+			assert.NoError(t, m.Lock(context.Background(), priority))
+			order <- priority
+			m.Unlock()
+		}()
+		// make sure the waiters are queued in order
+		assert.Eventually(t, func() bool {
+			m.mu.Lock()
+			defer m.mu.Unlock()
+			return len(m.waiters[priority.index()]) > 0
+		}, time.Second, time.Millisecond)
+	}
+	m.Unlock()
+
+	var got []Priority
+	for range priorities {
+		got = append(got, <-order)
+	}
+	return got
+}
+
+func TestPriorityMutexOrder(t *testing.T) {
+	m := &priorityMutex{maxSkips: 8}
+	got := lockInOrder(t, m, []Priority{PriorityBackground, PriorityNormal, PriorityInteractive})
+	assert.Equal(t, []Priority{PriorityInteractive, PriorityNormal, PriorityBackground}, got)
+}
+
+func TestPriorityMutexFairness(t *testing.T) {
+	m := &priorityMutex{maxSkips: 2}
+	got := lockInOrder(t, m, []Priority{PriorityBackground, PriorityInteractive, PriorityInteractive, PriorityInteractive, PriorityInteractive})
+	assert.Equal(t, []Priority{PriorityInteractive, PriorityInteractive, PriorityBackground, PriorityInteractive, PriorityInteractive}, got)
+}
+
+func TestPriorityMutexCancel(t *testing.T) {
+	m := &priorityMutex{}
+	assert.NoError(t, m.Lock(context.Background(), PriorityNormal))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	assert.ErrorIs(t, m.Lock(ctx, PriorityInteractive), context.DeadlineExceeded)
+
+	m.Unlock()
+	assert.True(t, m.TryLock())
+}
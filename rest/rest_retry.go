@@ -0,0 +1,93 @@
+package rest
+
+import (
+	"math/rand"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// DefaultRetryConfig returns the RetryConfig which is used by default.
+func DefaultRetryConfig() RetryConfig {
+	return RetryConfig{
+		MaxRetries:  3,
+		MinBackoff:  500 * time.Millisecond,
+		MaxBackoff:  10 * time.Second,
+		Jitter:      0.5,
+		ShouldRetry: DefaultShouldRetry,
+	}
+}
+
+// RetryConfig configures how the Client retries requests which failed because of transient errors like network errors or 502, 503 & 504 responses.
+// Only idempotent requests are retried, which are all GET, HEAD, PUT, DELETE & OPTIONS requests & requests marked with WithIdempotent.
+// Rate limited requests are retried independently of this as configured in the RateLimiter.
+type RetryConfig struct {
+	// MaxRetries is the maximum number of retries of a request. Zero disables retries.
+	MaxRetries int
+	// MinBackoff is the backoff before the first retry. It is doubled for each further retry.
+	MinBackoff time.Duration
+	// MaxBackoff is the maximum backoff between two retries.
+	MaxBackoff time.Duration
+	// Jitter is the fraction of the backoff which is randomized between 0 and 1, so clients don't retry at the same time.
+	Jitter float64
+	// ShouldRetry decides whether a request is retried from its response or error. Defaults to DefaultShouldRetry.
+	ShouldRetry func(rs *http.Response, err error) bool
+	// OnRetry is called before waiting for each retry. It can be used for logging or metrics.
+	OnRetry func(event RetryEvent)
+}
+
+// RetryEvent is passed to RetryConfig.OnRetry before a request is retried.
+type RetryEvent struct {
+	// Endpoint is the endpoint of the request.
+	Endpoint *CompiledEndpoint
+	// Response is the response of the failed request. It is nil if the request failed with an error.
+	Response *http.Response
+	// Err is the error of the failed request. It is nil if the request failed with a Response.
+	Err error
+	// Retry is the number of this retry, starting at 1.
+	Retry int
+	// Backoff is the time waited before the retry.
+	Backoff time.Duration
+}
+
+// DefaultShouldRetry retries network errors, 502, 503 & 504 responses & HTML error pages returned by Cloudflare.
+// Requests are never retried once their context is done.
+func DefaultShouldRetry(rs *http.Response, err error) bool {
+	if err != nil {
+		return true
+	}
+	switch rs.StatusCode {
+	case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	// Cloudflare returns HTML error pages for issues between it & Discord, e.g. with 520-530 status codes
+	return rs.StatusCode >= http.StatusInternalServerError && strings.HasPrefix(rs.Header.Get("Content-Type"), "text/html")
+}
+
+// isIdempotent returns whether the request can be retried without side effects.
+func isIdempotent(method string, config *RequestConfig) bool {
+	if config.Idempotent {
+		return true
+	}
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodPut, http.MethodDelete, http.MethodOptions:
+		return true
+	default:
+		return false
+	}
+}
+
+// backoff returns the jittered backoff before the given retry.
+func (c RetryConfig) backoff(retry int) time.Duration {
+	backoff := c.MinBackoff
+	for i := 1; i < retry && backoff < c.MaxBackoff; i++ {
+		backoff *= 2
+	}
+	if c.MaxBackoff > 0 && backoff > c.MaxBackoff {
+		backoff = c.MaxBackoff
+	}
+	if c.Jitter > 0 {
+		backoff -= time.Duration(c.Jitter * rand.Float64() * float64(backoff))
+	}
+	return backoff
+}
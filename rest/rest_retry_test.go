@@ -0,0 +1,81 @@
+package rest
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/disgoorg/disgo/discord"
+)
+
+// newRetryTestClient returns a Client for a server which fails the first failures requests with the given status code.
+func newRetryTestClient(t *testing.T, failures int32, statusCode int, contentType string) (Client, *atomic.Int32, *[]RetryEvent) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if requests.Add(1) <= failures {
+			w.Header().Set("Content-Type", contentType)
+			w.WriteHeader(statusCode)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id":"1","content":"test"}`))
+	}))
+	t.Cleanup(server.Close)
+
+	var events []RetryEvent
+	client := NewClient("token",
+		WithURL(server.URL),
+		WithRetries(2, time.Millisecond, 10*time.Millisecond),
+		WithRetryHook(func(event RetryEvent) {
+			events = append(events, event)
+		}),
+	)
+	return client, &requests, &events
+}
+
+func TestClientRetry(t *testing.T) {
+	client, requests, events := newRetryTestClient(t, 2, http.StatusServiceUnavailable, "application/json")
+
+	message, err := NewChannels(client).GetMessage(1, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, "test", message.Content)
+	assert.Equal(t, int32(3), requests.Load())
+	if assert.Len(t, *events, 2) {
+		assert.Equal(t, 1, (*events)[0].Retry)
+		assert.Equal(t, http.StatusServiceUnavailable, (*events)[0].Response.StatusCode)
+		assert.Equal(t, 2, (*events)[1].Retry)
+	}
+}
+
+func TestClientRetryMaxRetries(t *testing.T) {
+	client, requests, _ := newRetryTestClient(t, 3, 520, "text/html")
+
+	_, err := NewChannels(client).GetMessage(1, 1)
+	assert.Error(t, err)
+	assert.Equal(t, int32(3), requests.Load())
+}
+
+func TestClientRetryIdempotency(t *testing.T) {
+	client, requests, _ := newRetryTestClient(t, 1, http.StatusBadGateway, "application/json")
+
+	_, err := NewChannels(client).CreateMessage(1, discord.MessageCreate{Content: "test"})
+	assert.Error(t, err, "POST requests should not be retried")
+	assert.Equal(t, int32(1), requests.Load())
+
+	requests.Store(0)
+	_, err = NewChannels(client).CreateMessage(1, discord.MessageCreate{Content: "test", Nonce: "1", EnforceNonce: true})
+	assert.NoError(t, err, "POST requests with an enforced nonce should be retried")
+	assert.Equal(t, int32(2), requests.Load())
+}
+
+func TestClientRetryNotTransient(t *testing.T) {
+	client, requests, _ := newRetryTestClient(t, 1, http.StatusInternalServerError, "application/json")
+
+	_, err := NewChannels(client).GetMessage(1, 1)
+	assert.Error(t, err)
+	assert.Equal(t, int32(1), requests.Load())
+}
@@ -0,0 +1,254 @@
+// Package restproxy provides a http.Handler which forwards Discord REST API requests through a shared rest.Client & rest.RateLimiter.
+// This allows multiple services to share one bot token and one rate limit view.
+package restproxy
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/disgoorg/disgo/discord"
+	"github.com/disgoorg/disgo/rest"
+)
+
+// hopHeaders are headers which are only meant for a single connection and are not forwarded.
+var hopHeaders = []string{
+	"Connection",
+	"Keep-Alive",
+	"Proxy-Authenticate",
+	"Proxy-Authorization",
+	"Proxy-Connection",
+	"Te",
+	"Trailer",
+	"Transfer-Encoding",
+	"Upgrade",
+}
+
+// forwardHeaders are the headers of incoming requests which are forwarded to Discord.
+// All other headers are dropped, as they may contain credentials for the Proxy itself.
+var forwardHeaders = []string{
+	"Accept",
+	"Content-Type",
+	"User-Agent",
+	"X-Audit-Log-Reason",
+}
+
+var majorParameters = strings.Split(rest.MajorParameters, ":")
+
+// New returns a new http.Handler which forwards all requests to Discord using the given token and the http.Client & rest.RateLimiter of the given rest.Client.
+// Requests using a Bearer token keep it, all other requests to routes which require bot authorization are authorized with the bot token.
+// Only the headers Discord needs are forwarded, see forwardHeaders.
+// All requests are denied unless an Authorizer is set with WithAuthorizer, as the Proxy adds the bot token to them.
+func New(token string, client rest.Client, opts ...ConfigOpt) http.Handler {
+	config := DefaultConfig()
+	config.Apply(opts)
+	config.Logger = config.Logger.With(slog.String("name", "rest_proxy"))
+	if config.Authorizer == nil {
+		config.Logger.Warn("no authorizer set, all requests are denied")
+	}
+
+	return &proxyImpl{
+		token:     token,
+		client:    client,
+		config:    *config,
+		matcher:   newRouteMatcher(rest.Endpoints()),
+		endpoints: map[string]*rest.Endpoint{},
+	}
+}
+
+type proxyImpl struct {
+	token  string
+	client rest.Client
+	config Config
+
+	matcher *routeMatcher
+	// endpoints of unknown routes are reused as rate limiters may key on the *rest.Endpoint
+	endpoints   map[string]*rest.Endpoint
+	endpointsMu sync.Mutex
+}
+
+func (p *proxyImpl) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if p.config.Authorizer == nil || !p.config.Authorizer(r) {
+		http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+		return
+	}
+
+	path, ok := strings.CutPrefix(r.URL.Path, p.config.PathPrefix)
+	if !ok || !strings.HasPrefix(path, "/") {
+		http.NotFound(w, r)
+		return
+	}
+
+	rqBody, err := io.ReadAll(r.Body)
+	if err != nil {
+		p.config.Logger.Error("failed to read request body", slog.Any("err", err))
+		http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+		return
+	}
+
+	endpoint := p.compile(r.Method, path, r.URL.RawQuery)
+	rs, rsBody, err := p.do(r, endpoint, rqBody)
+	if err != nil {
+		status := http.StatusBadGateway
+		if errors.Is(err, context.DeadlineExceeded) || errors.Is(err, context.Canceled) {
+			status = http.StatusGatewayTimeout
+		}
+		p.config.Logger.Error("failed to forward request", slog.Any("err", err), slog.String("endpoint", endpoint.URL))
+		http.Error(w, http.StatusText(status), status)
+		return
+	}
+
+	// pass all headers including the rate limit ones back to the caller
+	for key, values := range rs.Header {
+		w.Header()[key] = values
+	}
+	removeHopHeaders(w.Header())
+	w.WriteHeader(rs.StatusCode)
+	if _, err = w.Write(rsBody); err != nil {
+		p.config.Logger.Debug("failed to write response body", slog.Any("err", err))
+	}
+}
+
+func (p *proxyImpl) do(r *http.Request, endpoint *rest.CompiledEndpoint, rqBody []byte) (*http.Response, []byte, error) {
+	for tries := 1; ; tries++ {
+		if err := p.client.RateLimiter().WaitBucket(r.Context(), endpoint); err != nil {
+			return nil, nil, err
+		}
+
+		rq, err := http.NewRequestWithContext(r.Context(), r.Method, p.config.URL+endpoint.URL, bytes.NewReader(rqBody))
+		if err != nil {
+			_ = p.client.RateLimiter().UnlockBucket(endpoint, nil)
+			return nil, nil, err
+		}
+		for _, h := range forwardHeaders {
+			if values := r.Header.Values(h); len(values) > 0 {
+				rq.Header[http.CanonicalHeaderKey(h)] = values
+			}
+		}
+		if authorization := r.Header.Get("Authorization"); strings.HasPrefix(authorization, discord.TokenTypeBearer.Apply("")) {
+			rq.Header.Set("Authorization", authorization)
+		} else if endpoint.Endpoint.BotAuth {
+			rq.Header.Set("Authorization", discord.TokenTypeBot.Apply(p.token))
+		}
+
+		rs, err := p.client.HTTPClient().Do(rq)
+		if err != nil {
+			_ = p.client.RateLimiter().UnlockBucket(endpoint, nil)
+			return nil, nil, err
+		}
+
+		if err = p.client.RateLimiter().UnlockBucket(endpoint, rs); err != nil {
+			p.config.Logger.Error("failed to unlock rest bucket", slog.Any("err", err), slog.String("endpoint", endpoint.URL))
+		}
+
+		rsBody, err := io.ReadAll(rs.Body)
+		_ = rs.Body.Close()
+		if err != nil {
+			return nil, nil, err
+		}
+		p.config.Logger.Debug("forwarded request", slog.String("method", r.Method), slog.String("endpoint", endpoint.URL), slog.String("code", rs.Status))
+
+		if rs.StatusCode == http.StatusTooManyRequests && tries < p.config.MaxRetries {
+			continue
+		}
+		return rs, rsBody, nil
+	}
+}
+
+// compile turns the given path into a rest.CompiledEndpoint.
+// Known paths are compiled with the rest.Endpoint of the rest package, so they share their buckets with rest.Client(s).
+// For unknown paths IDs & tokens are replaced with placeholders to get a route and the major parameters are extracted like rest.Endpoint.Compile does.
+func (p *proxyImpl) compile(method string, path string, rawQuery string) *rest.CompiledEndpoint {
+	var query string
+	if rawQuery != "" {
+		query = "?" + rawQuery
+	}
+
+	if endpoint, params, ok := p.matcher.match(method, path); ok {
+		compiled := endpoint.Compile(nil, params...)
+		compiled.URL += query
+		return compiled
+	}
+
+	route, majorParams := parseRoute(path)
+
+	key := method + "+" + route
+	p.endpointsMu.Lock()
+	endpoint, ok := p.endpoints[key]
+	if !ok {
+		// not created with rest.NewEndpoint or rest.NewNoBotAuthEndpoint to keep them out of rest.Endpoints
+		endpoint = &rest.Endpoint{
+			Method: method,
+			Route:  route,
+			// webhook & interaction tokens authorize the request themselves
+			BotAuth: !strings.Contains(route, ".token}"),
+		}
+		p.endpoints[key] = endpoint
+	}
+	p.endpointsMu.Unlock()
+
+	return &rest.CompiledEndpoint{
+		Endpoint:    endpoint,
+		URL:         path + query,
+		MajorParams: majorParams,
+	}
+}
+
+// parseRoute replaces IDs & tokens in the given path with placeholders named after the preceding path segment.
+// e.g. /channels/123/messages/456 becomes /channels/{channel.id}/messages/{message.id}
+func parseRoute(path string) (string, string) {
+	segments := strings.Split(path, "/")
+
+	var majorParams []string
+	for i := 1; i < len(segments); i++ {
+		prev := segments[i-1]
+
+		var paramName string
+		switch {
+		case prev == "reactions" && segments[i] != "":
+			paramName = "emoji"
+		case i > 1 && (segments[i-2] == "interactions" && segments[i] != "" && !isID(segments[i]) || segments[i-2] == "webhooks" && isInteractionToken(segments[i])):
+			paramName = "interaction.token"
+		case i > 1 && segments[i-2] == "webhooks" && segments[i] != "" && !isID(segments[i]):
+			paramName = "webhook.token"
+		case prev == "webhooks" && i+1 < len(segments) && isInteractionToken(segments[i+1]) && isID(segments[i]):
+			paramName = "application.id"
+		case isID(segments[i]) && strings.HasPrefix(prev, "{"):
+			paramName = "id"
+		case isID(segments[i]):
+			paramName = strings.TrimSuffix(prev, "s") + ".id"
+		default:
+			continue
+		}
+
+		if slices.Contains(majorParameters, paramName) {
+			majorParams = append(majorParams, paramName+"="+segments[i])
+		}
+		segments[i] = "{" + paramName + "}"
+	}
+	return strings.Join(segments, "/"), strings.Join(majorParams, ":")
+}
+
+func isID(segment string) bool {
+	if segment == "" {
+		return false
+	}
+	for _, c := range segment {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+func removeHopHeaders(header http.Header) {
+	for _, h := range hopHeaders {
+		header.Del(h)
+	}
+}
@@ -0,0 +1,88 @@
+package restproxy
+
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+
+	"github.com/disgoorg/disgo/rest"
+)
+
+// DefaultConfig returns a Config with sensible defaults.
+func DefaultConfig() *Config {
+	return &Config{
+		Logger:     slog.Default(),
+		URL:        fmt.Sprintf("%sv%d", rest.API, rest.Version),
+		PathPrefix: fmt.Sprintf("/api/v%d", rest.Version),
+		MaxRetries: rest.MaxRetries,
+	}
+}
+
+// Config lets you configure your Proxy instance.
+type Config struct {
+	// Logger is the logger of the Proxy. Defaults to slog.Default().
+	Logger *slog.Logger
+	// URL is the Discord API url requests are forwarded to. Defaults to the Discord API url of the current rest.Version.
+	URL string
+	// PathPrefix is stripped from the path of incoming requests before forwarding them. Defaults to "/api/v10".
+	PathPrefix string
+	// MaxRetries is the maximum number of retries the Proxy does on a 429 response. Defaults to rest.MaxRetries.
+	MaxRetries int
+	// Authorizer decides whether an incoming request is allowed to use the Proxy. Defaults to nil which denies all requests.
+	Authorizer Authorizer
+}
+
+// Authorizer is used to check whether an incoming request is allowed to use the Proxy.
+type Authorizer func(r *http.Request) bool
+
+// AllowAll is an Authorizer which allows all requests.
+func AllowAll(_ *http.Request) bool {
+	return true
+}
+
+// ConfigOpt can be used to supply optional parameters to New.
+type ConfigOpt func(config *Config)
+
+// Apply applies the given ConfigOpt(s) to the Config.
+func (c *Config) Apply(opts []ConfigOpt) {
+	for _, opt := range opts {
+		opt(c)
+	}
+}
+
+// WithLogger applies a custom logger to the Proxy.
+func WithLogger(logger *slog.Logger) ConfigOpt {
+	return func(config *Config) {
+		config.Logger = logger
+	}
+}
+
+// WithURL sets the Discord API url requests are forwarded to.
+func WithURL(url string) ConfigOpt {
+	return func(config *Config) {
+		config.URL = url
+	}
+}
+
+// WithPathPrefix sets the prefix which is stripped from the path of incoming requests.
+func WithPathPrefix(pathPrefix string) ConfigOpt {
+	return func(config *Config) {
+		config.PathPrefix = pathPrefix
+	}
+}
+
+// WithMaxRetries sets the maximum number of retries the Proxy does on a 429 response.
+func WithMaxRetries(maxRetries int) ConfigOpt {
+	return func(config *Config) {
+		config.MaxRetries = maxRetries
+	}
+}
+
+// WithAuthorizer sets the Authorizer which decides whether an incoming request is allowed to use the Proxy.
+// As the Proxy adds the bot token to all requests, it denies all requests without one.
+// Use AllowAll only if the Proxy is not reachable by untrusted clients.
+func WithAuthorizer(authorizer Authorizer) ConfigOpt {
+	return func(config *Config) {
+		config.Authorizer = authorizer
+	}
+}
@@ -0,0 +1,112 @@
+package restproxy
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/disgoorg/disgo/rest"
+)
+
+// interactionTokenPrefix is the base64 encoded "interaction:" prefix of interaction tokens.
+// It tells interaction follow-ups & webhook executions apart, as both use /webhooks/{id}/{token}.
+const interactionTokenPrefix = "aW50ZXJhY3Rpb246"
+
+// routeMatcher matches request paths to the rest.Endpoint(s) of the rest package, so the Proxy uses the same routes & buckets as rest.Client(s).
+type routeMatcher struct {
+	// routes are grouped by method & number of path segments
+	routes map[string][]matcherRoute
+}
+
+type matcherRoute struct {
+	endpoint *rest.Endpoint
+	segments []string
+	// literals is the number of segments which are no parameters. Routes with more literals are preferred.
+	literals int
+}
+
+func newRouteMatcher(endpoints []*rest.Endpoint) *routeMatcher {
+	m := &routeMatcher{routes: map[string][]matcherRoute{}}
+	for _, endpoint := range endpoints {
+		segments := strings.Split(endpoint.Route, "/")
+		var literals int
+		for _, segment := range segments {
+			if !isParam(segment) {
+				literals++
+			}
+		}
+		key := routeKey(endpoint.Method, len(segments))
+		m.routes[key] = append(m.routes[key], matcherRoute{
+			endpoint: endpoint,
+			segments: segments,
+			literals: literals,
+		})
+	}
+	return m
+}
+
+// match returns the rest.Endpoint matching the method & path and the values of its parameters.
+func (m *routeMatcher) match(method string, path string) (*rest.Endpoint, []any, bool) {
+	segments := strings.Split(path, "/")
+
+	routes := m.routes[routeKey(method, len(segments))]
+	var best *matcherRoute
+	for i := range routes {
+		if (best == nil || routes[i].literals > best.literals) && routes[i].matches(segments) {
+			best = &routes[i]
+		}
+	}
+	if best == nil {
+		return nil, nil, false
+	}
+
+	var params []any
+	for i, segment := range best.segments {
+		if isParam(segment) {
+			params = append(params, segments[i])
+		}
+	}
+	return best.endpoint, params, true
+}
+
+func (r matcherRoute) matches(segments []string) bool {
+	for i, segment := range r.segments {
+		if !isParam(segment) {
+			if segment != segments[i] {
+				return false
+			}
+			continue
+		}
+		if !matchesParam(segment[1:len(segment)-1], segments[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// matchesParam returns whether the path segment is a valid value for the parameter.
+func matchesParam(name string, value string) bool {
+	switch {
+	case value == "":
+		return false
+	case strings.HasSuffix(name, ".id"):
+		return isID(value)
+	case name == "interaction.token":
+		return isInteractionToken(value)
+	case name == "webhook.token":
+		return !isInteractionToken(value)
+	default:
+		return true
+	}
+}
+
+func routeKey(method string, segments int) string {
+	return method + "+" + strconv.Itoa(segments)
+}
+
+func isParam(segment string) bool {
+	return strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "}")
+}
+
+func isInteractionToken(segment string) bool {
+	return strings.HasPrefix(segment, interactionTokenPrefix)
+}
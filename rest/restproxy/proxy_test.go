@@ -0,0 +1,207 @@
+package restproxy
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/disgoorg/disgo/rest"
+)
+
+func TestParseRoute(t *testing.T) {
+	tt := []struct {
+		Path        string
+		Route       string
+		MajorParams string
+	}{
+		{
+			Path:        "/channels/123/messages/456",
+			Route:       "/channels/{channel.id}/messages/{message.id}",
+			MajorParams: "channel.id=123",
+		},
+		{
+			Path:        "/channels/123/messages/456/reactions/%F0%9F%91%8D/@me",
+			Route:       "/channels/{channel.id}/messages/{message.id}/reactions/{emoji}/@me",
+			MajorParams: "channel.id=123",
+		},
+		{
+			Path:        "/webhooks/123/abc/messages/@original",
+			Route:       "/webhooks/{webhook.id}/{webhook.token}/messages/@original",
+			MajorParams: "webhook.id=123",
+		},
+		{
+			Path:        "/webhooks/123/aW50ZXJhY3Rpb246abc/messages/@original",
+			Route:       "/webhooks/{application.id}/{interaction.token}/messages/@original",
+			MajorParams: "interaction.token=aW50ZXJhY3Rpb246abc",
+		},
+		{
+			Path:        "/interactions/123/abc/callback",
+			Route:       "/interactions/{interaction.id}/{interaction.token}/callback",
+			MajorParams: "interaction.token=abc",
+		},
+		{
+			Path:        "/guilds/123/members/456",
+			Route:       "/guilds/{guild.id}/members/{member.id}",
+			MajorParams: "guild.id=123",
+		},
+		{
+			Path:  "/users/@me",
+			Route: "/users/@me",
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.Path, func(t *testing.T) {
+			route, majorParams := parseRoute(tc.Path)
+			assert.Equal(t, tc.Route, route)
+			assert.Equal(t, tc.MajorParams, majorParams)
+		})
+	}
+}
+
+func TestRouteMatcher(t *testing.T) {
+	matcher := newRouteMatcher(rest.Endpoints())
+
+	tt := []struct {
+		Method      string
+		Path        string
+		Endpoint    *rest.Endpoint
+		MajorParams string
+	}{
+		{
+			Method:      http.MethodGet,
+			Path:        "/channels/123/messages/456",
+			Endpoint:    rest.GetMessage,
+			MajorParams: "channel.id=123",
+		},
+		{
+			Method:      http.MethodPost,
+			Path:        "/channels/123/messages/bulk-delete",
+			Endpoint:    rest.BulkDeleteMessages,
+			MajorParams: "channel.id=123",
+		},
+		{
+			Method:      http.MethodPost,
+			Path:        "/webhooks/123/aW50ZXJhY3Rpb246abc",
+			Endpoint:    rest.CreateFollowupMessage,
+			MajorParams: "interaction.token=aW50ZXJhY3Rpb246abc",
+		},
+		{
+			Method:      http.MethodPost,
+			Path:        "/webhooks/123/abc",
+			Endpoint:    rest.CreateWebhookMessage,
+			MajorParams: "webhook.id=123",
+		},
+		{
+			Method:   http.MethodGet,
+			Path:     "/users/@me",
+			Endpoint: rest.GetCurrentUser,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.Path, func(t *testing.T) {
+			endpoint, params, ok := matcher.match(tc.Method, tc.Path)
+			if !assert.True(t, ok) {
+				return
+			}
+			assert.Equal(t, tc.Endpoint.Route, endpoint.Route)
+			compiled := endpoint.Compile(nil, params...)
+			assert.Equal(t, tc.Path, compiled.URL)
+			assert.Equal(t, tc.MajorParams, compiled.MajorParams)
+		})
+	}
+
+	_, _, ok := matcher.match(http.MethodGet, "/unknown/123")
+	assert.False(t, ok)
+}
+
+func TestProxy(t *testing.T) {
+	discordServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bot token", r.Header.Get("Authorization"))
+		assert.Equal(t, "reason", r.Header.Get("X-Audit-Log-Reason"))
+		assert.Empty(t, r.Header.Get("X-Proxy-Secret"))
+		assert.Equal(t, "/api/v10/channels/123/messages", r.URL.Path)
+		assert.Equal(t, "limit=1", r.URL.RawQuery)
+
+		body, _ := io.ReadAll(r.Body)
+		assert.Equal(t, `{"content":"hi"}`, string(body))
+
+		w.Header().Set("X-RateLimit-Bucket", "abcd")
+		w.Header().Set("X-RateLimit-Limit", "5")
+		w.Header().Set("X-RateLimit-Remaining", "4")
+		w.Header().Set("X-RateLimit-Reset-After", "1")
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{"id":"1"}`))
+	}))
+	defer discordServer.Close()
+
+	proxy := httptest.NewServer(New("token", rest.NewClient(""), WithURL(discordServer.URL+"/api/v10"), WithAuthorizer(func(r *http.Request) bool {
+		return r.Header.Get("X-Proxy-Secret") == "secret"
+	})))
+	defer proxy.Close()
+
+	rq, err := http.NewRequest(http.MethodPost, proxy.URL+"/api/v10/channels/123/messages?limit=1", strings.NewReader(`{"content":"hi"}`))
+	assert.NoError(t, err)
+	rq.Header.Set("Authorization", "Bot not-the-token")
+	rq.Header.Set("X-Audit-Log-Reason", "reason")
+	rq.Header.Set("X-Proxy-Secret", "secret")
+
+	rs, err := http.DefaultClient.Do(rq)
+	assert.NoError(t, err)
+	defer rs.Body.Close()
+
+	body, _ := io.ReadAll(rs.Body)
+	assert.Equal(t, http.StatusCreated, rs.StatusCode)
+	assert.Equal(t, `{"id":"1"}`, string(body))
+	assert.Equal(t, "abcd", rs.Header.Get("X-RateLimit-Bucket"))
+	assert.Equal(t, "4", rs.Header.Get("X-RateLimit-Remaining"))
+}
+
+func TestProxy_NoBotAuth(t *testing.T) {
+	discordServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Empty(t, r.Header.Get("Authorization"))
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer discordServer.Close()
+
+	proxy := httptest.NewServer(New("token", rest.NewClient(""), WithURL(discordServer.URL+"/api/v10"), WithAuthorizer(AllowAll)))
+	defer proxy.Close()
+
+	// a known & an unknown route authorized by their webhook token
+	for _, path := range []string{"/webhooks/123/token", "/webhooks/123/token/unknown"} {
+		rq, err := http.NewRequest(http.MethodPost, proxy.URL+"/api/v10"+path, strings.NewReader(`{"content":"hi"}`))
+		assert.NoError(t, err)
+
+		rs, err := http.DefaultClient.Do(rq)
+		assert.NoError(t, err)
+		_ = rs.Body.Close()
+		assert.Equal(t, http.StatusNoContent, rs.StatusCode)
+	}
+}
+
+func TestProxy_NoAuthorizer(t *testing.T) {
+	proxy := httptest.NewServer(New("token", rest.NewClient("")))
+	defer proxy.Close()
+
+	rs, err := http.Get(proxy.URL + "/api/v10/users/@me")
+	assert.NoError(t, err)
+	_ = rs.Body.Close()
+	assert.Equal(t, http.StatusUnauthorized, rs.StatusCode)
+}
+
+func TestProxy_Unauthorized(t *testing.T) {
+	proxy := httptest.NewServer(New("token", rest.NewClient(""), WithAuthorizer(func(r *http.Request) bool {
+		return r.Header.Get("X-Proxy-Secret") == "secret"
+	})))
+	defer proxy.Close()
+
+	rs, err := http.Get(proxy.URL + "/api/v10/users/@me")
+	assert.NoError(t, err)
+	_ = rs.Body.Close()
+	assert.Equal(t, http.StatusUnauthorized, rs.StatusCode)
+}
@@ -0,0 +1,299 @@
+// Package restredis provides a rest.RateLimiter which shares its buckets and the global rate limit between multiple processes via redis.
+package restredis
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/disgoorg/disgo/rest"
+)
+
+var _ rest.RateLimiter = (*rateLimiterImpl)(nil)
+
+// acquireScript atomically checks the global rate limit & takes a request from the bucket.
+// It returns 0 if the request may be sent or the number of milliseconds to wait before trying again,
+// followed by 1 if the bucket is reserved for a request whose response will tell us the limits.
+//
+// KEYS[1] is the bucket key, KEYS[2] is the global key.
+// ARGV[1] is the current unix time in milliseconds, ARGV[2] is the pending timeout in milliseconds.
+var acquireScript = redis.NewScript(`
+local now = tonumber(ARGV[1])
+local pendingTimeout = tonumber(ARGV[2])
+
+local global = tonumber(redis.call('GET', KEYS[2]))
+if global ~= nil and global > now then
+	return {global - now, 0}
+end
+
+local bucket = redis.call('HMGET', KEYS[1], 'limit', 'remaining', 'reset', 'pending')
+local limit = tonumber(bucket[1])
+local remaining = tonumber(bucket[2])
+local reset = tonumber(bucket[3])
+local pending = tonumber(bucket[4]) or 0
+
+if reset == nil or reset <= now then
+	-- unknown or expired bucket. reserve it until the response tells us the new limits
+	local nextRemaining = 0
+	if limit ~= nil and limit > 0 then
+		nextRemaining = limit - 1
+	end
+	redis.call('HSET', KEYS[1], 'remaining', nextRemaining, 'reset', now + pendingTimeout, 'pending', 1)
+	redis.call('PEXPIRE', KEYS[1], pendingTimeout)
+	return {0, 0}
+end
+
+if remaining ~= nil and remaining > 0 then
+	redis.call('HINCRBY', KEYS[1], 'remaining', -1)
+	return {0, 0}
+end
+
+return {reset - now, pending}
+`)
+
+// updateScript stores the limits of a bucket received from Discord.
+// The remaining count never increases within the same window as other processes may have requests in flight.
+//
+// KEYS[1] is the bucket key.
+// ARGV[1] is the limit, ARGV[2] the remaining count, ARGV[3] the reset as unix time in milliseconds, ARGV[4] the current unix time in milliseconds.
+var updateScript = redis.NewScript(`
+local limit = tonumber(ARGV[1])
+local remaining = tonumber(ARGV[2])
+local reset = tonumber(ARGV[3])
+local now = tonumber(ARGV[4])
+
+local bucket = redis.call('HMGET', KEYS[1], 'remaining', 'reset')
+local currentRemaining = tonumber(bucket[1])
+local currentReset = tonumber(bucket[2])
+if currentRemaining ~= nil and currentReset ~= nil and currentReset > now and currentRemaining < remaining then
+	remaining = currentRemaining
+end
+
+redis.call('HSET', KEYS[1], 'limit', limit, 'remaining', remaining, 'reset', reset)
+redis.call('HDEL', KEYS[1], 'pending')
+redis.call('PEXPIREAT', KEYS[1], reset + 1000)
+return remaining
+`)
+
+// releaseScript gives back a request to the bucket which was never sent.
+//
+// KEYS[1] is the bucket key.
+var releaseScript = redis.NewScript(`
+if redis.call('EXISTS', KEYS[1]) == 1 then
+	redis.call('HINCRBY', KEYS[1], 'remaining', 1)
+end
+return 0
+`)
+
+// NewRateLimiter returns a new rest.RateLimiter which stores its state in redis using the given redis.UniversalClient & ConfigOpt(s).
+// All processes sharing one bot token should use the same redis and key prefix.
+// The redis.UniversalClient is not closed by the RateLimiter.
+func NewRateLimiter(client redis.UniversalClient, opts ...ConfigOpt) rest.RateLimiter {
+	config := DefaultConfig()
+	config.Apply(opts)
+	config.Logger = config.Logger.With(slog.String("name", "rest_redis_rate_limiter"))
+
+	return &rateLimiterImpl{
+		client: client,
+		config: *config,
+	}
+}
+
+type rateLimiterImpl struct {
+	client redis.UniversalClient
+	config Config
+}
+
+func (l *rateLimiterImpl) MaxRetries() int {
+	return l.config.MaxRetries
+}
+
+// Close is a no-op as the RateLimiter holds no local state.
+func (l *rateLimiterImpl) Close(_ context.Context) {}
+
+// Reset is a no-op as the state in redis is shared with other processes.
+func (l *rateLimiterImpl) Reset() {}
+
+func (l *rateLimiterImpl) key(parts ...string) string {
+	key := l.config.Prefix
+	for i, part := range parts {
+		if i > 0 {
+			key += ":"
+		}
+		key += part
+	}
+	return key
+}
+
+func routeHash(endpoint *rest.CompiledEndpoint) string {
+	return endpoint.Endpoint.Method + "+" + endpoint.Endpoint.Route
+}
+
+// bucketKey returns the redis key of the bucket the endpoint belongs to.
+// Routes are mapped to the bucket hash Discord sent us. Until we know it, the route itself is used.
+func (l *rateLimiterImpl) bucketKey(ctx context.Context, endpoint *rest.CompiledEndpoint) (string, error) {
+	hash, err := l.client.Get(ctx, l.key("hash", routeHash(endpoint))).Result()
+	if errors.Is(err, redis.Nil) {
+		hash = routeHash(endpoint)
+	} else if err != nil {
+		return "", fmt.Errorf("failed to get bucket hash: %w", err)
+	}
+	if endpoint.MajorParams != "" {
+		hash += "+" + endpoint.MajorParams
+	}
+	return l.key("bucket", hash), nil
+}
+
+func (l *rateLimiterImpl) WaitBucket(ctx context.Context, endpoint *rest.CompiledEndpoint) error {
+	for {
+		key, err := l.bucketKey(ctx, endpoint)
+		if err != nil {
+			return err
+		}
+
+		now := time.Now()
+		result, err := acquireScript.Run(ctx, l.client, []string{key, l.key("global")}, now.UnixMilli(), l.config.PendingTimeout.Milliseconds()).Int64Slice()
+		if err != nil {
+			return fmt.Errorf("failed to acquire bucket: %w", err)
+		}
+		if len(result) != 2 {
+			return fmt.Errorf("unexpected acquire result: %v", result)
+		}
+		wait, pending := time.Duration(result[0])*time.Millisecond, result[1] == 1
+		if wait <= 0 {
+			return nil
+		}
+
+		until := now.Add(wait)
+		if pending {
+			// the response of the pending request tells us the limits, which can be any time before the reservation expires
+			wait = min(wait, l.config.PollInterval)
+		} else if deadline, ok := ctx.Deadline(); ok && until.After(deadline) {
+			return context.DeadlineExceeded
+		}
+		l.config.Logger.Debug("waiting for rest bucket", slog.String("key", key), slog.Time("until", until), slog.Bool("pending", pending))
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+func (l *rateLimiterImpl) UnlockBucket(endpoint *rest.CompiledEndpoint, rs *http.Response) error {
+	ctx := context.Background()
+
+	// the key WaitBucket used, which differs from the bucket key if we learn the bucket hash with this response
+	pendingKey, err := l.bucketKey(ctx, endpoint)
+	if err != nil {
+		return err
+	}
+
+	// no response provided means the request was never sent or failed, so we give the request back
+	if rs == nil || rs.Header == nil {
+		return releaseScript.Run(ctx, l.client, []string{pendingKey}).Err()
+	}
+
+	global := rs.Header.Get("X-RateLimit-Global") != ""
+	cloudflare := rs.Header.Get("via") == ""
+	bucketHeader := rs.Header.Get("X-RateLimit-Bucket")
+	remainingHeader := rs.Header.Get("X-RateLimit-Remaining")
+	limitHeader := rs.Header.Get("X-RateLimit-Limit")
+	resetHeader := rs.Header.Get("X-RateLimit-Reset")
+	resetAfterHeader := rs.Header.Get("X-RateLimit-Reset-After")
+	retryAfterHeader := rs.Header.Get("Retry-After")
+
+	l.config.Logger.Debug("ratelimit response headers", slog.Int("code", rs.StatusCode), slog.Bool("global", global), slog.Bool("cloudflare", cloudflare), slog.String("bucket", bucketHeader), slog.String("remaining", remainingHeader), slog.String("limit", limitHeader), slog.String("reset", resetHeader), slog.String("reset_after", resetAfterHeader), slog.String("retry_after", retryAfterHeader))
+
+	if bucketHeader != "" {
+		if err := l.client.Set(ctx, l.key("hash", routeHash(endpoint)), bucketHeader, l.config.HashTTL).Err(); err != nil {
+			return fmt.Errorf("failed to set bucket hash: %w", err)
+		}
+	}
+
+	key, err := l.bucketKey(ctx, endpoint)
+	if err != nil {
+		return err
+	}
+	if key != pendingKey {
+		// the reservation was made on the route key, release it so waiters move on to the bucket key
+		defer func() {
+			if err := l.client.Del(ctx, pendingKey).Err(); err != nil {
+				l.config.Logger.Error("failed to release pending bucket", slog.String("key", pendingKey), slog.Any("err", err))
+			}
+		}()
+	}
+
+	now := time.Now()
+
+	// we hit a rate limit. let's see if it was global cloudflare or a route specific one
+	if rs.StatusCode == http.StatusTooManyRequests {
+		retryAfter, err := strconv.ParseFloat(retryAfterHeader, 64)
+		if err != nil {
+			return fmt.Errorf("invalid retryAfter %s: %w", retryAfterHeader, err)
+		}
+		retryAfterDuration := time.Duration(retryAfter * float64(time.Second))
+		reset := now.Add(retryAfterDuration)
+
+		if global || cloudflare {
+			l.config.Logger.Warn("global rate limit exceeded", slog.Float64("retry_after", retryAfter), slog.Bool("cloudflare", cloudflare))
+			return l.client.Set(ctx, l.key("global"), reset.UnixMilli(), retryAfterDuration).Err()
+		}
+
+		l.config.Logger.Warn("rate limit exceeded", slog.String("endpoint", endpoint.URL), slog.Float64("retry_after", retryAfter))
+		pipe := l.client.TxPipeline()
+		pipe.HSet(ctx, key, "remaining", 0, "reset", reset.UnixMilli())
+		pipe.HDel(ctx, key, "pending")
+		pipe.PExpireAt(ctx, key, reset.Add(time.Second))
+		_, err = pipe.Exec(ctx)
+		return err
+	}
+
+	// if we don't have a bucket header, we can't update anything and just give back the request
+	if bucketHeader == "" {
+		return releaseScript.Run(ctx, l.client, []string{key}).Err()
+	}
+
+	limit := -1
+	if limitHeader != "" {
+		if limit, err = strconv.Atoi(limitHeader); err != nil {
+			return fmt.Errorf("invalid limit %s: %w", limitHeader, err)
+		}
+	}
+
+	remaining := 0
+	if remainingHeader != "" {
+		if remaining, err = strconv.Atoi(remainingHeader); err != nil {
+			return fmt.Errorf("invalid remaining %s: %w", remainingHeader, err)
+		}
+	}
+
+	// we prioritize the reset after header over the reset header as it's more accurate due to clock differences
+	var reset time.Time
+	if resetAfterHeader != "" {
+		resetAfter, err := strconv.ParseFloat(resetAfterHeader, 64)
+		if err != nil {
+			return fmt.Errorf("invalid reset after %s: %w", resetAfterHeader, err)
+		}
+		reset = now.Add(time.Duration(resetAfter * float64(time.Second)))
+	} else if resetHeader != "" {
+		resetUnix, err := strconv.ParseFloat(resetHeader, 64)
+		if err != nil {
+			return fmt.Errorf("invalid reset %s: %w", resetHeader, err)
+		}
+		reset = time.UnixMilli(int64(resetUnix * 1000))
+	} else {
+		return fmt.Errorf("no reset or reset after header found in response")
+	}
+
+	return updateScript.Run(ctx, l.client, []string{key}, limit, remaining, reset.UnixMilli(), now.UnixMilli()).Err()
+}
@@ -0,0 +1,89 @@
+package restredis
+
+import (
+	"log/slog"
+	"time"
+
+	"github.com/disgoorg/disgo/rest"
+)
+
+// DefaultConfig returns a Config with sensible defaults.
+func DefaultConfig() *Config {
+	return &Config{
+		Logger:         slog.Default(),
+		MaxRetries:     rest.MaxRetries,
+		Prefix:         "disgo:rest:",
+		PendingTimeout: 10 * time.Second,
+		PollInterval:   100 * time.Millisecond,
+		HashTTL:        24 * time.Hour,
+	}
+}
+
+// Config lets you configure your RateLimiter instance.
+type Config struct {
+	// Logger is the logger of the RateLimiter. Defaults to slog.Default().
+	Logger *slog.Logger
+	// MaxRetries is the maximum number of retries the rest.Client should do on a 429 response. Defaults to rest.MaxRetries.
+	MaxRetries int
+	// Prefix is prepended to all redis keys. Use a different prefix per bot token. Defaults to "disgo:rest:".
+	Prefix string
+	// PendingTimeout is how long a bucket with unknown limits is reserved for a single request before other requests may try again.
+	// This should be larger than the timeout of the http.Client used by the rest.Client. Defaults to 10 seconds.
+	PendingTimeout time.Duration
+	// PollInterval is how often requests waiting for a reserved bucket check whether its limits are known. Defaults to 100 milliseconds.
+	PollInterval time.Duration
+	// HashTTL is how long route to bucket hash mappings are kept in redis. Defaults to 24 hours.
+	HashTTL time.Duration
+}
+
+// ConfigOpt can be used to supply optional parameters to NewRateLimiter.
+type ConfigOpt func(config *Config)
+
+// Apply applies the given ConfigOpt(s) to the Config.
+func (c *Config) Apply(opts []ConfigOpt) {
+	for _, opt := range opts {
+		opt(c)
+	}
+}
+
+// WithLogger applies a custom logger to the RateLimiter.
+func WithLogger(logger *slog.Logger) ConfigOpt {
+	return func(config *Config) {
+		config.Logger = logger
+	}
+}
+
+// WithMaxRetries tells the rest.Client to retry the request up to the specified number of times if it encounters a 429 response.
+func WithMaxRetries(maxRetries int) ConfigOpt {
+	return func(config *Config) {
+		config.MaxRetries = maxRetries
+	}
+}
+
+// WithPrefix sets the prefix of all redis keys used by the RateLimiter.
+func WithPrefix(prefix string) ConfigOpt {
+	return func(config *Config) {
+		config.Prefix = prefix
+	}
+}
+
+// WithPendingTimeout sets how long a bucket with unknown limits is reserved for a single request.
+func WithPendingTimeout(pendingTimeout time.Duration) ConfigOpt {
+	return func(config *Config) {
+		config.PendingTimeout = pendingTimeout
+	}
+}
+
+// WithPollInterval sets how often requests waiting for a reserved bucket check whether its limits are known.
+func WithPollInterval(pollInterval time.Duration) ConfigOpt {
+	return func(config *Config) {
+		config.PollInterval = pollInterval
+	}
+}
+
+// WithHashTTL sets how long route to bucket hash mappings are kept in redis.
+func WithHashTTL(hashTTL time.Duration) ConfigOpt {
+	return func(config *Config) {
+		config.HashTTL = hashTTL
+	}
+}
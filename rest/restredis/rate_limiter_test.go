@@ -0,0 +1,109 @@
+package restredis
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/disgoorg/disgo/rest"
+)
+
+func newTestRateLimiter(t *testing.T) rest.RateLimiter {
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	t.Cleanup(func() {
+		_ = client.Close()
+	})
+	return NewRateLimiter(client)
+}
+
+func shortCtx(t *testing.T) context.Context {
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	t.Cleanup(cancel)
+	return ctx
+}
+
+func TestRateLimiter_Bucket(t *testing.T) {
+	limiter := newTestRateLimiter(t)
+	endpoint := rest.GetChannel.Compile(nil, 1)
+
+	// unknown buckets only allow a single request until we know the limits
+	assert.NoError(t, limiter.WaitBucket(context.Background(), endpoint))
+	assert.ErrorIs(t, limiter.WaitBucket(shortCtx(t), endpoint), context.DeadlineExceeded)
+
+	assert.NoError(t, limiter.UnlockBucket(endpoint, &http.Response{
+		StatusCode: http.StatusOK,
+		Header: http.Header{
+			"X-Ratelimit-Bucket":      []string{"abcd"},
+			"X-Ratelimit-Limit":       []string{"2"},
+			"X-Ratelimit-Remaining":   []string{"1"},
+			"X-Ratelimit-Reset-After": []string{"10"},
+			"Via":                     []string{"1.1 google"},
+		},
+	}))
+
+	assert.NoError(t, limiter.WaitBucket(shortCtx(t), endpoint))
+	assert.ErrorIs(t, limiter.WaitBucket(shortCtx(t), endpoint), context.DeadlineExceeded)
+
+	// other major params use their own bucket
+	assert.NoError(t, limiter.WaitBucket(shortCtx(t), rest.GetChannel.Compile(nil, 2)))
+}
+
+func TestRateLimiter_PendingWaiter(t *testing.T) {
+	limiter := newTestRateLimiter(t)
+	endpoint := rest.GetChannel.Compile(nil, 1)
+
+	assert.NoError(t, limiter.WaitBucket(context.Background(), endpoint))
+
+	done := make(chan error, 1)
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+		done <- limiter.WaitBucket(ctx, endpoint)
+	}()
+
+	// the waiter is woken once the limits are known instead of waiting for the reservation to expire
+	time.Sleep(50 * time.Millisecond)
+	assert.NoError(t, limiter.UnlockBucket(endpoint, &http.Response{
+		StatusCode: http.StatusOK,
+		Header: http.Header{
+			"X-Ratelimit-Bucket":      []string{"abcd"},
+			"X-Ratelimit-Limit":       []string{"5"},
+			"X-Ratelimit-Remaining":   []string{"4"},
+			"X-Ratelimit-Reset-After": []string{"10"},
+			"Via":                     []string{"1.1 google"},
+		},
+	}))
+	assert.NoError(t, <-done)
+}
+
+func TestRateLimiter_Release(t *testing.T) {
+	limiter := newTestRateLimiter(t)
+	endpoint := rest.GetChannel.Compile(nil, 1)
+
+	assert.NoError(t, limiter.WaitBucket(context.Background(), endpoint))
+	assert.NoError(t, limiter.UnlockBucket(endpoint, nil))
+	assert.NoError(t, limiter.WaitBucket(shortCtx(t), endpoint))
+}
+
+func TestRateLimiter_Global(t *testing.T) {
+	limiter := newTestRateLimiter(t)
+	endpoint := rest.GetChannel.Compile(nil, 1)
+
+	assert.NoError(t, limiter.WaitBucket(context.Background(), endpoint))
+	assert.NoError(t, limiter.UnlockBucket(endpoint, &http.Response{
+		StatusCode: http.StatusTooManyRequests,
+		Header: http.Header{
+			"X-Ratelimit-Global": []string{"true"},
+			"Retry-After":        []string{"5"},
+			"Via":                []string{"1.1 google"},
+		},
+	}))
+
+	assert.ErrorIs(t, limiter.WaitBucket(shortCtx(t), rest.GetGuild.Compile(nil, 1)), context.DeadlineExceeded)
+}
@@ -0,0 +1,68 @@
+package sharding
+
+import (
+	"context"
+	"hash/fnv"
+	"slices"
+	"strconv"
+)
+
+// Coordinator distributes the shards of a bot across multiple worker processes using a shared CoordinatorBackend.
+// Each worker runs its own ShardManager and a Coordinator which opens & closes the shards assigned to it.
+// When a worker joins, leaves or dies, the shards are rebalanced across the remaining workers.
+// A worker only opens a shard after claiming it in the CoordinatorBackend, so a shard is never open on two workers at once.
+// Claims expire after the worker TTL, a worker which can't refresh its claims in time closes its shards.
+//
+//	coordinator := sharding.NewCoordinator(backend, shardCount, sharding.WithCoordinatorRateLimiter(shardingredis.NewRateLimiter(redisClient)))
+//	shardManager := sharding.New(token, eventHandlerFunc, sharding.WithShardCount(shardCount), sharding.WithRateLimiter(coordinator.RateLimiter()))
+//	err := coordinator.Run(ctx, shardManager)
+type Coordinator interface {
+	// WorkerID returns the ID of this worker.
+	WorkerID() string
+
+	// ShardIDs returns the shard IDs currently assigned to this worker.
+	ShardIDs() []int
+
+	// RateLimiter returns the RateLimiter of the CoordinatorConfig which limits the logins of the shards across the whole cluster.
+	// It should be used by the ShardManager passed to Run.
+	RateLimiter() RateLimiter
+
+	// Run joins the cluster and keeps the shards of the ShardManager in sync with the shards assigned to this worker.
+	// It blocks until the context is done, then closes all shards and leaves the cluster.
+	Run(ctx context.Context, shardManager ShardManager) error
+}
+
+// assignShards assigns each shard to one of the given workers using rendezvous hashing.
+// Every worker computes the same assignment for the same set of workers and only the shards of a removed worker move when it leaves.
+func assignShards(workers []string, shardCount int) map[string][]int {
+	assignments := make(map[string][]int, len(workers))
+	if len(workers) == 0 {
+		return assignments
+	}
+
+	workers = slices.Clone(workers)
+	slices.Sort(workers)
+	for shardID := 0; shardID < shardCount; shardID++ {
+		var (
+			bestWorker string
+			bestScore  uint64
+		)
+		for _, worker := range workers {
+			score := rendezvousScore(worker, shardID)
+			if bestWorker == "" || score > bestScore {
+				bestWorker = worker
+				bestScore = score
+			}
+		}
+		assignments[bestWorker] = append(assignments[bestWorker], shardID)
+	}
+	return assignments
+}
+
+func rendezvousScore(worker string, shardID int) uint64 {
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(worker))
+	_, _ = h.Write([]byte{':'})
+	_, _ = h.Write([]byte(strconv.Itoa(shardID)))
+	return h.Sum64()
+}
@@ -0,0 +1,120 @@
+package sharding
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"time"
+)
+
+// CoordinatorBackend stores the state shared by all workers of a cluster coordinated by Coordinator(s).
+// Implementations must be safe for concurrent use by all workers of the cluster.
+type CoordinatorBackend interface {
+	// Heartbeat registers the worker or refreshes its registration.
+	// Workers which did not send a heartbeat within the ttl are considered dead.
+	Heartbeat(ctx context.Context, workerID string, ttl time.Duration) error
+
+	// Leave removes the worker from the cluster and releases all its shard claims.
+	Leave(ctx context.Context, workerID string) error
+
+	// Workers returns the IDs of all alive workers.
+	Workers(ctx context.Context) ([]string, error)
+
+	// ClaimShards claims the given shards for the worker or refreshes its existing claims.
+	// A claim expires after the ttl, so a shard of a dead worker can be claimed by another one.
+	// Returns the shard IDs claimed by the worker, shards claimed by another worker are left out.
+	ClaimShards(ctx context.Context, workerID string, shardIDs []int, ttl time.Duration) ([]int, error)
+
+	// ReleaseShards releases the claims of the worker on the given shards.
+	// Shards claimed by another worker are not touched.
+	ReleaseShards(ctx context.Context, workerID string, shardIDs []int) error
+}
+
+var _ CoordinatorBackend = (*memoryCoordinatorBackend)(nil)
+
+// NewMemoryCoordinatorBackend returns a CoordinatorBackend which keeps the cluster state in memory.
+// It can only coordinate workers within the same process and is mainly meant as a reference & for testing.
+func NewMemoryCoordinatorBackend() CoordinatorBackend {
+	return &memoryCoordinatorBackend{
+		workers: map[string]time.Time{},
+		claims:  map[int]memoryShardClaim{},
+	}
+}
+
+type memoryShardClaim struct {
+	workerID string
+	until    time.Time
+}
+
+type memoryCoordinatorBackend struct {
+	mu      sync.Mutex
+	workers map[string]time.Time
+	claims  map[int]memoryShardClaim
+}
+
+func (b *memoryCoordinatorBackend) Heartbeat(_ context.Context, workerID string, ttl time.Duration) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.workers[workerID] = time.Now().Add(ttl)
+	return nil
+}
+
+func (b *memoryCoordinatorBackend) Leave(_ context.Context, workerID string) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	delete(b.workers, workerID)
+	for shardID, claim := range b.claims {
+		if claim.workerID == workerID {
+			delete(b.claims, shardID)
+		}
+	}
+	return nil
+}
+
+func (b *memoryCoordinatorBackend) Workers(_ context.Context) ([]string, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := time.Now()
+	workers := make([]string, 0, len(b.workers))
+	for workerID, expiry := range b.workers {
+		if expiry.Before(now) {
+			delete(b.workers, workerID)
+			continue
+		}
+		workers = append(workers, workerID)
+	}
+	slices.Sort(workers)
+	return workers, nil
+}
+
+func (b *memoryCoordinatorBackend) ClaimShards(_ context.Context, workerID string, shardIDs []int, ttl time.Duration) ([]int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := time.Now()
+	claimed := make([]int, 0, len(shardIDs))
+	for _, shardID := range shardIDs {
+		if claim, ok := b.claims[shardID]; ok && claim.workerID != workerID && claim.until.After(now) {
+			continue
+		}
+		b.claims[shardID] = memoryShardClaim{
+			workerID: workerID,
+			until:    now.Add(ttl),
+		}
+		claimed = append(claimed, shardID)
+	}
+	return claimed, nil
+}
+
+func (b *memoryCoordinatorBackend) ReleaseShards(_ context.Context, workerID string, shardIDs []int) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for _, shardID := range shardIDs {
+		if claim, ok := b.claims[shardID]; ok && claim.workerID == workerID {
+			delete(b.claims, shardID)
+		}
+	}
+	return nil
+}
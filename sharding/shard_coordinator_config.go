@@ -0,0 +1,81 @@
+package sharding
+
+import (
+	"log/slog"
+	"time"
+
+	"github.com/disgoorg/disgo/internal/insecurerandstr"
+)
+
+// DefaultCoordinatorConfig returns a CoordinatorConfig with sensible defaults.
+func DefaultCoordinatorConfig() *CoordinatorConfig {
+	return &CoordinatorConfig{
+		Logger:            slog.Default(),
+		WorkerID:          insecurerandstr.RandStr(16),
+		HeartbeatInterval: 5 * time.Second,
+		WorkerTTL:         15 * time.Second,
+	}
+}
+
+// CoordinatorConfig lets you configure your Coordinator instance.
+type CoordinatorConfig struct {
+	// Logger is the logger of the Coordinator. Defaults to slog.Default()
+	Logger *slog.Logger
+	// WorkerID is the unique ID of this worker in the cluster. Defaults to a random string.
+	WorkerID string
+	// RateLimiter is the RateLimiter returned by Coordinator.RateLimiter. Defaults to NewRateLimiter(), which only limits the shards of this worker.
+	// Workers running in separate processes need a RateLimiter shared by all of them, like the one of shardingredis.
+	RateLimiter RateLimiter
+	// HeartbeatInterval is how often the worker refreshes its registration & rebalances its shards. Defaults to 5 seconds.
+	HeartbeatInterval time.Duration
+	// WorkerTTL is after how long without heartbeat a worker is considered dead and its shard claims expire. Defaults to 15 seconds.
+	WorkerTTL time.Duration
+}
+
+// CoordinatorConfigOpt is a type alias for a function that takes a CoordinatorConfig and is used to configure your Coordinator.
+type CoordinatorConfigOpt func(config *CoordinatorConfig)
+
+// Apply applies the given CoordinatorConfigOpt(s) to the CoordinatorConfig
+func (c *CoordinatorConfig) Apply(opts []CoordinatorConfigOpt) {
+	for _, opt := range opts {
+		opt(c)
+	}
+	if c.RateLimiter == nil {
+		c.RateLimiter = NewRateLimiter()
+	}
+}
+
+// WithCoordinatorLogger sets the logger of the Coordinator.
+func WithCoordinatorLogger(logger *slog.Logger) CoordinatorConfigOpt {
+	return func(config *CoordinatorConfig) {
+		config.Logger = logger
+	}
+}
+
+// WithWorkerID sets the unique ID of this worker in the cluster.
+func WithWorkerID(workerID string) CoordinatorConfigOpt {
+	return func(config *CoordinatorConfig) {
+		config.WorkerID = workerID
+	}
+}
+
+// WithCoordinatorRateLimiter sets the RateLimiter which limits the logins of the shards across the whole cluster.
+func WithCoordinatorRateLimiter(rateLimiter RateLimiter) CoordinatorConfigOpt {
+	return func(config *CoordinatorConfig) {
+		config.RateLimiter = rateLimiter
+	}
+}
+
+// WithHeartbeatInterval sets how often the worker refreshes its registration & rebalances its shards.
+func WithHeartbeatInterval(heartbeatInterval time.Duration) CoordinatorConfigOpt {
+	return func(config *CoordinatorConfig) {
+		config.HeartbeatInterval = heartbeatInterval
+	}
+}
+
+// WithWorkerTTL sets after how long without heartbeat a worker is considered dead.
+func WithWorkerTTL(workerTTL time.Duration) CoordinatorConfigOpt {
+	return func(config *CoordinatorConfig) {
+		config.WorkerTTL = workerTTL
+	}
+}
@@ -0,0 +1,285 @@
+package sharding
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+	"sync"
+	"time"
+)
+
+var _ Coordinator = (*coordinatorImpl)(nil)
+
+// NewCoordinator creates a new default Coordinator with the given CoordinatorBackend, total shard count and CoordinatorConfigOpt(s).
+// All workers of a cluster must use the same shard count.
+func NewCoordinator(backend CoordinatorBackend, shardCount int, opts ...CoordinatorConfigOpt) Coordinator {
+	config := DefaultCoordinatorConfig()
+	config.Apply(opts)
+	config.Logger = config.Logger.With(slog.String("name", "sharding_coordinator"), slog.String("worker_id", config.WorkerID))
+
+	return &coordinatorImpl{
+		backend:    backend,
+		shardCount: shardCount,
+		config:     *config,
+	}
+}
+
+type coordinatorImpl struct {
+	backend    CoordinatorBackend
+	shardCount int
+	config     CoordinatorConfig
+
+	// refreshMu serializes refreshing & changing the shard claims, so a released claim is not refreshed again.
+	refreshMu sync.Mutex
+
+	mu          sync.Mutex
+	shardIDs    []int
+	claimed     map[int]struct{}
+	lastRefresh time.Time
+}
+
+func (c *coordinatorImpl) WorkerID() string {
+	return c.config.WorkerID
+}
+
+func (c *coordinatorImpl) ShardIDs() []int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return slices.Clone(c.shardIDs)
+}
+
+func (c *coordinatorImpl) RateLimiter() RateLimiter {
+	return c.config.RateLimiter
+}
+
+func (c *coordinatorImpl) Run(ctx context.Context, shardManager ShardManager) error {
+	now := time.Now()
+	if err := c.backend.Heartbeat(ctx, c.config.WorkerID, c.config.WorkerTTL); err != nil {
+		return fmt.Errorf("failed to join cluster: %w", err)
+	}
+	c.mu.Lock()
+	c.claimed = map[int]struct{}{}
+	c.lastRefresh = now
+	c.mu.Unlock()
+	c.config.Logger.Debug("joined cluster")
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		c.heartbeat(ctx, shardManager)
+	}()
+
+	// the first rebalance happens after one interval to let workers started at the same time register first
+	ticker := time.NewTicker(c.config.HeartbeatInterval)
+	defer ticker.Stop()
+loop:
+	for {
+		select {
+		case <-ctx.Done():
+			break loop
+		case <-ticker.C:
+			c.rebalance(ctx, shardManager)
+		}
+	}
+	wg.Wait()
+
+	closeCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	// close our shards before leaving, so they are not opened twice
+	shardManager.Close(closeCtx)
+	if err := c.backend.Leave(closeCtx, c.config.WorkerID); err != nil {
+		return fmt.Errorf("failed to leave cluster: %w", err)
+	}
+	c.config.Logger.Debug("left cluster")
+	return nil
+}
+
+// heartbeat refreshes the registration & shard claims of this worker until the context is done.
+// It runs separately from rebalancing as opening shards may take a long time.
+func (c *coordinatorImpl) heartbeat(ctx context.Context, shardManager ShardManager) {
+	ticker := time.NewTicker(c.config.HeartbeatInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			c.refresh(ctx, shardManager)
+		}
+	}
+}
+
+// refresh refreshes the registration & shard claims of this worker.
+// Shards whose claim was lost are closed. If the claims expire before the next refresh, all shards are closed, as other workers may open them then.
+func (c *coordinatorImpl) refresh(ctx context.Context, shardManager ShardManager) {
+	c.refreshMu.Lock()
+	defer c.refreshMu.Unlock()
+
+	now := time.Now()
+	shardIDs := c.claimedShardIDs()
+	err := c.backend.Heartbeat(ctx, c.config.WorkerID, c.config.WorkerTTL)
+	var claimed []int
+	if err == nil {
+		claimed, err = c.backend.ClaimShards(ctx, c.config.WorkerID, shardIDs, c.config.WorkerTTL)
+	}
+	if err != nil {
+		c.config.Logger.Error("failed to send heartbeat", slog.Any("err", err))
+		c.mu.Lock()
+		expired := time.Since(c.lastRefresh)+c.config.HeartbeatInterval >= c.config.WorkerTTL
+		c.mu.Unlock()
+		if expired {
+			c.config.Logger.Warn("registration expires before the next heartbeat, closing all shards")
+			c.closeShards(ctx, shardManager, shardIDs)
+		}
+		return
+	}
+
+	c.mu.Lock()
+	c.lastRefresh = now
+	c.mu.Unlock()
+
+	var lost []int
+	for _, shardID := range shardIDs {
+		if !slices.Contains(claimed, shardID) {
+			lost = append(lost, shardID)
+		}
+	}
+	if len(lost) > 0 {
+		c.config.Logger.Warn("shard claims taken over by another worker, closing shards", slog.String("shard_ids", fmt.Sprint(lost)))
+		c.closeShards(ctx, shardManager, lost)
+	}
+}
+
+// rebalance opens the shards newly assigned to this worker and closes the ones assigned to other workers.
+// Shards are only opened once this worker claimed them, which happens after the previous owner released them or its claim expired.
+func (c *coordinatorImpl) rebalance(ctx context.Context, shardManager ShardManager) {
+	workers, err := c.backend.Workers(ctx)
+	if err != nil {
+		c.config.Logger.Error("failed to get workers", slog.Any("err", err))
+		return
+	}
+
+	c.refreshMu.Lock()
+	if !slices.Contains(workers, c.config.WorkerID) {
+		// our registration expired, so other workers may have taken over our shards already
+		c.config.Logger.Warn("registration expired, closing all shards and joining again")
+		c.closeShards(ctx, shardManager, c.claimedShardIDs())
+		now := time.Now()
+		if err = c.backend.Heartbeat(ctx, c.config.WorkerID, c.config.WorkerTTL); err != nil {
+			c.refreshMu.Unlock()
+			c.config.Logger.Error("failed to join cluster", slog.Any("err", err))
+			return
+		}
+		c.mu.Lock()
+		c.lastRefresh = now
+		c.mu.Unlock()
+		workers = append(workers, c.config.WorkerID)
+	}
+
+	shardIDs := assignShards(workers, c.shardCount)[c.config.WorkerID]
+	c.mu.Lock()
+	c.shardIDs = shardIDs
+	c.mu.Unlock()
+
+	shards := shardManager.Shards()
+	var moved []int
+	for _, shardID := range c.claimedShardIDs() {
+		if !slices.Contains(shardIDs, shardID) {
+			moved = append(moved, shardID)
+		}
+	}
+	for shardID := range shards {
+		if !slices.Contains(shardIDs, shardID) && !slices.Contains(moved, shardID) {
+			moved = append(moved, shardID)
+		}
+	}
+	if len(moved) > 0 {
+		c.config.Logger.Debug("shards moved to other workers", slog.String("shard_ids", fmt.Sprint(moved)))
+		c.closeShards(ctx, shardManager, moved)
+	}
+
+	var unclaimed []int
+	for _, shardID := range shardIDs {
+		if !c.isClaimed(shardID) {
+			unclaimed = append(unclaimed, shardID)
+		}
+	}
+	var claimed []int
+	if len(unclaimed) > 0 {
+		claimed, err = c.backend.ClaimShards(ctx, c.config.WorkerID, unclaimed, c.config.WorkerTTL)
+		if err != nil {
+			c.config.Logger.Error("failed to claim shards", slog.Any("err", err))
+		}
+		c.mu.Lock()
+		for _, shardID := range claimed {
+			c.claimed[shardID] = struct{}{}
+		}
+		c.mu.Unlock()
+		if len(claimed) < len(unclaimed) {
+			c.config.Logger.Debug("waiting for other workers to release shards", slog.Int("shards", len(unclaimed)-len(claimed)))
+		}
+	}
+	for _, shardID := range unclaimed {
+		// shards opened without a claim must not run until we claimed them
+		if _, ok := shards[shardID]; ok && !slices.Contains(claimed, shardID) {
+			shardManager.CloseShard(ctx, shardID)
+		}
+	}
+	c.refreshMu.Unlock()
+
+	var wg sync.WaitGroup
+	for _, shardID := range claimed {
+		if _, ok := shards[shardID]; ok {
+			continue
+		}
+		wg.Add(1)
+		go func(shardID int) {
+			defer wg.Done()
+			c.config.Logger.Debug("shard assigned to worker", slog.Int("shard_id", shardID))
+			if err := shardManager.OpenShard(ctx, shardID); err != nil {
+				c.config.Logger.Error("failed to open shard", slog.Any("err", err), slog.Int("shard_id", shardID))
+			}
+			// the claim might have been lost while the shard was waiting for its bucket
+			if !c.isClaimed(shardID) {
+				shardManager.CloseShard(ctx, shardID)
+			}
+		}(shardID)
+	}
+	wg.Wait()
+}
+
+// closeShards closes the given shards and releases their claims.
+func (c *coordinatorImpl) closeShards(ctx context.Context, shardManager ShardManager, shardIDs []int) {
+	c.mu.Lock()
+	for _, shardID := range shardIDs {
+		delete(c.claimed, shardID)
+	}
+	c.mu.Unlock()
+
+	for _, shardID := range shardIDs {
+		shardManager.CloseShard(ctx, shardID)
+	}
+	if err := c.backend.ReleaseShards(ctx, c.config.WorkerID, shardIDs); err != nil {
+		c.config.Logger.Error("failed to release shards", slog.Any("err", err))
+	}
+}
+
+func (c *coordinatorImpl) claimedShardIDs() []int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	shardIDs := make([]int, 0, len(c.claimed))
+	for shardID := range c.claimed {
+		shardIDs = append(shardIDs, shardID)
+	}
+	slices.Sort(shardIDs)
+	return shardIDs
+}
+
+func (c *coordinatorImpl) isClaimed(shardID int) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	_, ok := c.claimed[shardID]
+	return ok
+}
@@ -0,0 +1,85 @@
+package sharding
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAssignShards(t *testing.T) {
+	workers := []string{"a", "b", "c"}
+	assignments := assignShards(workers, 64)
+
+	seen := map[int]string{}
+	for worker, shardIDs := range assignments {
+		for _, shardID := range shardIDs {
+			_, ok := seen[shardID]
+			assert.False(t, ok, "shard %d assigned twice", shardID)
+			seen[shardID] = worker
+		}
+	}
+	assert.Len(t, seen, 64)
+
+	// only the shards of the removed worker move
+	for worker, shardIDs := range assignShards([]string{"c", "a"}, 64) {
+		for _, shardID := range shardIDs {
+			if seen[shardID] != "b" {
+				assert.Equal(t, seen[shardID], worker)
+			}
+		}
+	}
+}
+
+func TestMemoryCoordinatorBackend(t *testing.T) {
+	ctx := context.Background()
+	backend := NewMemoryCoordinatorBackend()
+
+	assert.NoError(t, backend.Heartbeat(ctx, "a", time.Minute))
+	assert.NoError(t, backend.Heartbeat(ctx, "b", -time.Second))
+	workers, err := backend.Workers(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"a"}, workers)
+}
+
+func TestCoordinatorRateLimiter(t *testing.T) {
+	rateLimiter := NewRateLimiter()
+	coordinator := NewCoordinator(NewMemoryCoordinatorBackend(), 1, WithCoordinatorRateLimiter(rateLimiter))
+	assert.Same(t, rateLimiter, coordinator.RateLimiter())
+
+	assert.NotNil(t, NewCoordinator(NewMemoryCoordinatorBackend(), 1).RateLimiter())
+}
+
+func TestMemoryCoordinatorBackend_ClaimShards(t *testing.T) {
+	ctx := context.Background()
+	backend := NewMemoryCoordinatorBackend()
+
+	claimed, err := backend.ClaimShards(ctx, "a", []int{0, 1}, time.Minute)
+	assert.NoError(t, err)
+	assert.Equal(t, []int{0, 1}, claimed)
+
+	// shards stay claimed until released or expired
+	claimed, err = backend.ClaimShards(ctx, "b", []int{1, 2}, time.Minute)
+	assert.NoError(t, err)
+	assert.Equal(t, []int{2}, claimed)
+
+	assert.NoError(t, backend.ReleaseShards(ctx, "b", []int{1}))
+	assert.NoError(t, backend.ReleaseShards(ctx, "a", []int{1}))
+	claimed, err = backend.ClaimShards(ctx, "b", []int{1}, time.Minute)
+	assert.NoError(t, err)
+	assert.Equal(t, []int{1}, claimed)
+
+	claimed, err = backend.ClaimShards(ctx, "a", []int{0}, -time.Second)
+	assert.NoError(t, err)
+	assert.Equal(t, []int{0}, claimed)
+	claimed, err = backend.ClaimShards(ctx, "b", []int{0}, time.Minute)
+	assert.NoError(t, err)
+	assert.Equal(t, []int{0}, claimed)
+
+	// leaving releases all claims
+	assert.NoError(t, backend.Leave(ctx, "b"))
+	claimed, err = backend.ClaimShards(ctx, "a", []int{0, 1, 2}, time.Minute)
+	assert.NoError(t, err)
+	assert.Equal(t, []int{0, 1, 2}, claimed)
+}
@@ -2,9 +2,12 @@ package sharding
 
 import (
 	"context"
+	"errors"
+	"time"
 
 	"github.com/disgoorg/snowflake/v2"
 
+	"github.com/disgoorg/disgo/discord"
 	"github.com/disgoorg/disgo/gateway"
 )
 
@@ -15,6 +18,7 @@ const ShardSplitCount = 2
 // For more information on sharding see: https://discord.com/developers/docs/topics/gateway#sharding
 type ShardManager interface {
 	// Open opens all configured shards.
+	// If a rest.Gateway is configured, the shard count, max concurrency & session start limit are fetched from Discord first.
 	Open(ctx context.Context)
 	// Close closes all shards.
 	Close(ctx context.Context)
@@ -35,6 +39,76 @@ type ShardManager interface {
 	Shards() map[int]gateway.Gateway
 }
 
+// ShardResharder is implemented by ShardManagers which can change their shard count without downtime.
+// Use a type assertion to check whether a ShardManager supports it.
+type ShardResharder interface {
+	// Reshard opens a new set of shards with the given shard count next to the current ones.
+	// Once all new shards are ready and received all their guilds, event dispatching & ShardByGuildID switch to the new shards and the old ones are closed.
+	// Shards without gateway.IntentGuilds are ready after READY, guilds which stay unavailable are skipped after Config.GuildReadyTimeout.
+	// Events of the new shards are not dispatched until the switch, so the caches stay populated from the old shards.
+	// If the ShardManager only manages some shards, the new shard count must be a multiple of the current one.
+	// If the context is done before the new shards are ready, they are closed and the current shards keep running.
+	Reshard(ctx context.Context, newShardCount int) error
+}
+
+// ShardHandoffer is implemented by ShardManagers which can hand off shards to another ShardManager.
+// Use a type assertion to check whether a ShardManager supports it.
+type ShardHandoffer interface {
+	// Handoff stops event delivery of the given shards and returns their sessions, so another ShardManager can take them over with TakeOver.
+	// If no shard IDs are given, all shards are handed off. The sessions are JSON serializable to pass them to another process.
+	// The shards stay connected until they are closed with CloseShard or Close, which keeps their sessions resumable.
+	// Shards without a session are skipped and reported in the returned error.
+	Handoff(shardIDs ...int) (map[int]gateway.Session, error)
+
+	// CancelHandoff takes back the sessions of the given handed off shards, which resume them & deliver the events missed since Handoff.
+	// It must only be called for shards which were not taken over.
+	CancelHandoff(ctx context.Context, shardIDs ...int) error
+
+	// TakeOver opens shards resuming the given sessions and waits until all of them are resumed or the context is done.
+	// Sessions must have been created with the same shard count as the ShardManager.
+	// Shards which fail to resume are closed without invalidating their session & removed again.
+	TakeOver(ctx context.Context, sessions map[int]gateway.Session) error
+}
+
+// HandoffShards moves the given shards from one ShardManager to another without missing events.
+// If no shard IDs are given, all shards of from are moved. Both ShardManagers must implement ShardHandoffer.
+// The shards of from are only closed after the shards of to resumed their sessions.
+// Shards which to fails to take over are handed back to from with ShardHandoffer.CancelHandoff.
+func HandoffShards(ctx context.Context, from ShardManager, to ShardManager, shardIDs ...int) error {
+	fromHandoffer, ok := from.(ShardHandoffer)
+	if !ok {
+		return discord.ErrHandoffNotSupported
+	}
+	toHandoffer, ok := to.(ShardHandoffer)
+	if !ok {
+		return discord.ErrHandoffNotSupported
+	}
+
+	sessions, handoffErr := fromHandoffer.Handoff(shardIDs...)
+	if len(sessions) == 0 {
+		return handoffErr
+	}
+
+	takeOverErr := toHandoffer.TakeOver(ctx, sessions)
+
+	var failedShardIDs []int
+	for shardID := range sessions {
+		if to.Shard(shardID) == nil {
+			failedShardIDs = append(failedShardIDs, shardID)
+			continue
+		}
+		from.CloseShard(ctx, shardID)
+	}
+	if len(failedShardIDs) == 0 {
+		return errors.Join(handoffErr, takeOverErr)
+	}
+
+	// the context might be done already, but the shards still need to resume their sessions
+	cancelCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 30*time.Second)
+	defer cancel()
+	return errors.Join(handoffErr, takeOverErr, fromHandoffer.CancelHandoff(cancelCtx, failedShardIDs...))
+}
+
 // ShardIDByGuild returns the shard ID for the given guildID and shardCount.
 func ShardIDByGuild(guildID snowflake.ID, shardCount int) int {
 	return int((uint64(guildID) >> 22) % uint64(shardCount))
@@ -2,8 +2,10 @@ package sharding
 
 import (
 	"log/slog"
+	"time"
 
 	"github.com/disgoorg/disgo/gateway"
+	"github.com/disgoorg/disgo/rest"
 )
 
 // DefaultConfig returns a Config with sensible defaults.
@@ -12,6 +14,7 @@ func DefaultConfig() *Config {
 		Logger:            slog.Default(),
 		GatewayCreateFunc: gateway.New,
 		ShardSplitCount:   ShardSplitCount,
+		GuildReadyTimeout: 15 * time.Second,
 	}
 }
 
@@ -23,6 +26,8 @@ type Config struct {
 	ShardIDs map[int]struct{}
 	// ShardCount is the total shard count of the ShardManager. Leave this at 0 to let Discord calculate the shard count for you.
 	ShardCount int
+	// Rest is used to fetch the recommended shard count, max concurrency & session start limit from Discord when opening the ShardManager. Defaults to nil (no fetching).
+	Rest rest.Gateway
 	// ShardSplitCount is the count a shard should be split into if it is too large. This is only used if AutoScaling is enabled.
 	ShardSplitCount int
 	// AutoScaling will automatically re-shard shards if they are too large. This is disabled by default.
@@ -35,6 +40,10 @@ type Config struct {
 	RateLimiter RateLimiter
 	// RateLimiterConfigOpts are the RateLimiterConfigOpt(s) which are applied to the RateLimiter.
 	RateLimiterConfigOpts []RateLimiterConfigOpt
+	// SessionStore is the gateway.SessionStore shared by all shards. Defaults to nil (no persistence).
+	SessionStore gateway.SessionStore
+	// GuildReadyTimeout is how long Reshard waits for the next guild of a new shard. Guilds still unavailable afterward are treated as received. Defaults to 15 seconds.
+	GuildReadyTimeout time.Duration
 }
 
 // ConfigOpt is a type alias for a function that takes a Config and is used to configure your Server.
@@ -48,6 +57,9 @@ func (c *Config) Apply(opts []ConfigOpt) {
 	if c.RateLimiter == nil {
 		c.RateLimiter = NewRateLimiter(c.RateLimiterConfigOpts...)
 	}
+	if c.SessionStore != nil {
+		c.GatewayConfigOpts = append(c.GatewayConfigOpts, gateway.WithSessionStore(c.SessionStore))
+	}
 }
 
 // WithLogger sets the logger of the ShardManager.
@@ -76,6 +88,16 @@ func WithShardCount(shardCount int) ConfigOpt {
 	}
 }
 
+// WithRest sets the rest.Gateway used to fetch the recommended shard count, max concurrency & session start limit from Discord when opening the ShardManager.
+// The shard count and shard IDs are only set if they were not configured.
+// If not enough session starts remain, no shards are opened, see gateway.EventSessionStartLimit.
+// The max concurrency is applied to the RateLimiter if it implements ConcurrencyRateLimiter.
+func WithRest(restGateway rest.Gateway) ConfigOpt {
+	return func(config *Config) {
+		config.Rest = restGateway
+	}
+}
+
 // WithShardSplitCount sets the count a shard should be split into if it is too large.
 // This is only used if AutoScaling is enabled.
 func WithShardSplitCount(shardSplitCount int) ConfigOpt {
@@ -112,9 +134,23 @@ func WithRateLimiter(rateLimiter RateLimiter) ConfigOpt {
 	}
 }
 
+// WithSessionStore sets the gateway.SessionStore all shards use to save their sessions on Close and resume them on Open.
+func WithSessionStore(sessionStore gateway.SessionStore) ConfigOpt {
+	return func(config *Config) {
+		config.SessionStore = sessionStore
+	}
+}
+
 // WithRateLimiterConfigOpt lets you configure the default RateLimiter used by the ShardManager.
 func WithRateLimiterConfigOpt(opts ...RateLimiterConfigOpt) ConfigOpt {
 	return func(config *Config) {
 		config.RateLimiterConfigOpts = append(config.RateLimiterConfigOpts, opts...)
 	}
 }
+
+// WithGuildReadyTimeout sets how long Reshard waits for the next guild of a new shard before treating the guilds still unavailable as received.
+func WithGuildReadyTimeout(guildReadyTimeout time.Duration) ConfigOpt {
+	return func(config *Config) {
+		config.GuildReadyTimeout = guildReadyTimeout
+	}
+}
@@ -6,20 +6,31 @@ import (
 	"fmt"
 	"log/slog"
 	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/disgoorg/snowflake/v2"
 	"github.com/gorilla/websocket"
 
+	"github.com/disgoorg/disgo/discord"
 	"github.com/disgoorg/disgo/gateway"
+	"github.com/disgoorg/disgo/rest"
 )
 
-var _ ShardManager = (*shardManagerImpl)(nil)
+var (
+	_ ShardManager   = (*shardManagerImpl)(nil)
+	_ ShardHandoffer = (*shardManagerImpl)(nil)
+	_ ShardResharder = (*shardManagerImpl)(nil)
+)
 
 // New creates a new default ShardManager with the given token, eventHandlerFunc and ConfigOpt(s).
 func New(token string, eventHandlerFunc gateway.EventHandlerFunc, opts ...ConfigOpt) ShardManager {
 	config := DefaultConfig()
 	config.Apply(opts)
 	config.Logger = config.Logger.With(slog.String("name", "sharding"))
+	if config.ShardIDs == nil {
+		config.ShardIDs = map[int]struct{}{}
+	}
 
 	return &shardManagerImpl{
 		shards:           map[int]gateway.Gateway{},
@@ -36,6 +47,15 @@ type shardManagerImpl struct {
 	token            string
 	eventHandlerFunc gateway.EventHandlerFunc
 	config           Config
+
+	// discovered is whether the gateway bot info was already applied to the config
+	discovered bool
+
+	// generation is the generation of shards which currently dispatches events. It's increased by every Reshard
+	generation atomic.Uint64
+	reshardMu  sync.Mutex
+	// reshardCancel aborts the running Reshard, which then closes its new shards. It's guarded by shardsMu
+	reshardCancel context.CancelFunc
 }
 
 func (m *shardManagerImpl) closeHandler(shard gateway.Gateway, err error) {
@@ -77,7 +97,7 @@ func (m *shardManagerImpl) closeHandler(shard gateway.Gateway, err error) {
 			}
 			defer m.config.RateLimiter.UnlockBucket(shardID)
 
-			newShard := m.config.GatewayCreateFunc(m.token, m.eventHandlerFunc, m.closeHandler, append(m.config.GatewayConfigOpts, gateway.WithShardID(shardID), gateway.WithShardCount(newShardCount))...)
+			newShard := m.config.GatewayCreateFunc(m.token, m.eventHandler(m.generation.Load(), nil), m.closeHandler, append(m.config.GatewayConfigOpts, gateway.WithShardID(shardID), gateway.WithShardCount(newShardCount))...)
 			m.shards[shardID] = newShard
 			if err := newShard.Open(context.TODO()); err != nil {
 				m.config.Logger.Error("failed to re shard", slog.Any("err", err), slog.Int("shard_id", shardID))
@@ -89,11 +109,18 @@ func (m *shardManagerImpl) closeHandler(shard gateway.Gateway, err error) {
 }
 
 func (m *shardManagerImpl) Open(ctx context.Context) {
-	m.config.Logger.Debug("opening shards", slog.String("shard_ids", fmt.Sprint(m.config.ShardIDs)))
-	var wg sync.WaitGroup
+	if m.config.Rest != nil {
+		if err := m.discoverShards(ctx); err != nil {
+			m.config.Logger.Error("failed to open shards", slog.Any("err", err))
+			return
+		}
+	}
 
 	m.shardsMu.Lock()
 	defer m.shardsMu.Unlock()
+
+	m.config.Logger.Debug("opening shards", slog.String("shard_ids", fmt.Sprint(m.config.ShardIDs)))
+	var wg sync.WaitGroup
 	for shardInt := range m.config.ShardIDs {
 		shardID := shardInt
 		if _, ok := m.shards[shardID]; ok {
@@ -109,7 +136,7 @@ func (m *shardManagerImpl) Open(ctx context.Context) {
 			}
 			defer m.config.RateLimiter.UnlockBucket(shardID)
 
-			shard := m.config.GatewayCreateFunc(m.token, m.eventHandlerFunc, m.closeHandler, append(m.config.GatewayConfigOpts, gateway.WithShardID(shardID), gateway.WithShardCount(m.config.ShardCount))...)
+			shard := m.config.GatewayCreateFunc(m.token, m.eventHandler(m.generation.Load(), nil), m.closeHandler, append(m.config.GatewayConfigOpts, gateway.WithShardID(shardID), gateway.WithShardCount(m.config.ShardCount))...)
 			m.shards[shardID] = shard
 			if err := shard.Open(ctx); err != nil {
 				m.config.Logger.Error("failed to open shard", slog.Any("err", err), slog.Int("shard_id", shardID))
@@ -119,7 +146,73 @@ func (m *shardManagerImpl) Open(ctx context.Context) {
 	wg.Wait()
 }
 
+// discoverShards fetches the gateway bot info from Discord to fill in the shard count, shard IDs, gateway url & max concurrency.
+// It fails if there are fewer session starts remaining than shards to open.
+func (m *shardManagerImpl) discoverShards(ctx context.Context) error {
+	// fetch without holding shardsMu, so the shards stay usable while waiting for Discord
+	gatewayBot, err := m.config.Rest.GetGatewayBot(rest.WithCtx(ctx))
+	if err != nil {
+		return fmt.Errorf("failed to get gateway bot: %w", err)
+	}
+	limit := gatewayBot.SessionStartLimit
+	m.config.Logger.Debug("fetched gateway bot", slog.Int("shards", gatewayBot.Shards), slog.Int("max_concurrency", limit.MaxConcurrency), slog.Int("remaining", limit.Remaining), slog.Int("total", limit.Total))
+
+	m.shardsMu.Lock()
+	if m.config.ShardCount == 0 {
+		m.config.ShardCount = gatewayBot.Shards
+	}
+	if len(m.config.ShardIDs) == 0 {
+		for shardID := 0; shardID < m.config.ShardCount; shardID++ {
+			m.config.ShardIDs[shardID] = struct{}{}
+		}
+	}
+	if !m.discovered {
+		// configured options take precedence over the values from Discord
+		m.config.GatewayConfigOpts = append([]gateway.ConfigOpt{gateway.WithURL(gatewayBot.URL)}, m.config.GatewayConfigOpts...)
+		m.discovered = true
+	}
+	if rateLimiter, ok := m.config.RateLimiter.(ConcurrencyRateLimiter); ok && limit.MaxConcurrency > 0 {
+		rateLimiter.SetMaxConcurrency(limit.MaxConcurrency)
+	}
+
+	var (
+		required     int
+		firstShardID = -1
+	)
+	for shardID := range m.config.ShardIDs {
+		if firstShardID == -1 || shardID < firstShardID {
+			firstShardID = shardID
+		}
+		if _, ok := m.shards[shardID]; !ok {
+			required++
+		}
+	}
+	m.shardsMu.Unlock()
+
+	// dispatch without holding shardsMu, so listeners can use the ShardManager
+	m.eventHandlerFunc(gateway.EventTypeSessionStartLimit, 0, max(firstShardID, 0), gateway.EventSessionStartLimit{
+		SessionStartLimit: limit,
+		ShardsToOpen:      required,
+	})
+
+	if limit.Remaining < required {
+		resetAfter := time.Duration(limit.ResetAfter) * time.Millisecond
+		return fmt.Errorf("not enough session starts remaining: %d of %d remaining but %d shards to open, resets in %s", limit.Remaining, limit.Total, required, resetAfter)
+	}
+	return nil
+}
+
 func (m *shardManagerImpl) Close(ctx context.Context) {
+	m.shardsMu.Lock()
+	reshardCancel := m.reshardCancel
+	m.shardsMu.Unlock()
+	if reshardCancel != nil {
+		// abort resharding and wait until the new shards are closed or switched to
+		reshardCancel()
+		m.reshardMu.Lock()
+		m.reshardMu.Unlock()
+	}
+
 	m.config.Logger.Debug("closing shards", slog.String("shard_ids", fmt.Sprint(m.config.ShardIDs)))
 	var wg sync.WaitGroup
 
@@ -148,7 +241,7 @@ func (m *shardManagerImpl) openShard(ctx context.Context, shardID int, shardCoun
 		return err
 	}
 	defer m.config.RateLimiter.UnlockBucket(shardID)
-	shard := m.config.GatewayCreateFunc(m.token, m.eventHandlerFunc, m.closeHandler, append(m.config.GatewayConfigOpts, gateway.WithShardID(shardID), gateway.WithShardCount(shardCount))...)
+	shard := m.config.GatewayCreateFunc(m.token, m.eventHandler(m.generation.Load(), nil), m.closeHandler, append(m.config.GatewayConfigOpts, gateway.WithShardID(shardID), gateway.WithShardCount(shardCount))...)
 
 	m.shardsMu.Lock()
 	defer m.shardsMu.Unlock()
@@ -168,8 +261,145 @@ func (m *shardManagerImpl) CloseShard(ctx context.Context, shardID int) {
 	}
 }
 
+func (m *shardManagerImpl) Handoff(shardIDs ...int) (map[int]gateway.Session, error) {
+	m.shardsMu.Lock()
+	defer m.shardsMu.Unlock()
+
+	if len(shardIDs) == 0 {
+		for shardID := range m.shards {
+			shardIDs = append(shardIDs, shardID)
+		}
+	}
+	m.config.Logger.Debug("handing off shards", slog.String("shard_ids", fmt.Sprint(shardIDs)))
+
+	sessions := make(map[int]gateway.Session, len(shardIDs))
+	var errs []error
+	for _, shardID := range shardIDs {
+		shard, ok := m.shards[shardID]
+		if !ok {
+			errs = append(errs, fmt.Errorf("shard %d: %w", shardID, discord.ErrShardNotFound))
+			continue
+		}
+		handoffer, ok := shard.(gateway.Handoffer)
+		if !ok {
+			errs = append(errs, fmt.Errorf("shard %d: %w", shardID, discord.ErrHandoffNotSupported))
+			continue
+		}
+		session, err := handoffer.Handoff()
+		if err != nil {
+			errs = append(errs, fmt.Errorf("shard %d: %w", shardID, err))
+			continue
+		}
+		sessions[shardID] = session
+	}
+	return sessions, errors.Join(errs...)
+}
+
+func (m *shardManagerImpl) CancelHandoff(ctx context.Context, shardIDs ...int) error {
+	m.config.Logger.Debug("cancelling shard handoff", slog.String("shard_ids", fmt.Sprint(shardIDs)))
+
+	var (
+		wg     sync.WaitGroup
+		errsMu sync.Mutex
+		errs   []error
+	)
+	for _, shardID := range shardIDs {
+		shardID := shardID
+		handoffer, ok := m.Shard(shardID).(gateway.Handoffer)
+		if !ok {
+			errs = append(errs, fmt.Errorf("shard %d: %w", shardID, discord.ErrShardNotFound))
+			continue
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := handoffer.CancelHandoff(ctx); err != nil {
+				errsMu.Lock()
+				errs = append(errs, fmt.Errorf("shard %d: %w", shardID, err))
+				errsMu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+	return errors.Join(errs...)
+}
+
+func (m *shardManagerImpl) TakeOver(ctx context.Context, sessions map[int]gateway.Session) error {
+	m.config.Logger.Debug("taking over shards", slog.Int("shards", len(sessions)))
+
+	var (
+		wg     sync.WaitGroup
+		errsMu sync.Mutex
+		errs   []error
+	)
+	for shardID, session := range sessions {
+		if session.ShardCount != m.config.ShardCount {
+			errsMu.Lock()
+			errs = append(errs, fmt.Errorf("shard %d: session shard count %d does not match %d", shardID, session.ShardCount, m.config.ShardCount))
+			errsMu.Unlock()
+			continue
+		}
+
+		// resuming does not count against the identify rate limit, so we don't wait for the shard bucket here
+		shard := m.config.GatewayCreateFunc(m.token, m.eventHandler(m.generation.Load(), nil), m.closeHandler, append(m.config.GatewayConfigOpts, gateway.WithShardID(shardID), gateway.WithShardCount(m.config.ShardCount), gateway.WithSession(session))...)
+		m.shardsMu.Lock()
+		_, managed := m.config.ShardIDs[shardID]
+		m.config.ShardIDs[shardID] = struct{}{}
+		m.shards[shardID] = shard
+		m.shardsMu.Unlock()
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			err := shard.Open(ctx)
+			if err == nil {
+				err = waitReady(ctx, shard)
+			}
+			if err == nil {
+				return
+			}
+			errsMu.Lock()
+			errs = append(errs, fmt.Errorf("shard %d: %w", shard.ShardID(), err))
+			errsMu.Unlock()
+
+			// the session is handed back, so it must stay resumable
+			closeCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			shard.CloseWithCode(closeCtx, websocket.CloseServiceRestart, "Take over failed")
+			cancel()
+
+			m.shardsMu.Lock()
+			if m.shards[shard.ShardID()] == shard {
+				delete(m.shards, shard.ShardID())
+				if !managed {
+					delete(m.config.ShardIDs, shard.ShardID())
+				}
+			}
+			m.shardsMu.Unlock()
+		}()
+	}
+	wg.Wait()
+	return errors.Join(errs...)
+}
+
+// waitReady blocks until the given shard is ready or the context is done.
+func waitReady(ctx context.Context, shard gateway.Gateway) error {
+	ticker := time.NewTicker(100 * time.Millisecond)
+	defer ticker.Stop()
+	for shard.Status() != gateway.StatusReady {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+	return nil
+}
+
 func (m *shardManagerImpl) ShardByGuildID(guildId snowflake.ID) gateway.Gateway {
+	m.shardsMu.Lock()
 	shardCount := m.config.ShardCount
+	m.shardsMu.Unlock()
 	var shard gateway.Gateway
 	for shard == nil || shardCount != 0 {
 		shard = m.Shard(ShardIDByGuild(guildId, shardCount))
@@ -191,5 +421,5 @@ func (m *shardManagerImpl) Shards() map[int]gateway.Gateway {
 	for shardID, shard := range m.shards {
 		shards[shardID] = shard
 	}
-	return m.shards
+	return shards
 }
@@ -0,0 +1,291 @@
+package sharding
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/disgoorg/snowflake/v2"
+	"github.com/gorilla/websocket"
+
+	"github.com/disgoorg/disgo/gateway"
+)
+
+// eventHandler returns the gateway.EventHandlerFunc for shards of the given generation.
+// Only events of the current generation are dispatched, events of other generations are passed to the readyTracker if there is one.
+func (m *shardManagerImpl) eventHandler(generation uint64, tracker *readyTracker) gateway.EventHandlerFunc {
+	return func(gatewayEventType gateway.EventType, sequenceNumber int, shardID int, event gateway.EventData) {
+		if m.generation.Load() != generation {
+			if tracker != nil {
+				tracker.handle(shardID, event)
+			}
+			return
+		}
+		m.eventHandlerFunc(gatewayEventType, sequenceNumber, shardID, event)
+	}
+}
+
+func (m *shardManagerImpl) Reshard(ctx context.Context, newShardCount int) error {
+	m.reshardMu.Lock()
+	defer m.reshardMu.Unlock()
+
+	openCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	m.shardsMu.Lock()
+	oldShardCount := m.config.ShardCount
+	newShardIDs, err := reshardShardIDs(m.config.ShardIDs, oldShardCount, newShardCount)
+	if err == nil {
+		// Close aborts resharding with this
+		m.reshardCancel = cancel
+	}
+	m.shardsMu.Unlock()
+	if err != nil {
+		return err
+	}
+	defer func() {
+		m.shardsMu.Lock()
+		m.reshardCancel = nil
+		m.shardsMu.Unlock()
+	}()
+	m.config.Logger.Debug("resharding", slog.Int("old_shard_count", oldShardCount), slog.Int("new_shard_count", newShardCount), slog.String("new_shard_ids", fmt.Sprint(newShardIDs)))
+
+	generation := m.generation.Load() + 1
+	tracker := newReadyTracker(newShardIDs, m.config.GuildReadyTimeout)
+	closeHandler := func(shard gateway.Gateway, err error) {
+		if m.generation.Load() != generation {
+			tracker.fail(fmt.Errorf("shard %d closed: %w", shard.ShardID(), err))
+			return
+		}
+		m.closeHandler(shard, err)
+	}
+
+	var (
+		wg          sync.WaitGroup
+		newShardsMu sync.Mutex
+		newShards   = make(map[int]gateway.Gateway, len(newShardIDs))
+	)
+	for _, shardID := range newShardIDs {
+		wg.Add(1)
+		go func(shardID int) {
+			defer wg.Done()
+			if err := m.config.RateLimiter.WaitBucket(openCtx, shardID); err != nil {
+				tracker.fail(fmt.Errorf("failed to wait shard bucket of shard %d: %w", shardID, err))
+				return
+			}
+			defer m.config.RateLimiter.UnlockBucket(shardID)
+
+			shard := m.config.GatewayCreateFunc(m.token, m.eventHandler(generation, tracker), closeHandler, append(m.config.GatewayConfigOpts, gateway.WithShardID(shardID), gateway.WithShardCount(newShardCount))...)
+			tracker.setIntents(shardID, shard.Intents())
+			newShardsMu.Lock()
+			newShards[shardID] = shard
+			newShardsMu.Unlock()
+			if err := shard.Open(openCtx); err != nil {
+				tracker.fail(fmt.Errorf("failed to open shard %d: %w", shardID, err))
+			}
+		}(shardID)
+	}
+
+	select {
+	case <-openCtx.Done():
+		err = openCtx.Err()
+	case <-tracker.done:
+		err = tracker.err
+	}
+	// stop shards still opening, they are closed below if resharding failed
+	cancel()
+	wg.Wait()
+	if err != nil {
+		m.config.Logger.Error("new shards did not get ready", slog.Any("err", err))
+		closeShards(context.Background(), newShards)
+		return fmt.Errorf("failed to reshard: %w", err)
+	}
+
+	m.shardsMu.Lock()
+	oldShards := m.shards
+	m.shards = newShards
+	m.config.ShardCount = newShardCount
+	m.config.ShardIDs = make(map[int]struct{}, len(newShardIDs))
+	for _, shardID := range newShardIDs {
+		m.config.ShardIDs[shardID] = struct{}{}
+	}
+	m.generation.Store(generation)
+	m.shardsMu.Unlock()
+	m.config.Logger.Debug("switched to new shards", slog.Int("shard_count", newShardCount))
+
+	closeShards(ctx, oldShards)
+	return nil
+}
+
+// reshardShardIDs returns the shard IDs which cover the same guilds with the new shard count as the given shard IDs with the old shard count.
+func reshardShardIDs(shardIDs map[int]struct{}, oldShardCount int, newShardCount int) ([]int, error) {
+	if newShardCount <= 0 {
+		return nil, fmt.Errorf("invalid shard count %d", newShardCount)
+	}
+
+	var newShardIDs []int
+	if len(shardIDs) == oldShardCount {
+		for shardID := 0; shardID < newShardCount; shardID++ {
+			newShardIDs = append(newShardIDs, shardID)
+		}
+		return newShardIDs, nil
+	}
+
+	// a guild on new shard i was on old shard i % oldShardCount as long as the new shard count is a multiple of the old one
+	if oldShardCount == 0 || newShardCount%oldShardCount != 0 {
+		return nil, fmt.Errorf("new shard count %d must be a multiple of %d when only managing some shards", newShardCount, oldShardCount)
+	}
+	for shardID := 0; shardID < newShardCount; shardID++ {
+		if _, ok := shardIDs[shardID%oldShardCount]; ok {
+			newShardIDs = append(newShardIDs, shardID)
+		}
+	}
+	return newShardIDs, nil
+}
+
+// closeShards closes the given shards without saving their sessions to the gateway.SessionStore.
+// Their sessions belong to a shard count which is not used anymore and would replace the sessions of the shards with the same IDs.
+func closeShards(ctx context.Context, shards map[int]gateway.Gateway) {
+	var wg sync.WaitGroup
+	for _, shard := range shards {
+		wg.Add(1)
+		go func(shard gateway.Gateway) {
+			defer wg.Done()
+			shard.CloseWithCode(ctx, websocket.CloseNormalClosure, "Resharding")
+		}(shard)
+	}
+	wg.Wait()
+}
+
+// readyTracker keeps track of which shards are ready and which guilds they still have to receive.
+type readyTracker struct {
+	mu                sync.Mutex
+	guildReadyTimeout time.Duration
+	pendingShards     map[int]struct{}
+	// pendingGuilds are the guilds each shard still has to receive
+	pendingGuilds map[int]map[snowflake.ID]struct{}
+	// guildTimers treat the pending guilds of a shard as received once it did not receive a guild for guildReadyTimeout
+	guildTimers map[int]*time.Timer
+	// withoutGuilds are the shards without gateway.IntentGuilds, which never receive their guilds
+	withoutGuilds map[int]struct{}
+	done          chan struct{}
+	closed        bool
+	// err is set if a shard failed before all shards were ready. It must only be read after done is closed
+	err error
+}
+
+func newReadyTracker(shardIDs []int, guildReadyTimeout time.Duration) *readyTracker {
+	t := &readyTracker{
+		guildReadyTimeout: guildReadyTimeout,
+		pendingShards:     make(map[int]struct{}, len(shardIDs)),
+		pendingGuilds:     map[int]map[snowflake.ID]struct{}{},
+		guildTimers:       map[int]*time.Timer{},
+		withoutGuilds:     map[int]struct{}{},
+		done:              make(chan struct{}),
+	}
+	for _, shardID := range shardIDs {
+		t.pendingShards[shardID] = struct{}{}
+	}
+	t.checkDone()
+	return t
+}
+
+// setIntents sets the intents of the given shard. It must be called before the shard is opened.
+func (t *readyTracker) setIntents(shardID int, intents gateway.Intents) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if !intents.Has(gateway.IntentGuilds) {
+		t.withoutGuilds[shardID] = struct{}{}
+	}
+}
+
+func (t *readyTracker) handle(shardID int, event gateway.EventData) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	switch e := event.(type) {
+	case gateway.EventReady:
+		delete(t.pendingShards, shardID)
+		if _, ok := t.withoutGuilds[shardID]; ok || len(e.Guilds) == 0 {
+			break
+		}
+		guilds := make(map[snowflake.ID]struct{}, len(e.Guilds))
+		for _, guild := range e.Guilds {
+			guilds[guild.ID] = struct{}{}
+		}
+		t.pendingGuilds[shardID] = guilds
+		t.resetGuildTimer(shardID)
+	case gateway.EventGuildCreate:
+		t.guildReceived(shardID, e.ID)
+	case gateway.EventGuildDelete:
+		t.guildReceived(shardID, e.ID)
+	default:
+		return
+	}
+	t.checkDone()
+}
+
+// guildReceived removes the guild from the pending guilds of the shard. It must be called with mu held.
+func (t *readyTracker) guildReceived(shardID int, guildID snowflake.ID) {
+	guilds, ok := t.pendingGuilds[shardID]
+	if !ok {
+		return
+	}
+	delete(guilds, guildID)
+	if len(guilds) > 0 {
+		t.resetGuildTimer(shardID)
+		return
+	}
+	delete(t.pendingGuilds, shardID)
+	if timer, ok := t.guildTimers[shardID]; ok {
+		timer.Stop()
+		delete(t.guildTimers, shardID)
+	}
+}
+
+// resetGuildTimer restarts the guild ready timeout of the shard. It must be called with mu held.
+func (t *readyTracker) resetGuildTimer(shardID int) {
+	if timer, ok := t.guildTimers[shardID]; ok {
+		timer.Reset(t.guildReadyTimeout)
+		return
+	}
+	t.guildTimers[shardID] = time.AfterFunc(t.guildReadyTimeout, func() {
+		t.mu.Lock()
+		defer t.mu.Unlock()
+		// the remaining guilds are unavailable, they are received once they become available again
+		delete(t.pendingGuilds, shardID)
+		delete(t.guildTimers, shardID)
+		t.checkDone()
+	})
+}
+
+// fail closes done with the given error, unless done is already closed.
+func (t *readyTracker) fail(err error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.closed {
+		return
+	}
+	t.err = err
+	t.close()
+}
+
+// checkDone closes done once all shards are ready and all guilds were received. It must be called with mu held or before t is shared.
+func (t *readyTracker) checkDone() {
+	if t.closed || len(t.pendingShards) > 0 || len(t.pendingGuilds) > 0 {
+		return
+	}
+	t.close()
+}
+
+// close stops all guild timers and closes done. It must be called with mu held.
+func (t *readyTracker) close() {
+	for shardID, timer := range t.guildTimers {
+		timer.Stop()
+		delete(t.guildTimers, shardID)
+	}
+	t.closed = true
+	close(t.done)
+}
@@ -0,0 +1,147 @@
+package sharding
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/disgoorg/snowflake/v2"
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/disgoorg/disgo/discord"
+	"github.com/disgoorg/disgo/gateway"
+)
+
+func TestReshardShardIDs(t *testing.T) {
+	shardIDs, err := reshardShardIDs(map[int]struct{}{0: {}, 1: {}}, 2, 3)
+	assert.NoError(t, err)
+	assert.Equal(t, []int{0, 1, 2}, shardIDs)
+
+	// only shard 1 of 2 is managed
+	shardIDs, err = reshardShardIDs(map[int]struct{}{1: {}}, 2, 6)
+	assert.NoError(t, err)
+	assert.Equal(t, []int{1, 3, 5}, shardIDs)
+	for _, shardID := range shardIDs {
+		guildID := snowflake.ID(uint64(shardID) << 22)
+		assert.Equal(t, 1, ShardIDByGuild(guildID, 2))
+	}
+
+	_, err = reshardShardIDs(map[int]struct{}{1: {}}, 2, 5)
+	assert.Error(t, err)
+
+	_, err = reshardShardIDs(map[int]struct{}{0: {}}, 1, 0)
+	assert.Error(t, err)
+}
+
+func TestReadyTracker(t *testing.T) {
+	tracker := newReadyTracker([]int{0, 1}, time.Minute)
+
+	tracker.handle(0, gateway.EventReady{Guilds: []discord.UnavailableGuild{{ID: 1}, {ID: 2}}})
+	tracker.handle(1, gateway.EventReady{})
+	tracker.handle(0, gateway.EventGuildCreate{GatewayGuild: gatewayGuild(1)})
+	assertNotDone(t, tracker)
+
+	tracker.handle(0, gateway.EventGuildDelete{GatewayGuild: gatewayGuild(2)})
+	select {
+	case <-tracker.done:
+	default:
+		t.Fatal("tracker should be done")
+	}
+}
+
+func TestReadyTracker_WithoutGuildsIntent(t *testing.T) {
+	tracker := newReadyTracker([]int{0}, time.Minute)
+	tracker.setIntents(0, gateway.IntentGuildMessages)
+
+	// no GUILD_CREATE is sent without the guilds intent
+	tracker.handle(0, gateway.EventReady{Guilds: []discord.UnavailableGuild{{ID: 1}}})
+	select {
+	case <-tracker.done:
+	default:
+		t.Fatal("tracker should be done")
+	}
+}
+
+func TestReadyTracker_GuildReadyTimeout(t *testing.T) {
+	tracker := newReadyTracker([]int{0}, 50*time.Millisecond)
+	tracker.setIntents(0, gateway.IntentGuilds)
+
+	tracker.handle(0, gateway.EventReady{Guilds: []discord.UnavailableGuild{{ID: 1}, {ID: 2}}})
+	tracker.handle(0, gateway.EventGuildCreate{GatewayGuild: gatewayGuild(1)})
+	assertNotDone(t, tracker)
+
+	// guild 2 stays unavailable
+	select {
+	case <-tracker.done:
+		assert.NoError(t, tracker.err)
+	case <-time.After(time.Second):
+		t.Fatal("tracker should be done after the guild ready timeout")
+	}
+}
+
+func assertNotDone(t *testing.T, tracker *readyTracker) {
+	select {
+	case <-tracker.done:
+		t.Fatal("tracker should not be done")
+	default:
+	}
+}
+
+func gatewayGuild(id snowflake.ID) discord.GatewayGuild {
+	return discord.GatewayGuild{RestGuild: discord.RestGuild{Guild: discord.Guild{ID: id}}}
+}
+
+func TestReshard_OpenFailure(t *testing.T) {
+	var closed atomic.Int32
+	m := New("", nil,
+		WithShardIDs(0),
+		WithShardCount(1),
+		WithRateLimiter(NewNoopRateLimiter()),
+		WithGatewayCreateFunc(func(_ string, _ gateway.EventHandlerFunc, _ gateway.CloseHandlerFunc, opts ...gateway.ConfigOpt) gateway.Gateway {
+			config := gateway.DefaultConfig()
+			config.Apply(opts)
+			return &failingGateway{shardID: config.ShardID, closed: &closed}
+		}),
+	)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	// the failed shard aborts resharding instead of waiting for the context
+	err := m.(ShardResharder).Reshard(ctx, 2)
+	assert.ErrorIs(t, err, errOpenFailed)
+	assert.NoError(t, ctx.Err())
+	assert.Equal(t, int32(2), closed.Load())
+	assert.Equal(t, 1, m.(*shardManagerImpl).config.ShardCount)
+}
+
+var errOpenFailed = errors.New("open failed")
+
+type failingGateway struct {
+	gateway.Gateway
+	shardID int
+	closed  *atomic.Int32
+}
+
+func (g *failingGateway) ShardID() int {
+	return g.shardID
+}
+
+func (g *failingGateway) Intents() gateway.Intents {
+	return gateway.IntentGuilds
+}
+
+func (g *failingGateway) Open(_ context.Context) error {
+	if g.shardID == 1 {
+		return errOpenFailed
+	}
+	return nil
+}
+
+func (g *failingGateway) CloseWithCode(_ context.Context, code int, _ string) {
+	if code == websocket.CloseNormalClosure {
+		g.closed.Add(1)
+	}
+}
@@ -0,0 +1,51 @@
+package sharding
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/disgoorg/disgo/discord"
+	"github.com/disgoorg/disgo/gateway"
+	"github.com/disgoorg/disgo/rest"
+)
+
+func TestOpen_SessionStartLimit(t *testing.T) {
+	var event *gateway.EventSessionStartLimit
+	m := New("", func(_ gateway.EventType, _ int, _ int, data gateway.EventData) {
+		if e, ok := data.(gateway.EventSessionStartLimit); ok {
+			event = &e
+		}
+	},
+		WithRest(&testRestGateway{gatewayBot: discord.GatewayBot{
+			Shards:            2,
+			SessionStartLimit: discord.SessionStartLimit{Total: 1000, Remaining: 1, MaxConcurrency: 1},
+		}}),
+		WithRateLimiter(NewNoopRateLimiter()),
+		WithGatewayCreateFunc(func(_ string, _ gateway.EventHandlerFunc, _ gateway.CloseHandlerFunc, _ ...gateway.ConfigOpt) gateway.Gateway {
+			t.Fatal("no shard should be opened")
+			return nil
+		}),
+	)
+
+	// not enough session starts remain for both shards
+	m.Open(context.Background())
+	assert.Empty(t, m.Shards())
+	if assert.NotNil(t, event) {
+		assert.Equal(t, 1, event.Remaining)
+		assert.Equal(t, 2, event.ShardsToOpen)
+	}
+}
+
+type testRestGateway struct {
+	gatewayBot discord.GatewayBot
+}
+
+func (g *testRestGateway) GetGateway(_ ...rest.RequestOpt) (*discord.Gateway, error) {
+	return &discord.Gateway{URL: g.gatewayBot.URL}, nil
+}
+
+func (g *testRestGateway) GetGatewayBot(_ ...rest.RequestOpt) (*discord.GatewayBot, error) {
+	return &g.gatewayBot, nil
+}
@@ -2,11 +2,15 @@ package sharding
 
 import (
 	"context"
+	"time"
 )
 
 // MaxConcurrency is the default number of shards that can log in at the same time.
 const MaxConcurrency = 1
 
+// IdentifyWindow is the time a max concurrency bucket is blocked after a shard logged in.
+const IdentifyWindow = 5 * time.Second
+
 // RateLimiter limits how many shards can log in to Discord at the same time.
 type RateLimiter interface {
 	// Close gracefully closes the RateLimiter.
@@ -21,6 +25,13 @@ type RateLimiter interface {
 	UnlockBucket(shardID int)
 }
 
+// ConcurrencyRateLimiter is implemented by RateLimiters whose max concurrency can be changed after creation.
+// The ShardManager applies the max concurrency fetched from Discord to its RateLimiter if it implements ConcurrencyRateLimiter.
+type ConcurrencyRateLimiter interface {
+	// SetMaxConcurrency sets the max concurrency used to build the buckets.
+	SetMaxConcurrency(maxConcurrency int)
+}
+
 // ShardMaxConcurrencyKey returns the bucket the given shardID with maxConcurrency belongs to.
 func ShardMaxConcurrencyKey(shardID int, maxConcurrency int) int {
 	return shardID % maxConcurrency
@@ -9,7 +9,10 @@ import (
 	"github.com/sasha-s/go-csync"
 )
 
-var _ RateLimiter = (*rateLimiterImpl)(nil)
+var (
+	_ RateLimiter            = (*rateLimiterImpl)(nil)
+	_ ConcurrencyRateLimiter = (*rateLimiterImpl)(nil)
+)
 
 // NewRateLimiter creates a new default RateLimiter with the given RateLimiterConfigOpt(s).
 func NewRateLimiter(opts ...RateLimiterConfigOpt) RateLimiter {
@@ -47,6 +50,17 @@ func (r *rateLimiterImpl) Close(ctx context.Context) {
 	}
 }
 
+func (r *rateLimiterImpl) SetMaxConcurrency(maxConcurrency int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if maxConcurrency == r.config.MaxConcurrency {
+		return
+	}
+	r.config.MaxConcurrency = maxConcurrency
+	// the keys of the buckets changed
+	r.buckets = map[int]*bucket{}
+}
+
 func (r *rateLimiterImpl) getBucket(shardID int, create bool) *bucket {
 	r.config.Logger.Debug("locking shard rate limiter")
 	r.mu.Lock()
@@ -108,7 +122,7 @@ func (r *rateLimiterImpl) UnlockBucket(shardID int) {
 		b.mu.Unlock()
 	}()
 
-	b.Reset = time.Now().Add(5 * time.Second)
+	b.Reset = time.Now().Add(IdentifyWindow)
 }
 
 type bucket struct {
@@ -0,0 +1,111 @@
+// Package shardingredis provides a sharding.RateLimiter which shares the max concurrency buckets between multiple processes via redis.
+package shardingredis
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/disgoorg/disgo/internal/insecurerandstr"
+	"github.com/disgoorg/disgo/sharding"
+)
+
+var (
+	_ sharding.RateLimiter            = (*rateLimiterImpl)(nil)
+	_ sharding.ConcurrencyRateLimiter = (*rateLimiterImpl)(nil)
+)
+
+// unlockScript keeps the bucket locked for the identify window if we still hold it.
+//
+// KEYS[1] is the bucket key.
+// ARGV[1] is the lock owner, ARGV[2] is the identify window in milliseconds.
+var unlockScript = redis.NewScript(`
+if redis.call('GET', KEYS[1]) == ARGV[1] then
+	return redis.call('PEXPIRE', KEYS[1], ARGV[2])
+end
+return 0
+`)
+
+// NewRateLimiter returns a new sharding.RateLimiter which stores its buckets in redis using the given redis.UniversalClient & ConfigOpt(s).
+// All processes sharing one bot token should use the same redis, key prefix and max concurrency.
+// The redis.UniversalClient is not closed by the RateLimiter.
+func NewRateLimiter(client redis.UniversalClient, opts ...ConfigOpt) sharding.RateLimiter {
+	config := DefaultConfig()
+	config.Apply(opts)
+	config.Logger = config.Logger.With(slog.String("name", "sharding_redis_rate_limiter"))
+
+	l := &rateLimiterImpl{
+		client: client,
+		owner:  insecurerandstr.RandStr(16),
+		config: *config,
+	}
+	l.maxConcurrency.Store(int64(config.MaxConcurrency))
+	return l
+}
+
+type rateLimiterImpl struct {
+	client redis.UniversalClient
+	// owner identifies the locks held by this RateLimiter
+	owner  string
+	config Config
+	// maxConcurrency is Config.MaxConcurrency, which can be changed by SetMaxConcurrency
+	maxConcurrency atomic.Int64
+}
+
+// Close is a no-op as locked buckets expire on their own.
+func (l *rateLimiterImpl) Close(_ context.Context) {}
+
+func (l *rateLimiterImpl) SetMaxConcurrency(maxConcurrency int) {
+	l.maxConcurrency.Store(int64(maxConcurrency))
+}
+
+func (l *rateLimiterImpl) key(shardID int) string {
+	return l.config.Prefix + "bucket:" + strconv.Itoa(sharding.ShardMaxConcurrencyKey(shardID, int(l.maxConcurrency.Load())))
+}
+
+func (l *rateLimiterImpl) WaitBucket(ctx context.Context, shardID int) error {
+	key := l.key(shardID)
+	for {
+		ok, err := l.client.SetNX(ctx, key, l.owner, l.config.LockTTL).Result()
+		if err != nil {
+			return fmt.Errorf("failed to lock shard bucket: %w", err)
+		}
+		if ok {
+			l.config.Logger.Debug("locked shard bucket", slog.String("key", key), slog.Int("shard_id", shardID))
+			return nil
+		}
+
+		wait := l.config.RetryInterval
+		ttl, err := l.client.PTTL(ctx, key).Result()
+		if err != nil && !errors.Is(err, redis.Nil) {
+			return fmt.Errorf("failed to get shard bucket ttl: %w", err)
+		}
+		if ttl >= 0 && ttl < wait {
+			wait = ttl
+		}
+		l.config.Logger.Debug("waiting for shard bucket", slog.String("key", key), slog.Duration("wait", wait))
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+func (l *rateLimiterImpl) UnlockBucket(shardID int) {
+	key := l.key(shardID)
+	if err := unlockScript.Run(context.Background(), l.client, []string{key}, l.owner, sharding.IdentifyWindow.Milliseconds()).Err(); err != nil {
+		l.config.Logger.Error("failed to unlock shard bucket", slog.Any("err", err), slog.String("key", key))
+		return
+	}
+	l.config.Logger.Debug("unlocked shard bucket", slog.String("key", key), slog.Int("shard_id", shardID))
+}
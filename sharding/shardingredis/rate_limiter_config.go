@@ -0,0 +1,78 @@
+package shardingredis
+
+import (
+	"log/slog"
+	"time"
+
+	"github.com/disgoorg/disgo/sharding"
+)
+
+// DefaultConfig returns a Config with sensible defaults.
+func DefaultConfig() *Config {
+	return &Config{
+		Logger:         slog.Default(),
+		MaxConcurrency: sharding.MaxConcurrency,
+		Prefix:         "disgo:sharding:",
+		LockTTL:        30 * time.Second,
+		RetryInterval:  250 * time.Millisecond,
+	}
+}
+
+// Config lets you configure your RateLimiter instance.
+type Config struct {
+	// Logger is the logger of the RateLimiter. Defaults to slog.Default().
+	Logger *slog.Logger
+	// MaxConcurrency is the max concurrency of the bot as returned by Discord. Defaults to sharding.MaxConcurrency.
+	MaxConcurrency int
+	// Prefix is prepended to all redis keys. Use a different prefix per bot token. Defaults to "disgo:sharding:".
+	Prefix string
+	// LockTTL is after how long a locked bucket is released if the process holding it dies before unlocking it. Defaults to 30 seconds.
+	LockTTL time.Duration
+	// RetryInterval is the maximum time to wait before trying to lock a bucket again. Defaults to 250 milliseconds.
+	RetryInterval time.Duration
+}
+
+// ConfigOpt can be used to supply optional parameters to NewRateLimiter.
+type ConfigOpt func(config *Config)
+
+// Apply applies the given ConfigOpt(s) to the Config.
+func (c *Config) Apply(opts []ConfigOpt) {
+	for _, opt := range opts {
+		opt(c)
+	}
+}
+
+// WithLogger applies a custom logger to the RateLimiter.
+func WithLogger(logger *slog.Logger) ConfigOpt {
+	return func(config *Config) {
+		config.Logger = logger
+	}
+}
+
+// WithMaxConcurrency sets the max concurrency used to build the buckets shared by all processes.
+func WithMaxConcurrency(maxConcurrency int) ConfigOpt {
+	return func(config *Config) {
+		config.MaxConcurrency = maxConcurrency
+	}
+}
+
+// WithPrefix sets the prefix of all redis keys used by the RateLimiter.
+func WithPrefix(prefix string) ConfigOpt {
+	return func(config *Config) {
+		config.Prefix = prefix
+	}
+}
+
+// WithLockTTL sets after how long a locked bucket is released if the process holding it dies.
+func WithLockTTL(lockTTL time.Duration) ConfigOpt {
+	return func(config *Config) {
+		config.LockTTL = lockTTL
+	}
+}
+
+// WithRetryInterval sets the maximum time to wait before trying to lock a bucket again.
+func WithRetryInterval(retryInterval time.Duration) ConfigOpt {
+	return func(config *Config) {
+		config.RetryInterval = retryInterval
+	}
+}
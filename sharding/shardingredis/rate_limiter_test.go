@@ -0,0 +1,46 @@
+package shardingredis
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/disgoorg/disgo/sharding"
+)
+
+func TestRateLimiter(t *testing.T) {
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	t.Cleanup(func() {
+		_ = client.Close()
+	})
+
+	// two processes sharing the same buckets
+	limiter1 := NewRateLimiter(client, WithMaxConcurrency(2))
+	limiter2 := NewRateLimiter(client, WithMaxConcurrency(2))
+
+	assert.NoError(t, limiter1.WaitBucket(context.Background(), 0))
+
+	// shard 2 shares the bucket with shard 0
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	assert.ErrorIs(t, limiter2.WaitBucket(ctx, 2), context.DeadlineExceeded)
+
+	// shard 1 is in another bucket
+	assert.NoError(t, limiter2.WaitBucket(context.Background(), 1))
+
+	// other processes can't unlock our bucket
+	limiter2.UnlockBucket(0)
+	assert.Equal(t, 30*time.Second, server.TTL("disgo:sharding:bucket:0"))
+
+	// the bucket stays locked for the identify window after unlocking
+	limiter1.UnlockBucket(0)
+	assert.Equal(t, sharding.IdentifyWindow, server.TTL("disgo:sharding:bucket:0"))
+
+	server.FastForward(sharding.IdentifyWindow)
+	assert.NoError(t, limiter2.WaitBucket(context.Background(), 2))
+}